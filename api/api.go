@@ -0,0 +1,15 @@
+// Package api embeds the documents describing the API so they can be served by
+// the server binary itself.
+package api
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI 3 document of the HTTP API.
+//
+//go:embed openapi.json
+var OpenAPI []byte
+
+// AsyncAPI is the AsyncAPI document of the run websocket.
+//
+//go:embed asyncapi.json
+var AsyncAPI []byte
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/pkg/client"
+	"gopkg.in/yaml.v3"
+)
+
+// batchMaxOperations is the most operations the server takes in a single
+// section batch.
+const batchMaxOperations = 100
+
+// agenda is the file a presentation is exported to and imported from, as in
+//
+//	name: Quarterly review
+//	sections:
+//	  - name: Introduction
+//	    duration: 5m
+//	  - name: Questions
+//	    duration: 10m
+type agenda struct {
+	Name     string          `json:"name" yaml:"name"`
+	Sections []agendaSection `json:"sections" yaml:"sections"`
+}
+
+type agendaSection struct {
+	Name     string   `json:"name" yaml:"name"`
+	Duration duration `json:"duration" yaml:"duration"`
+}
+
+// duration is written as in 1m30s instead of nanoseconds.
+type duration time.Duration
+
+func (d duration) MarshalText() ([]byte, error) {
+	return []byte(formatDuration(time.Duration(d))), nil
+}
+
+func (d *duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = duration(parsed)
+	return nil
+}
+
+// agendaFormat is the format of a file, JSON for .json files and YAML
+// otherwise.
+func agendaFormat(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return "json"
+	}
+
+	return "yaml"
+}
+
+func exportCommand(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("export", "<presentation>")
+	file := flags.String("f", "-", "file to export to, - writes to the standard output")
+	format := flags.String("format", "", "yaml or json, defaults to the extension of the file")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if *format == "" {
+		*format = agendaFormat(*file)
+	}
+	if *format != "yaml" && *format != "json" {
+		return usageError{fmt.Sprintf("format must be yaml or json, got %q", *format)}
+	}
+
+	presentation, err := a.client.GetPresentationWithSections(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	exported := agenda{
+		Name:     presentation.Name,
+		Sections: make([]agendaSection, 0, len(presentation.Sections)),
+	}
+	for _, section := range presentation.Sections {
+		exported.Sections = append(exported.Sections, agendaSection{
+			Name:     section.Name,
+			Duration: duration(section.Duration),
+		})
+	}
+
+	encoded, err := encodeAs(*format, exported)
+	if err != nil {
+		return err
+	}
+
+	if *file == "-" {
+		_, err := a.out.Write(encoded)
+		return err
+	}
+
+	if err := os.WriteFile(*file, encoded, 0o644); err != nil {
+		return err
+	}
+
+	a.notice("presentation %d exported to %s", ID, *file)
+	return nil
+}
+
+func importCommand(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("import", "<file>")
+	into := flags.Int64("into", 0, "presentation to replace the name and sections of, a new one is created by default")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	imported, err := readAgenda(args[0])
+	if err != nil {
+		return err
+	}
+
+	var creates []client.BatchOperation
+	for _, section := range imported.Sections {
+		d := time.Duration(section.Duration)
+		creates = append(creates, client.BatchOperation{
+			Op:       client.BatchCreate,
+			Name:     &section.Name,
+			Duration: &d,
+		})
+	}
+
+	var ID int64
+	if *into == 0 {
+		ID, err = importNew(ctx, a, imported, creates)
+	} else {
+		ID, err = importInto(ctx, a, *into, imported, creates)
+	}
+	if err != nil {
+		return err
+	}
+
+	presentation, err := a.client.GetPresentationWithSections(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	summary := presentationsTable(presentation.Presentation)
+	summary.rows[0][2] = formatDuration(presentation.Totals.Duration)
+
+	return a.render(presentation, summary, sectionsTable(presentation.Sections...))
+}
+
+// importNew creates a presentation with the sections of imported. The
+// presentation is deleted again when its sections can't be created, so a
+// failed import leaves nothing behind.
+func importNew(ctx context.Context, a app, imported agenda, creates []client.BatchOperation) (int64, error) {
+	if err := checkBatchSize(creates); err != nil {
+		return 0, err
+	}
+
+	presentation, err := a.client.CreatePresentation(ctx, client.PresentationInput{Name: imported.Name})
+	if err != nil {
+		return 0, err
+	}
+	if len(creates) == 0 {
+		return presentation.ID, nil
+	}
+
+	if _, err := a.client.BatchSections(ctx, presentation.ID, creates); err != nil {
+		if deleteErr := a.client.DeletePresentation(ctx, presentation.ID); deleteErr != nil {
+			return 0, fmt.Errorf(
+				"%w\npresentation %d was created without sections and could not be deleted: %v",
+				err,
+				presentation.ID,
+				deleteErr,
+			)
+		}
+
+		return 0, err
+	}
+
+	return presentation.ID, nil
+}
+
+// importInto replaces the sections and then the name of presentation ID with
+// those of imported. Sections are replaced in a single batch, which is applied
+// whole or not at all, and the presentation is only renamed once they are.
+func importInto(
+	ctx context.Context,
+	a app,
+	ID int64,
+	imported agenda,
+	creates []client.BatchOperation,
+) (int64, error) {
+	existing, err := a.client.GetPresentationWithSections(ctx, ID)
+	if err != nil {
+		return 0, err
+	}
+
+	var operations []client.BatchOperation
+	for _, section := range existing.Sections {
+		operations = append(operations, client.BatchOperation{Op: client.BatchDelete, ID: &section.ID})
+	}
+	operations = append(operations, creates...)
+	if err := checkBatchSize(operations); err != nil {
+		return 0, err
+	}
+
+	if len(operations) > 0 {
+		if _, err := a.client.BatchSections(ctx, ID, operations); err != nil {
+			return 0, err
+		}
+	}
+
+	if existing.Name != imported.Name {
+		// sections are not part of the version of a presentation, it still
+		// matches the one read before replacing them
+		patch := client.PresentationPatch{Name: &imported.Name}
+		if _, err := a.client.PatchPresentation(ctx, ID, patch, client.IfMatch(existing.Version)); err != nil {
+			return 0, fmt.Errorf(
+				"%w\nthe sections of presentation %d were replaced but it is still named %q",
+				err,
+				ID,
+				existing.Name,
+			)
+		}
+	}
+
+	return ID, nil
+}
+
+// checkBatchSize fails when operations don't fit in a single batch, imports
+// split across batches could be left half applied.
+func checkBatchSize(operations []client.BatchOperation) error {
+	if len(operations) <= batchMaxOperations {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"importing needs %d section operations, more than the %d that can be applied at once",
+		len(operations),
+		batchMaxOperations,
+	)
+}
+
+// readAgenda reads the agenda at path, - reads it from the standard input.
+// JSON being valid YAML, both formats are read alike.
+func readAgenda(path string) (agenda, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return agenda{}, err
+		}
+		defer f.Close()
+
+		r = f
+	}
+
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	var read agenda
+	if err := decoder.Decode(&read); err != nil {
+		if errors.Is(err, io.EOF) {
+			return agenda{}, fmt.Errorf("%s is empty", path)
+		}
+
+		return agenda{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return read, nil
+}
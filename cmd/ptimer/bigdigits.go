@@ -0,0 +1,44 @@
+package main
+
+import "strings"
+
+// glyphs are the characters of the large countdown, # are filled cells.
+var glyphs = map[rune][5]string{
+	'0': {"#####", "#   #", "#   #", "#   #", "#####"},
+	'1': {"  #  ", " ##  ", "  #  ", "  #  ", " ### "},
+	'2': {"#####", "    #", "#####", "#    ", "#####"},
+	'3': {"#####", "    #", " ####", "    #", "#####"},
+	'4': {"#   #", "#   #", "#####", "    #", "    #"},
+	'5': {"#####", "#    ", "#####", "    #", "#####"},
+	'6': {"#####", "#    ", "#####", "#   #", "#####"},
+	'7': {"#####", "    #", "   # ", "  #  ", "  #  "},
+	'8': {"#####", "#   #", "#####", "#   #", "#####"},
+	'9': {"#####", "#   #", "#####", "    #", "#####"},
+	':': {"   ", " # ", "   ", " # ", "   "},
+	'+': {"     ", "  #  ", " ### ", "  #  ", "     "},
+}
+
+// bigText draws text with glyphs, characters without one are left out.
+func bigText(text string) [5]string {
+	var rows [5]strings.Builder
+	for i, r := range text {
+		glyph, ok := glyphs[r]
+		if !ok {
+			continue
+		}
+
+		for row := range rows {
+			if i > 0 {
+				rows[row].WriteByte(' ')
+			}
+			rows[row].WriteString(strings.ReplaceAll(glyph[row], "#", "█"))
+		}
+	}
+
+	var lines [5]string
+	for row := range rows {
+		lines[row] = rows[row].String()
+	}
+
+	return lines
+}
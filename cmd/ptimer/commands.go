@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/PabloVarg/presentation-timer/pkg/client"
+)
+
+// subcommands runs the command named by the first argument.
+func subcommands(name string, commands map[string]command) command {
+	return func(ctx context.Context, a app, args []string) error {
+		names := slices.Sorted(maps.Keys(commands))
+
+		if len(args) == 0 {
+			return usageError{fmt.Sprintf("usage: ptimer %s %s", name, strings.Join(names, "|"))}
+		}
+
+		cmd, ok := commands[args[0]]
+		if !ok {
+			return usageError{fmt.Sprintf(
+				"unknown command %q, must be one of %s",
+				name+" "+args[0],
+				strings.Join(names, ", "),
+			)}
+		}
+
+		return cmd(ctx, a, args[1:])
+	}
+}
+
+// flagSet returns the flag set of a command, its usage lists the expected
+// arguments. The output format can also be set after the command.
+func (a *app) flagSet(name string, arguments string) *flag.FlagSet {
+	flags := flag.NewFlagSet("ptimer "+name, flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: ptimer %s [flags] %s\n", name, arguments)
+		flags.PrintDefaults()
+	}
+	flags.Func("o", "output format, one of table, json or yaml", func(value string) error {
+		if !slices.Contains(outputFormats, value) {
+			return fmt.Errorf("must be one of table, json or yaml")
+		}
+
+		a.output = value
+		return nil
+	})
+
+	return flags
+}
+
+// parseArgs parses the flags of a command, which can be placed before or after
+// its arguments, and checks that exactly count arguments were given.
+func parseArgs(flags *flag.FlagSet, args []string, count int) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+
+			return nil, usageError{}
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			break
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if count >= 0 && len(positional) != count {
+		flags.Usage()
+		return nil, usageError{}
+	}
+
+	return positional, nil
+}
+
+func parseID(arg string) (int64, error) {
+	ID, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil || ID <= 0 {
+		return 0, usageError{fmt.Sprintf("invalid ID %q", arg)}
+	}
+
+	return ID, nil
+}
+
+// ifMatchFlag adds the -if-match flag to a command that changes a resource,
+// the returned function gives the options of the request.
+func ifMatchFlag(flags *flag.FlagSet) func() []func(*http.Request) {
+	version := flags.Int("if-match", 0, "only apply the change if the resource is at this version")
+
+	return func() []func(*http.Request) {
+		if *version == 0 {
+			return nil
+		}
+
+		return []func(*http.Request){client.IfMatch(int32(*version))}
+	}
+}
+
+// listOptionsFlags adds the flags that narrow down and sort lists.
+func listOptionsFlags(flags *flag.FlagSet, opts *client.ListOptions) {
+	flags.Func("page-size", "how many items to request at a time", func(value string) error {
+		size, err := strconv.ParseInt(value, 10, 32)
+		opts.PageSize = int32(size)
+		return err
+	})
+	flags.StringVar(&opts.SortBy, "sort-by", "", "comma separated fields to sort by, prefixed with - to sort in descending order")
+	flags.StringVar(&opts.NameContains, "name-contains", "", "only list items whose name contains this")
+	flags.DurationVar(&opts.MinDuration, "min-duration", 0, "only list items lasting at least this")
+	flags.DurationVar(&opts.MaxDuration, "max-duration", 0, "only list items lasting at most this")
+	flags.StringVar(&opts.Search, "search", "", "full text search")
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+)
+
+var outputFormats = []string{"table", "json", "yaml"}
+
+// config is where the server is and how to talk to it. Values are taken, from
+// lowest to highest priority, from the defaults, the configuration file,
+// environment variables and command line flags.
+type config struct {
+	URL       string `json:"url"`
+	APIKey    string `json:"api_key"`
+	Workspace int64  `json:"workspace"`
+	Output    string `json:"output"`
+}
+
+func defaultConfig() config {
+	return config{
+		URL:    "http://localhost:8000",
+		Output: "table",
+	}
+}
+
+// defaultConfigPath is ptimer/config.json in the user configuration
+// directory, as in ~/.config/ptimer/config.json.
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, "ptimer", "config.json")
+}
+
+// loadConfig parses the global flags out of args, returning the arguments of
+// the command.
+func loadConfig(flags *flag.FlagSet, args []string) (config, []string, error) {
+	conf := defaultConfig()
+
+	configFile := flags.String("config", envOr("PTIMER_CONFIG", defaultConfigPath()), "path to a JSON configuration file (env PTIMER_CONFIG)")
+	url := flags.String("url", "", "base URL of the server (env PTIMER_URL)")
+	apiKey := flags.String("api-key", "", "API key or session token to authenticate with (env PTIMER_API_KEY)")
+	workspace := flags.Int64("workspace", 0, "workspace to act on, defaults to the one of the API key (env PTIMER_WORKSPACE)")
+	output := flags.String("o", "", "output format, one of table, json or yaml (env PTIMER_OUTPUT)")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return config{}, nil, err
+		}
+
+		// the flag set already reported the error
+		return config{}, nil, usageError{}
+	}
+
+	if *configFile != "" {
+		if err := conf.loadFile(*configFile); err != nil {
+			return config{}, nil, err
+		}
+	}
+
+	if err := conf.loadEnv(); err != nil {
+		return config{}, nil, err
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "url":
+			conf.URL = *url
+		case "api-key":
+			conf.APIKey = *apiKey
+		case "workspace":
+			conf.Workspace = *workspace
+		case "o":
+			conf.Output = *output
+		}
+	})
+
+	if !slices.Contains(outputFormats, conf.Output) {
+		return config{}, nil, usageError{fmt.Sprintf("output must be one of table, json or yaml, got %q", conf.Output)}
+	}
+
+	return conf, flags.Args(), nil
+}
+
+// loadFile reads the configuration file at path, a missing file is ignored.
+func (c *config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return err
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *config) loadEnv() error {
+	if value, ok := os.LookupEnv("PTIMER_URL"); ok {
+		c.URL = value
+	}
+	if value, ok := os.LookupEnv("PTIMER_API_KEY"); ok {
+		c.APIKey = value
+	}
+	if value, ok := os.LookupEnv("PTIMER_OUTPUT"); ok {
+		c.Output = value
+	}
+	if value, ok := os.LookupEnv("PTIMER_WORKSPACE"); ok {
+		workspace, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("env PTIMER_WORKSPACE: %w", err)
+		}
+
+		c.Workspace = workspace
+	}
+
+	return nil
+}
+
+func envOr(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+
+	return fallback
+}
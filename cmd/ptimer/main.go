@@ -0,0 +1,119 @@
+// Command ptimer manages the presentations of a presentation timer server from
+// the command line.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	"github.com/PabloVarg/presentation-timer/pkg/client"
+)
+
+const usage = `Usage: ptimer [flags] <command> [arguments]
+
+Commands:
+  presentations list|get|create|edit|delete
+  sections      list|create|edit|delete|move|reorder
+  export        <presentation> [-f file] [-format yaml|json]
+  import        <file> [-into presentation]
+  run           <presentation> [-extend duration]
+
+Run ptimer <command> -h for the arguments of a command.
+
+Flags:
+`
+
+// app is what commands need to run, a client for the server and where to
+// write their output.
+type app struct {
+	client *client.Client
+	out    io.Writer
+	output string
+}
+
+type command func(ctx context.Context, a app, args []string) error
+
+var commands = map[string]command{
+	"presentations": presentationsCommand,
+	"sections":      sectionsCommand,
+	"export":        exportCommand,
+	"import":        importCommand,
+	"run":           runCommand,
+}
+
+func main() {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Kill, os.Interrupt)
+	defer cancel()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		var usageErr usageError
+		if errors.As(err, &usageErr) {
+			if usageErr.message != "" {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			os.Exit(2)
+		}
+
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("ptimer", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+
+	conf, args, err := loadConfig(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		flags.Usage()
+		return usageError{"a command is required"}
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		flags.Usage()
+		return usageError{fmt.Sprintf("unknown command %q", args[0])}
+	}
+
+	opts := []func(*client.Client){}
+	if conf.APIKey != "" {
+		opts = append(opts, client.WithToken(conf.APIKey))
+	}
+	if conf.Workspace != 0 {
+		opts = append(opts, client.WithWorkspace(conf.Workspace))
+	}
+
+	c, err := client.New(conf.URL, opts...)
+	if err != nil {
+		return usageError{err.Error()}
+	}
+
+	return cmd(ctx, app{client: c, out: stdout, output: conf.Output}, args[1:])
+}
+
+// usageError is returned when a command is called wrong, it makes ptimer exit
+// with status 2. Errors without a message were already reported.
+type usageError struct {
+	message string
+}
+
+func (e usageError) Error() string {
+	return e.message
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// table is how a value is shown with the table output.
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+func (t table) write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(tw, strings.Join(t.headers, "\t"))
+	for _, row := range t.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+
+	return tw.Flush()
+}
+
+// render writes value in the output format of a, as it is returned by the
+// API for JSON and YAML and as tables otherwise.
+func (a app) render(value any, tables ...table) error {
+	switch a.output {
+	case "json":
+		return writeJSON(a.out, value)
+	case "yaml":
+		return writeYAML(a.out, value)
+	default:
+		for i, t := range tables {
+			if i > 0 {
+				fmt.Fprintln(a.out)
+			}
+
+			if err := t.write(a.out); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// notice tells what a command did when its result is not rendered, it is
+// left out of JSON and YAML outputs so they stay machine readable.
+func (a app) notice(format string, args ...any) {
+	if a.output != "table" {
+		return
+	}
+
+	fmt.Fprintf(a.out, format+"\n", args...)
+}
+
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+// writeYAML writes value with the field names and order of its JSON encoding.
+func writeYAML(w io.Writer, value any) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(encoded, &node); err != nil {
+		return err
+	}
+	plainStyle(&node)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// plainStyle drops the JSON flow and quoting styles of node, yaml picks the
+// ones needed when encoding.
+func plainStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		plainStyle(child)
+	}
+}
+
+// encodeAs encodes value in format, either json or yaml.
+func encodeAs(format string, value any) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var err error
+	switch format {
+	case "json":
+		err = writeJSON(&buf, value)
+	default:
+		err = writeYAML(&buf, value)
+	}
+
+	return buf.Bytes(), err
+}
+
+// formatDuration writes d in seconds, leaving out the trailing zero units, as
+// in 5m instead of 5m0s.
+func formatDuration(d time.Duration) string {
+	formatted := d.Round(time.Second).String()
+	if strings.HasSuffix(formatted, "m0s") {
+		formatted = strings.TrimSuffix(formatted, "0s")
+	}
+	if strings.HasSuffix(formatted, "h0m") {
+		formatted = strings.TrimSuffix(formatted, "0m")
+	}
+
+	return formatted
+}
+
+func formatTime(t time.Time) string {
+	return t.Local().Format(time.DateTime)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/PabloVarg/presentation-timer/pkg/client"
+)
+
+var presentationsCommand = subcommands("presentations", map[string]command{
+	"list":   listPresentations,
+	"get":    getPresentation,
+	"create": createPresentation,
+	"edit":   editPresentation,
+	"delete": deletePresentation,
+})
+
+func presentationsTable(presentations ...client.Presentation) table {
+	t := table{headers: []string{"ID", "NAME", "DURATION", "VERSION", "CREATED"}}
+	for _, p := range presentations {
+		// the duration is unknown when the presentation was not listed
+		duration := "-"
+		if p.Duration != nil {
+			duration = formatDuration(*p.Duration)
+		}
+
+		t.add(
+			strconv.FormatInt(p.ID, 10),
+			p.Name,
+			duration,
+			strconv.FormatInt(int64(p.Version), 10),
+			formatTime(p.CreatedAt),
+		)
+	}
+
+	return t
+}
+
+func listPresentations(ctx context.Context, a app, args []string) error {
+	var opts client.PresentationListOptions
+
+	flags := a.flagSet("presentations list", "")
+	listOptionsFlags(flags, &opts.ListOptions)
+	flags.BoolVar(&opts.SharedWithMe, "shared", false, "only list the presentations shared with you by others")
+	limit := flags.Int("limit", 0, "list at most this many presentations, 0 lists all of them")
+	if _, err := parseArgs(flags, args, 0); err != nil {
+		return err
+	}
+
+	presentations := []client.Presentation{}
+	for presentation, err := range a.client.Presentations(ctx, opts) {
+		if err != nil {
+			return err
+		}
+
+		presentations = append(presentations, presentation)
+		if len(presentations) == *limit {
+			break
+		}
+	}
+
+	return a.render(presentations, presentationsTable(presentations...))
+}
+
+func getPresentation(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("presentations get", "<presentation>")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	presentation, err := a.client.GetPresentationWithSections(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	return a.render(
+		presentation,
+		presentationsTable(presentation.Presentation),
+		sectionsTable(presentation.Sections...),
+	)
+}
+
+func createPresentation(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("presentations create", "<name>")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	presentation, err := a.client.CreatePresentation(ctx, client.PresentationInput{Name: args[0]})
+	if err != nil {
+		return err
+	}
+
+	return a.render(presentation, presentationsTable(presentation))
+}
+
+func editPresentation(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("presentations edit", "<presentation>")
+	name := flags.String("name", "", "new name of the presentation")
+	ifMatch := ifMatchFlag(flags)
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	var patch client.PresentationPatch
+	flags.Visit(func(f *flag.Flag) {
+		if f.Name == "name" {
+			patch.Name = name
+		}
+	})
+	if patch == (client.PresentationPatch{}) {
+		return usageError{"nothing to edit, set -name"}
+	}
+
+	if _, err := a.client.PatchPresentation(ctx, ID, patch, ifMatch()...); err != nil {
+		return err
+	}
+
+	presentation, err := a.client.GetPresentation(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	return a.render(presentation, presentationsTable(presentation))
+}
+
+func deletePresentation(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("presentations delete", "<presentation>")
+	ifMatch := ifMatchFlag(flags)
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := a.client.DeletePresentation(ctx, ID, ifMatch()...); err != nil {
+		return err
+	}
+
+	a.notice("presentation %d moved to the trash", ID)
+	return nil
+}
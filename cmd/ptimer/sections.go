@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"strconv"
+
+	"github.com/PabloVarg/presentation-timer/pkg/client"
+)
+
+var sectionsCommand = subcommands("sections", map[string]command{
+	"list":    listSections,
+	"create":  createSection,
+	"edit":    editSection,
+	"delete":  deleteSection,
+	"move":    moveSection,
+	"reorder": reorderSections,
+})
+
+func sectionsTable(sections ...client.Section) table {
+	t := table{headers: []string{"ID", "POSITION", "NAME", "DURATION", "VERSION"}}
+	for _, s := range sections {
+		t.add(
+			strconv.FormatInt(s.ID, 10),
+			strconv.FormatInt(int64(s.Position), 10),
+			s.Name,
+			formatDuration(s.Duration),
+			strconv.FormatInt(int64(s.Version), 10),
+		)
+	}
+
+	return t
+}
+
+func listSections(ctx context.Context, a app, args []string) error {
+	var opts client.ListOptions
+
+	flags := a.flagSet("sections list", "<presentation>")
+	listOptionsFlags(flags, &opts)
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	presentationID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	sections := []client.Section{}
+	for section, err := range a.client.Sections(ctx, presentationID, opts) {
+		if err != nil {
+			return err
+		}
+
+		sections = append(sections, section)
+	}
+
+	return a.render(sections, sectionsTable(sections...))
+}
+
+func createSection(ctx context.Context, a app, args []string) error {
+	var input client.SectionInput
+
+	flags := a.flagSet("sections create", "<presentation>")
+	flags.StringVar(&input.Name, "name", "", "name of the section")
+	flags.DurationVar(&input.Duration, "duration", 0, "how long the section lasts, as in 5m or 1m30s")
+	position := flags.Int("position", -1, "position of the section, defaults to after the last one")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	presentationID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if *position >= 0 {
+		p := int16(*position)
+		input.Position = &p
+	}
+
+	section, err := a.client.CreateSection(ctx, presentationID, input)
+	if err != nil {
+		return err
+	}
+
+	return a.render(section, sectionsTable(section))
+}
+
+func editSection(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("sections edit", "<section>")
+	name := flags.String("name", "", "new name of the section")
+	duration := flags.Duration("duration", 0, "new duration of the section")
+	position := flags.Int("position", 0, "new position of the section")
+	ifMatch := ifMatchFlag(flags)
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	var patch client.SectionPatch
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "name":
+			patch.Name = name
+		case "duration":
+			patch.Duration = duration
+		case "position":
+			p := int16(*position)
+			patch.Position = &p
+		}
+	})
+	if patch == (client.SectionPatch{}) {
+		return usageError{"nothing to edit, set -name, -duration or -position"}
+	}
+
+	if _, err := a.client.PatchSection(ctx, ID, patch, ifMatch()...); err != nil {
+		return err
+	}
+
+	section, err := a.client.GetSection(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	return a.render(section, sectionsTable(section))
+}
+
+func deleteSection(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("sections delete", "<section>")
+	ifMatch := ifMatchFlag(flags)
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := a.client.DeleteSection(ctx, ID, ifMatch()...); err != nil {
+		return err
+	}
+
+	a.notice("section %d moved to the trash", ID)
+	return nil
+}
+
+func moveSection(ctx context.Context, a app, args []string) error {
+	var movement client.SectionMovement
+
+	flags := a.flagSet("sections move", "<section>")
+	flags.Func("by", "how many positions to move the section by, negative to move it up", func(value string) error {
+		by, err := strconv.ParseInt(value, 10, 32)
+		move := int32(by)
+		movement.Move = &move
+		return err
+	})
+	flags.Func("before", "section to move the section before", func(value string) error {
+		ID, err := parseID(value)
+		movement.MoveBefore = &ID
+		return err
+	})
+	flags.Func("after", "section to move the section after", func(value string) error {
+		ID, err := parseID(value)
+		movement.MoveAfter = &ID
+		return err
+	})
+	ifMatch := ifMatchFlag(flags)
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	if movement == (client.SectionMovement{}) {
+		return usageError{"nowhere to move the section to, set -by, -before or -after"}
+	}
+
+	if _, err := a.client.MoveSection(ctx, ID, movement, ifMatch()...); err != nil {
+		return err
+	}
+
+	section, err := a.client.GetSection(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	return a.render(section, sectionsTable(section))
+}
+
+func reorderSections(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("sections reorder", "<presentation> <section>...")
+	args, err := parseArgs(flags, args, -1)
+	if err != nil {
+		return err
+	}
+	if len(args) < 2 {
+		flags.Usage()
+		return usageError{}
+	}
+
+	presentationID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+
+	order := make([]int64, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		ID, err := parseID(arg)
+		if err != nil {
+			return err
+		}
+
+		order = append(order, ID)
+	}
+
+	sections, err := a.client.ReorderSections(ctx, presentationID, order)
+	if err != nil {
+		return err
+	}
+
+	return a.render(sections, sectionsTable(sections...))
+}
@@ -0,0 +1,466 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/PabloVarg/presentation-timer/pkg/client"
+	"golang.org/x/term"
+)
+
+// Escape sequences of the terminal.
+const (
+	escAltScreen     = "\x1b[?1049h"
+	escMainScreen    = "\x1b[?1049l"
+	escHideCursor    = "\x1b[?25l"
+	escShowCursor    = "\x1b[?25h"
+	escHome          = "\x1b[H"
+	escClearLine     = "\x1b[K"
+	escClearToBottom = "\x1b[J"
+	escReset         = "\x1b[0m"
+	escBold          = "\x1b[1m"
+	escDim           = "\x1b[2m"
+	escRed           = "\x1b[31m"
+	escGreen         = "\x1b[32m"
+	escYellow        = "\x1b[33m"
+)
+
+// Actions mapped to keys.
+const (
+	keyToggle   = "toggle"
+	keyStart    = "start"
+	keyPause    = "pause"
+	keyResume   = "resume"
+	keyNext     = "next"
+	keyPrevious = "previous"
+	keyExtend   = "extend"
+	keyQuit     = "quit"
+)
+
+// tuiFrame is how often the countdown is redrawn between states.
+const tuiFrame = 100 * time.Millisecond
+
+// runView is what the terminal shows of a run.
+type runView struct {
+	presentation client.PresentationWithSections
+	extendBy     time.Duration
+	state        client.RunState
+	// received is when state was received, the countdown goes on from it
+	received time.Time
+	// overSince is when the last section ran out, zero otherwise
+	overSince time.Time
+	message   string
+	// messageUntil is when message stops being shown
+	messageUntil time.Time
+}
+
+func runCommand(ctx context.Context, a app, args []string) error {
+	flags := a.flagSet("run", "<presentation>")
+	extendBy := flags.Duration("extend", time.Minute, "time added to the current section by the extend key")
+	args, err := parseArgs(flags, args, 1)
+	if err != nil {
+		return err
+	}
+
+	ID, err := parseID(args[0])
+	if err != nil {
+		return err
+	}
+	if *extendBy < time.Millisecond || *extendBy > 24*time.Hour {
+		return usageError{"extend must be between 1ms and 24h"}
+	}
+
+	stdin, stdout := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !term.IsTerminal(stdin) || !term.IsTerminal(stdout) {
+		return errors.New("run needs a terminal")
+	}
+
+	presentation, err := a.client.GetPresentationWithSections(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	run, err := a.client.Run(ctx, ID)
+	if err != nil {
+		return err
+	}
+	defer run.Close()
+
+	previous, err := term.MakeRaw(stdin)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(stdin, previous)
+
+	fmt.Fprint(os.Stdout, escAltScreen+escHideCursor)
+	defer fmt.Fprint(os.Stdout, escReset+escShowCursor+escMainScreen)
+
+	view := &runView{presentation: presentation, extendBy: *extendBy}
+	if state, err := run.Status(ctx); err == nil {
+		view.update(state)
+	}
+
+	keys := readKeys(ctx)
+	results := make(chan error, 1)
+	ticker := time.NewTicker(tuiFrame)
+	defer ticker.Stop()
+
+	for {
+		width, height, err := term.GetSize(stdout)
+		if err != nil {
+			width, height = 80, 24
+		}
+		fmt.Fprint(os.Stdout, view.render(width, height))
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case state, ok := <-run.States():
+			if !ok {
+				return run.Err()
+			}
+
+			view.update(state)
+		case err := <-results:
+			view.notify(describeRunError(err))
+		case key, ok := <-keys:
+			if !ok || key == keyQuit {
+				return nil
+			}
+
+			action := view.action(run, key)
+			if action == nil {
+				continue
+			}
+
+			go func() {
+				actionCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+				defer cancel()
+
+				if err := action(actionCtx); err != nil {
+					select {
+					case results <- err:
+					case <-ctx.Done():
+					}
+				}
+			}()
+		}
+	}
+}
+
+// readKeys delivers the actions of the keys pressed until ctx is done or the
+// terminal is closed.
+func readKeys(ctx context.Context) <-chan string {
+	keys := make(chan string)
+
+	go func() {
+		defer close(keys)
+
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+
+			key := keyAction(buf[:n])
+			if key == "" {
+				continue
+			}
+
+			select {
+			case keys <- key:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return keys
+}
+
+func keyAction(input []byte) string {
+	switch string(input) {
+	case " ", "\r":
+		return keyToggle
+	case "s":
+		return keyStart
+	case "p":
+		return keyPause
+	case "r":
+		return keyResume
+	case "n", "l", "\x1b[C":
+		return keyNext
+	case "b", "h", "\x1b[D":
+		return keyPrevious
+	case "e", "+":
+		return keyExtend
+	case "q", "\x03", "\x1b":
+		return keyQuit
+	default:
+		return ""
+	}
+}
+
+// action is the request sent to the run for key, nil when there is none.
+func (v *runView) action(run *client.Run, key string) func(context.Context) error {
+	if key == keyToggle {
+		switch {
+		case v.state.State == "running":
+			key = keyPause
+		case v.idle():
+			key = keyStart
+		default:
+			key = keyResume
+		}
+	}
+
+	switch key {
+	case keyStart:
+		v.overSince = time.Time{}
+		return run.Start
+	case keyPause:
+		return run.Pause
+	case keyResume:
+		return run.Resume
+	case keyExtend:
+		return func(ctx context.Context) error {
+			return run.Extend(ctx, v.extendBy)
+		}
+	case keyNext, keyPrevious:
+		step := v.index()
+		if key == keyNext {
+			step++
+		} else {
+			step--
+		}
+		if step < 0 || step >= len(v.presentation.Sections) {
+			return nil
+		}
+
+		return func(ctx context.Context) error {
+			return run.Step(ctx, int32(step))
+		}
+	default:
+		return nil
+	}
+}
+
+func (v *runView) update(state client.RunState) {
+	now := time.Now()
+
+	last := len(v.presentation.Sections) - 1
+	finished := state.State != "running" && state.MsLeft == 0 && v.sectionIndex(state.Step.ID) == last
+	switch {
+	case !finished:
+		v.overSince = time.Time{}
+	case v.state.State == "running" && v.overSince.IsZero():
+		v.overSince = now
+	}
+
+	v.state = state
+	v.received = now
+}
+
+func (v *runView) notify(message string) {
+	v.message = message
+	v.messageUntil = time.Now().Add(5 * time.Second)
+}
+
+// idle reports whether the run has not started or is over.
+func (v *runView) idle() bool {
+	return v.state.State != "running" && v.state.MsLeft == 0
+}
+
+// index is the position of the current section in the presentation.
+func (v *runView) index() int {
+	return v.sectionIndex(v.state.Step.ID)
+}
+
+// sectionIndex is the position of a section in the presentation, -1 when it
+// is not part of it.
+func (v *runView) sectionIndex(ID int64) int {
+	return slices.IndexFunc(v.presentation.Sections, func(s client.Section) bool {
+		return s.ID == ID
+	})
+}
+
+// left is the time left of the current section.
+func (v *runView) left() time.Duration {
+	left := time.Duration(v.state.MsLeft) * time.Millisecond
+	if v.state.State == "running" {
+		left -= time.Since(v.received)
+	}
+
+	return max(left, 0)
+}
+
+func (v *runView) render(width, height int) string {
+	var lines []string
+	line := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	index, sections := v.index(), v.presentation.Sections
+	left := v.left()
+
+	status := v.state.State
+	switch {
+	case !v.overSince.IsZero():
+		status = "finished"
+	case v.idle():
+		status = "ready"
+	case status != "running":
+		status = "paused"
+	}
+	line("%s%s%s  %s%s%s", escBold, v.presentation.Name, escReset, escDim, status, escReset)
+	line("")
+
+	countdown, color := formatCountdown(left), escGreen
+	switch {
+	case !v.overSince.IsZero():
+		countdown, color = "+"+formatCountdown(time.Since(v.overSince)), escRed
+	case v.state.State == "running" && left <= min(time.Minute, v.state.Step.Duration/5):
+		color = escYellow
+	case v.state.State != "running":
+		color = escDim
+	}
+
+	big := bigText(countdown)
+	if utf8.RuneCountInString(big[0]) < width && height >= 16 {
+		for _, row := range big {
+			line("%s%s%s", color, row, escReset)
+		}
+	} else {
+		line("%s%s%s%s", escBold, color, countdown, escReset)
+	}
+	line("")
+
+	if index >= 0 {
+		line("Now   %s (%d/%d) %s", v.state.Step.Name, index+1, len(sections), formatDuration(v.state.Step.Duration))
+	} else {
+		line("Now   %s", v.state.Step.Name)
+	}
+	if index >= 0 && index+1 < len(sections) {
+		next := sections[index+1]
+		line("Next  %s %s", next.Name, formatDuration(next.Duration))
+	} else {
+		line("Next  %s-%s", escDim, escReset)
+	}
+	line("")
+
+	line("%s", progressBar(v.progress(left), min(width, 60), color))
+	line("Total left %s of %s", formatCountdown(v.totalLeft(index, left)), formatCountdown(v.presentation.Totals.Duration))
+	line("")
+
+	if v.message != "" && time.Now().Before(v.messageUntil) {
+		line("%s%s%s", escRed, v.message, escReset)
+	} else {
+		line("")
+	}
+	line(
+		"%sspace start/pause/resume  ←/b previous  →/n next  e +%s  s restart  q quit%s",
+		escDim,
+		formatDuration(v.extendBy),
+		escReset,
+	)
+
+	var b strings.Builder
+	b.WriteString(escHome)
+	for i, l := range lines {
+		if i >= height {
+			break
+		}
+		if i > 0 {
+			b.WriteString("\r\n")
+		}
+
+		b.WriteString(l)
+		b.WriteString(escClearLine)
+	}
+	b.WriteString(escClearToBottom)
+
+	return b.String()
+}
+
+// progress is how much of the current section is over, from 0 to 1.
+func (v *runView) progress(left time.Duration) float64 {
+	if v.idle() && v.overSince.IsZero() {
+		return 0
+	}
+
+	duration := v.state.Step.Duration
+	if duration <= 0 || left > duration {
+		return 0
+	}
+
+	return 1 - float64(left)/float64(duration)
+}
+
+// totalLeft is the time left of the whole presentation.
+func (v *runView) totalLeft(index int, left time.Duration) time.Duration {
+	if index < 0 || (v.idle() && v.overSince.IsZero()) {
+		return v.presentation.Totals.Duration
+	}
+
+	total := left
+	for _, section := range v.presentation.Sections[index+1:] {
+		total += section.Duration
+	}
+
+	return total
+}
+
+func progressBar(progress float64, width int, color string) string {
+	width -= 7
+	if width < 1 {
+		return fmt.Sprintf("%3.0f%%", progress*100)
+	}
+
+	filled := int(math.Round(progress * float64(width)))
+	return fmt.Sprintf(
+		"%s%s%s%s %3.0f%%",
+		color,
+		strings.Repeat("█", filled),
+		escReset,
+		strings.Repeat("░", width-filled),
+		progress*100,
+	)
+}
+
+// formatCountdown writes d as M:SS or H:MM:SS, rounding seconds up so a
+// section starts at its full duration.
+func formatCountdown(d time.Duration) string {
+	seconds := int64(math.Ceil(d.Seconds()))
+	hours, minutes := seconds/3600, seconds/60%60
+	seconds %= 60
+
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+func describeRunError(err error) string {
+	var runErr *client.RunError
+	switch {
+	case errors.As(err, &runErr):
+		return runErr.Message
+	case errors.Is(err, client.ErrRunDisconnected):
+		return "connection lost, reconnecting"
+	default:
+		return err.Error()
+	}
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log/slog"
 	"os"
@@ -9,69 +11,235 @@ import (
 	"sync"
 	"time"
 
-	"github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/config"
+	"github.com/PabloVarg/presentation-timer/internal/migrate"
 	"github.com/PabloVarg/presentation-timer/internal/server"
-	"github.com/jackc/pgx/v5"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	conf, args, err := config.Load(os.Args[0], os.Args[1:], os.Stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	logger := newLogger(conf.Log)
+
+	switch {
+	case len(args) == 0:
+		run(logger, conf)
+	case args[0] == "migrate" && len(args) == 2:
+		if err := runMigrate(logger, conf, args[1]); err != nil {
+			logger.Error("migrate", "err", err)
+			os.Exit(1)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Usage: %s [flags] [migrate up|down|status|version]\n", os.Args[0])
+		os.Exit(2)
+	}
+}
 
-	run(logger)
+func newLogger(conf config.Log) *slog.Logger {
+	var level slog.Level
+	level.UnmarshalText([]byte(conf.Level))
+
+	opts := &slog.HandlerOptions{Level: level}
+	if conf.Format == "text" {
+		return slog.New(slog.NewTextHandler(os.Stdout, opts))
+	}
+
+	return slog.New(slog.NewJSONHandler(os.Stdout, opts))
 }
 
-func run(logger *slog.Logger) {
+func run(logger *slog.Logger, conf config.Config) {
 	var wg sync.WaitGroup
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Kill, os.Interrupt)
 	defer cancel()
 
-	port, ok := os.LookupEnv("PORT")
-	if !ok {
-		logger.Error("no port configured", "env", "PORT")
-		return
-	}
-
-	queriesStore, db, err := createQueries(ctx)
+	b, err := createStore(ctx, logger, conf)
 	if err != nil {
-		logger.ErrorContext(ctx, "error connecting to DB", "err", err)
+		logger.ErrorContext(ctx, "error creating store", "storage", conf.Storage, "err", err)
 		return
 	}
-	defer func() {
-		closeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	defer b.close()
 
-		db.Close(closeCtx)
-	}()
+	if conf.Migrate && b.migrator != nil {
+		if err := b.migrator.Up(ctx); err != nil {
+			logger.ErrorContext(ctx, "error applying migrations", "err", err)
+			return
+		}
+	}
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
 
-		server.RunTasks(ctx, logger, queriesStore)
+		server.RunTasks(
+			ctx,
+			logger,
+			b.store,
+			server.WithSectionOrderCleanInterval(time.Duration(conf.Tasks.CleanSectionInterval)),
+			server.WithSessionCleanInterval(time.Duration(conf.Tasks.CleanSessionsInterval)),
+			server.WithTrashPurgeInterval(time.Duration(conf.Tasks.PurgeTrashInterval)),
+			server.WithTrashRetention(time.Duration(conf.Tasks.TrashRetention)),
+			server.WithDBTimeout(time.Duration(conf.DB.Timeout)),
+		)
 	}()
 
-	server.ListenAndServe(ctx, &wg, fmt.Sprintf(":%s", port), logger, queriesStore)
+	server.ListenAndServe(ctx, &wg, serverConfig(conf), logger, b.store)
 
 	logger.Info("closing resources")
 	wg.Wait()
 }
 
-func createQueries(ctx context.Context) (*queries.Queries, *pgx.Conn, error) {
-	conn, err := pgx.Connect(
-		ctx,
-		fmt.Sprintf(
-			"dbname=%s user=%s password=%s host=%s sslmode=%s",
-			os.Getenv("POSTGRES_DB"),
-			os.Getenv("POSTGRES_USER"),
-			os.Getenv("POSTGRES_PASSWORD"),
-			os.Getenv("POSTGRES_HOST"),
-			os.Getenv("POSTGRES_SSLMODE"),
-		),
-	)
+func serverConfig(conf config.Config) server.Config {
+	return server.Config{
+		Addr:                  fmt.Sprintf(":%d", conf.Port),
+		ReadTimeout:           time.Duration(conf.HTTP.ReadTimeout),
+		WriteTimeout:          time.Duration(conf.HTTP.WriteTimeout),
+		IdleTimeout:           time.Duration(conf.HTTP.IdleTimeout),
+		ShutdownTimeout:       time.Duration(conf.HTTP.ShutdownTimeout),
+		DBTimeout:             time.Duration(conf.DB.Timeout),
+		PresentationsPageSize: conf.Pagination.PresentationsPageSize,
+		SectionsPageSize:      conf.Pagination.SectionsPageSize,
+		AuditPageSize:         conf.Pagination.AuditPageSize,
+		VersionsPageSize:      conf.Pagination.VersionsPageSize,
+		AuthEnabled:           conf.Auth.Enabled,
+		SessionTTL:            time.Duration(conf.Auth.SessionTTL),
+		AllowSignup:           conf.Auth.AllowSignup,
+		MetricsAddr:           conf.Metrics.Addr,
+	}
+}
+
+func runMigrate(logger *slog.Logger, conf config.Config, command string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Kill, os.Interrupt)
+	defer cancel()
+
+	b, err := createStore(ctx, logger, conf)
+	if err != nil {
+		return err
+	}
+	defer b.close()
+
+	if b.migrator == nil {
+		return fmt.Errorf("storage %q has no migrations", conf.Storage)
+	}
+
+	switch command {
+	case "up":
+		return b.migrator.Up(ctx)
+	case "down":
+		return b.migrator.Down(ctx)
+	case "status":
+		lines, err := b.migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+		return nil
+	case "version":
+		version, err := b.migrator.Version(ctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(version)
+		return nil
+	default:
+		return errors.New("migrate command must be one of up, down, status or version")
+	}
+}
+
+type backend struct {
+	store store.Store
+	// migrator is nil for storages without a schema
+	migrator *migrate.Migrator
+	close    func()
+}
+
+func createStore(ctx context.Context, logger *slog.Logger, conf config.Config) (backend, error) {
+	switch conf.Storage {
+	case "postgres":
+		return createQueries(ctx, logger, conf.DB)
+	case "sqlite":
+		return createSQLite(logger, conf.DB)
+	case "memory":
+		return backend{
+			store: store.NewMemory(),
+			close: func() {},
+		}, nil
+	default:
+		return backend{}, fmt.Errorf("unknown storage %q", conf.Storage)
+	}
+}
+
+func createQueries(ctx context.Context, logger *slog.Logger, conf config.DB) (backend, error) {
+	poolConf, err := pgxpool.ParseConfig(conf.DSN)
+	if err != nil {
+		return backend{}, err
+	}
+	poolConf.MaxConns = conf.MaxConns
+	poolConf.ConnConfig.Tracer = store.QueryTracer{}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConf)
+	if err != nil {
+		return backend{}, err
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, time.Duration(conf.Timeout))
+	defer cancel()
+
+	if err := pool.Ping(pingCtx); err != nil {
+		pool.Close()
+		return backend{}, err
+	}
+
+	db := stdlib.OpenDBFromPool(pool)
+	closeDB := func() {
+		db.Close()
+		pool.Close()
+	}
+
+	migrator, err := migrate.NewPostgres(logger, db)
+	if err != nil {
+		closeDB()
+		return backend{}, err
+	}
+
+	return backend{
+		store:    store.NewPostgres(pool),
+		migrator: migrator,
+		close:    closeDB,
+	}, nil
+}
+
+func createSQLite(logger *slog.Logger, conf config.DB) (backend, error) {
+	db, err := store.OpenSQLite(conf.SQLitePath)
+	if err != nil {
+		return backend{}, err
+	}
+	closeDB := func() { db.Close() }
+
+	migrator, err := migrate.NewSQLite(logger, db)
 	if err != nil {
-		return nil, nil, err
+		closeDB()
+		return backend{}, err
 	}
 
-	return queries.New(conn), nil, nil
+	return backend{
+		store:    store.NewSQLite(db),
+		migrator: migrator,
+		close:    closeDB,
+	}, nil
 }
@@ -0,0 +1,127 @@
+// Package auth holds the primitives used to authenticate requests: password
+// hashing, opaque tokens and the account attached to a request context.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Token prefixes tell session tokens, API keys and share links apart without
+// a lookup.
+const (
+	SessionPrefix   = "pts_"
+	APIKeyPrefix    = "ptk_"
+	ShareLinkPrefix = "ptl_"
+)
+
+// Account is the authenticated account of a request.
+type Account struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// ShareLink is the share link a request was made with, it gives read only
+// access to a single presentation.
+type ShareLink struct {
+	ID             int64
+	PresentationID int64
+	ExpiresAt      *time.Time
+}
+
+// Workspace is the workspace a request acts on and the role the account has
+// in it, Role is empty when authentication is disabled.
+type Workspace struct {
+	ID   int64
+	Role string
+}
+
+type (
+	contextKey          struct{}
+	shareLinkContextKey struct{}
+	workspaceContextKey struct{}
+)
+
+func WithAccount(ctx context.Context, account Account) context.Context {
+	return context.WithValue(ctx, contextKey{}, account)
+}
+
+// AccountFromContext returns the account attached by the authentication
+// middleware, ok is false for anonymous requests.
+func AccountFromContext(ctx context.Context) (Account, bool) {
+	account, ok := ctx.Value(contextKey{}).(Account)
+	return account, ok
+}
+
+func WithShareLink(ctx context.Context, link ShareLink) context.Context {
+	return context.WithValue(ctx, shareLinkContextKey{}, link)
+}
+
+func ShareLinkFromContext(ctx context.Context) (ShareLink, bool) {
+	link, ok := ctx.Value(shareLinkContextKey{}).(ShareLink)
+	return link, ok
+}
+
+func WithWorkspace(ctx context.Context, workspace Workspace) context.Context {
+	return context.WithValue(ctx, workspaceContextKey{}, workspace)
+}
+
+// WorkspaceFromContext returns the workspace attached by the authentication
+// middleware, ok is false for requests made with a share link.
+func WorkspaceFromContext(ctx context.Context) (Workspace, bool) {
+	workspace, ok := ctx.Value(workspaceContextKey{}).(Workspace)
+	return workspace, ok
+}
+
+// dummyPasswordHash is the bcrypt hash of a random password nobody knows, at
+// the default cost. It is checked when an account does not exist, so failed
+// logins take as long whether the username exists or not.
+var dummyPasswordHash = []byte("$2a$10$1fh1WdOD2TUgAc7nQ4x85uHXOaBhSnS/whC231Qm6c0dfzn5W3ayO")
+
+func HashPassword(password string) ([]byte, error) {
+	return bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+}
+
+func CheckPassword(hash []byte, password string) bool {
+	return bcrypt.CompareHashAndPassword(hash, []byte(password)) == nil
+}
+
+// CheckNoPassword takes as long as CheckPassword, for accounts that don't
+// exist. It always fails.
+func CheckNoPassword(password string) bool {
+	bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(password))
+	return false
+}
+
+// NewToken returns a random token with the given prefix and the hash it must
+// be stored as, the token itself is only ever shown to the client.
+func NewToken(prefix string) (string, []byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	token := prefix + base64.RawURLEncoding.EncodeToString(b)
+	return token, HashToken(token), nil
+}
+
+func HashToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
+
+// IsAPIKey reports whether token was issued as an API key.
+func IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, APIKeyPrefix)
+}
+
+// IsShareLink reports whether token was issued for a share link.
+func IsShareLink(token string) bool {
+	return strings.HasPrefix(token, ShareLinkPrefix)
+}
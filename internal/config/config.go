@@ -0,0 +1,320 @@
+// Package config loads the server configuration. Values are taken, from lowest
+// to highest priority, from the defaults, a JSON file, environment variables
+// and command line flags.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+type Config struct {
+	Port    int    `json:"port"`
+	Storage string `json:"storage"`
+	Migrate bool   `json:"migrate"`
+
+	HTTP       HTTP       `json:"http"`
+	DB         DB         `json:"db"`
+	Pagination Pagination `json:"pagination"`
+	Auth       Auth       `json:"auth"`
+	Tasks      Tasks      `json:"tasks"`
+	Log        Log        `json:"log"`
+	Metrics    Metrics    `json:"metrics"`
+}
+
+type HTTP struct {
+	ReadTimeout     Duration `json:"read_timeout"`
+	WriteTimeout    Duration `json:"write_timeout"`
+	IdleTimeout     Duration `json:"idle_timeout"`
+	ShutdownTimeout Duration `json:"shutdown_timeout"`
+}
+
+type DB struct {
+	DSN        string   `json:"dsn"`
+	MaxConns   int32    `json:"max_conns"`
+	Timeout    Duration `json:"timeout"`
+	SQLitePath string   `json:"sqlite_path"`
+}
+
+type Pagination struct {
+	PresentationsPageSize int32 `json:"presentations_page_size"`
+	SectionsPageSize      int32 `json:"sections_page_size"`
+	AuditPageSize         int32 `json:"audit_page_size"`
+	VersionsPageSize      int32 `json:"versions_page_size"`
+}
+
+type Auth struct {
+	Enabled     bool     `json:"enabled"`
+	SessionTTL  Duration `json:"session_ttl"`
+	AllowSignup bool     `json:"allow_signup"`
+}
+
+type Tasks struct {
+	CleanSectionInterval  Duration `json:"clean_section_interval"`
+	CleanSessionsInterval Duration `json:"clean_sessions_interval"`
+	PurgeTrashInterval    Duration `json:"purge_trash_interval"`
+	// TrashRetention is how long deleted presentations and sections are kept
+	// before they are purged
+	TrashRetention Duration `json:"trash_retention"`
+}
+
+type Metrics struct {
+	// Addr is where the metrics are served, apart from the api. Metrics are
+	// not served when it is empty
+	Addr string `json:"addr"`
+}
+
+type Log struct {
+	Level  string `json:"level"`
+	Format string `json:"format"`
+}
+
+var (
+	Storages   = []string{"postgres", "sqlite", "memory"}
+	LogLevels  = []string{"debug", "info", "warn", "error"}
+	LogFormats = []string{"json", "text"}
+)
+
+func Default() Config {
+	return Config{
+		Port:    8000,
+		Storage: "postgres",
+		HTTP: HTTP{
+			ReadTimeout:     Duration(10 * time.Second),
+			WriteTimeout:    Duration(30 * time.Second),
+			IdleTimeout:     Duration(time.Minute),
+			ShutdownTimeout: Duration(5 * time.Second),
+		},
+		DB: DB{
+			MaxConns:   10,
+			Timeout:    Duration(5 * time.Second),
+			SQLitePath: "presentation-timer.db",
+		},
+		Pagination: Pagination{
+			PresentationsPageSize: 20,
+			SectionsPageSize:      20,
+			AuditPageSize:         20,
+			VersionsPageSize:      20,
+		},
+		Auth: Auth{
+			Enabled:    true,
+			SessionTTL: Duration(7 * 24 * time.Hour),
+		},
+		Tasks: Tasks{
+			CleanSectionInterval:  Duration(time.Minute),
+			CleanSessionsInterval: Duration(time.Hour),
+			PurgeTrashInterval:    Duration(time.Hour),
+			TrashRetention:        Duration(30 * 24 * time.Hour),
+		},
+		Log: Log{
+			Level:  "info",
+			Format: "json",
+		},
+	}
+}
+
+// Load builds the configuration for the given command line arguments, it
+// returns the arguments left after parsing the flags.
+func Load(name string, args []string, output io.Writer) (Config, []string, error) {
+	conf := Default()
+
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(output)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s [flags] [migrate up|down|status|version]\n", name)
+		fs.PrintDefaults()
+	}
+
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a JSON configuration file (env CONFIG_FILE)")
+	flagValues := make(map[string]string)
+	for _, opt := range options {
+		usage := fmt.Sprintf("%s (env %s)", opt.usage, opt.env)
+		record := func(value string) error {
+			flagValues[opt.name] = value
+			return nil
+		}
+
+		if opt.value.boolean {
+			fs.BoolFunc(opt.name, usage, record)
+		} else {
+			fs.Func(opt.name, usage, record)
+		}
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, nil, err
+	}
+
+	if *configFile != "" {
+		if err := conf.loadFile(*configFile); err != nil {
+			return Config{}, nil, err
+		}
+	}
+
+	if err := conf.loadEnv(); err != nil {
+		return Config{}, nil, err
+	}
+
+	for _, opt := range options {
+		value, ok := flagValues[opt.name]
+		if !ok {
+			continue
+		}
+
+		if err := opt.value.set(&conf, value); err != nil {
+			return Config{}, nil, fmt.Errorf("flag -%s: %w", opt.name, err)
+		}
+	}
+
+	v := validation.New()
+	conf.Validate(v)
+	if !v.Valid() {
+		return Config{}, nil, validationError(v)
+	}
+
+	return conf, fs.Args(), nil
+}
+
+func (c *Config) loadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	decoder := json.NewDecoder(f)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	for _, opt := range options {
+		value, ok := os.LookupEnv(opt.env)
+		if !ok {
+			continue
+		}
+
+		if err := opt.value.set(c, value); err != nil {
+			return fmt.Errorf("env %s: %w", opt.env, err)
+		}
+	}
+
+	if c.DB.DSN == "" {
+		c.DB.DSN = postgresDSN()
+	}
+
+	return nil
+}
+
+// postgresDSN builds a DSN from the POSTGRES_* variables shared with the
+// database container, it returns an empty string if none is set.
+func postgresDSN() string {
+	keys := [][2]string{
+		{"dbname", "POSTGRES_DB"},
+		{"user", "POSTGRES_USER"},
+		{"password", "POSTGRES_PASSWORD"},
+		{"host", "POSTGRES_HOST"},
+		{"port", "POSTGRES_PORT"},
+		{"sslmode", "POSTGRES_SSLMODE"},
+	}
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+	var parts []string
+	for _, key := range keys {
+		value, ok := os.LookupEnv(key[1])
+		if !ok {
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%s='%s'", key[0], quote.Replace(value)))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (c Config) Validate(v validation.Validator) {
+	v.Check(
+		"port",
+		c.Port,
+		validation.IntCheckPositive("must be positive"),
+		validation.IntCheckMax(65535, "must be at most 65535"),
+	)
+	v.Check("storage", c.Storage, validation.StringCheckIn(Storages, "unknown storage"))
+
+	for key, d := range map[string]Duration{
+		"http.read_timeout":             c.HTTP.ReadTimeout,
+		"http.write_timeout":            c.HTTP.WriteTimeout,
+		"http.idle_timeout":             c.HTTP.IdleTimeout,
+		"http.shutdown_timeout":         c.HTTP.ShutdownTimeout,
+		"db.timeout":                    c.DB.Timeout,
+		"auth.session_ttl":              c.Auth.SessionTTL,
+		"tasks.clean_section_interval":  c.Tasks.CleanSectionInterval,
+		"tasks.clean_sessions_interval": c.Tasks.CleanSessionsInterval,
+		"tasks.purge_trash_interval":    c.Tasks.PurgeTrashInterval,
+		"tasks.trash_retention":         c.Tasks.TrashRetention,
+	} {
+		v.Check(key, time.Duration(d), validation.DurationCheckMin("must be at least 1ms", time.Millisecond))
+	}
+
+	if c.Storage == "postgres" {
+		v.Check("db.dsn", c.DB.DSN, validation.StringCheckNotEmpty("must be given for postgres"))
+		v.Check("db.max_conns", c.DB.MaxConns, validation.IntCheckPositive("must be positive"))
+	}
+	if c.Storage == "sqlite" {
+		v.Check("db.sqlite_path", c.DB.SQLitePath, validation.StringCheckNotEmpty("must be given for sqlite"))
+	}
+
+	for key, size := range map[string]int32{
+		"pagination.presentations_page_size": c.Pagination.PresentationsPageSize,
+		"pagination.sections_page_size":      c.Pagination.SectionsPageSize,
+		"pagination.audit_page_size":         c.Pagination.AuditPageSize,
+		"pagination.versions_page_size":      c.Pagination.VersionsPageSize,
+	} {
+		v.Check(
+			key,
+			size,
+			validation.IntCheckPositive("must be positive"),
+			validation.IntCheckMax(100, "maximum page size is 100"),
+		)
+	}
+
+	v.Check("log.level", c.Log.Level, validation.StringCheckIn(LogLevels, "unknown log level"))
+	v.Check("log.format", c.Log.Format, validation.StringCheckIn(LogFormats, "unknown log format"))
+
+	if c.Metrics.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Metrics.Addr); err != nil {
+			v.AddErrors("metrics.addr", "must be a host:port address")
+		}
+	}
+}
+
+func validationError(v validation.Validator) error {
+	errs := v.Errors()
+
+	keys := make([]string, 0, len(errs))
+	for key := range errs {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	messages := make([]string, 0, len(keys))
+	for _, key := range keys {
+		messages = append(messages, fmt.Sprintf("%s: %s", key, strings.Join(errs[key], ", ")))
+	}
+
+	return errors.New("invalid configuration: " + strings.Join(messages, "; "))
+}
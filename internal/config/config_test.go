@@ -0,0 +1,65 @@
+package config
+
+import (
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestLoadBoolFlags(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		migrate     bool
+		auth        bool
+		allowSignup bool
+		rest        []string
+	}{
+		{
+			name:    "bare flag followed by another flag",
+			args:    []string{"-migrate", "-auth=false", "-storage", "memory"},
+			migrate: true,
+		},
+		{
+			name:        "bare flags before the arguments",
+			args:        []string{"-storage", "memory", "-allow-signup", "-migrate", "migrate", "up"},
+			migrate:     true,
+			auth:        true,
+			allowSignup: true,
+			rest:        []string{"migrate", "up"},
+		},
+		{
+			name: "explicit values",
+			args: []string{"-storage=memory", "-migrate=false", "-auth=true"},
+			auth: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf, rest, err := Load("server", tt.args, io.Discard)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if conf.Migrate != tt.migrate {
+				t.Errorf("migrate is %v, want %v", conf.Migrate, tt.migrate)
+			}
+			if conf.Auth.Enabled != tt.auth {
+				t.Errorf("auth is %v, want %v", conf.Auth.Enabled, tt.auth)
+			}
+			if conf.Auth.AllowSignup != tt.allowSignup {
+				t.Errorf("allow signup is %v, want %v", conf.Auth.AllowSignup, tt.allowSignup)
+			}
+			if !slices.Equal(rest, tt.rest) {
+				t.Errorf("arguments left are %q, want %q", rest, tt.rest)
+			}
+		})
+	}
+}
+
+func TestLoadBoolFlagInvalid(t *testing.T) {
+	if _, _, err := Load("server", []string{"-storage", "memory", "-migrate=maybe"}, io.Discard); err == nil {
+		t.Error("-migrate=maybe was accepted")
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// option is a setting that can be given as an environment variable or flag.
+type option struct {
+	name  string
+	env   string
+	usage string
+	value optionValue
+}
+
+// optionValue parses the value of an option into the configuration, boolean
+// options can be given as a bare flag that means true.
+type optionValue struct {
+	set     func(c *Config, value string) error
+	boolean bool
+}
+
+var options = []option{
+	{"port", "PORT", "port the server listens on", intOption(func(c *Config) *int { return &c.Port })},
+	{"storage", "STORAGE", "storage backend to use (postgres, sqlite, memory)", stringOption(func(c *Config) *string { return &c.Storage })},
+	{"migrate", "MIGRATE", "apply pending migrations before starting the server", boolOption(func(c *Config) *bool { return &c.Migrate })},
+
+	{"http-read-timeout", "HTTP_READ_TIMEOUT", "maximum duration for reading a request", durationOption(func(c *Config) *Duration { return &c.HTTP.ReadTimeout })},
+	{"http-write-timeout", "HTTP_WRITE_TIMEOUT", "maximum duration for writing a response", durationOption(func(c *Config) *Duration { return &c.HTTP.WriteTimeout })},
+	{"http-idle-timeout", "HTTP_IDLE_TIMEOUT", "maximum duration to keep idle connections", durationOption(func(c *Config) *Duration { return &c.HTTP.IdleTimeout })},
+	{"http-shutdown-timeout", "HTTP_SHUTDOWN_TIMEOUT", "maximum duration to wait for requests on shutdown", durationOption(func(c *Config) *Duration { return &c.HTTP.ShutdownTimeout })},
+
+	{"db-dsn", "DATABASE_URL", "postgres connection string, built from POSTGRES_* if not given", stringOption(func(c *Config) *string { return &c.DB.DSN })},
+	{"db-max-conns", "DB_MAX_CONNS", "maximum size of the postgres connection pool", int32Option(func(c *Config) *int32 { return &c.DB.MaxConns })},
+	{"db-timeout", "DB_TIMEOUT", "timeout of database operations", durationOption(func(c *Config) *Duration { return &c.DB.Timeout })},
+	{"sqlite-path", "SQLITE_PATH", "path of the sqlite database file", stringOption(func(c *Config) *string { return &c.DB.SQLitePath })},
+
+	{"presentations-page-size", "PRESENTATIONS_PAGE_SIZE", "default page size when listing presentations", int32Option(func(c *Config) *int32 { return &c.Pagination.PresentationsPageSize })},
+	{"sections-page-size", "SECTIONS_PAGE_SIZE", "default page size when listing sections", int32Option(func(c *Config) *int32 { return &c.Pagination.SectionsPageSize })},
+	{"audit-page-size", "AUDIT_PAGE_SIZE", "default page size when listing audit entries", int32Option(func(c *Config) *int32 { return &c.Pagination.AuditPageSize })},
+	{"versions-page-size", "VERSIONS_PAGE_SIZE", "default page size when listing presentation versions", int32Option(func(c *Config) *int32 { return &c.Pagination.VersionsPageSize })},
+
+	{"auth", "AUTH_ENABLED", "require authentication on the api", boolOption(func(c *Config) *bool { return &c.Auth.Enabled })},
+	{"session-ttl", "SESSION_TTL", "lifetime of the sessions created on login", durationOption(func(c *Config) *Duration { return &c.Auth.SessionTTL })},
+	{"allow-signup", "ALLOW_SIGNUP", "let anyone create an account, the first one can always be created", boolOption(func(c *Config) *bool { return &c.Auth.AllowSignup })},
+
+	{"clean-section-interval", "CLEAN_SECTION_INTERVAL", "interval between section positions clean ups", durationOption(func(c *Config) *Duration { return &c.Tasks.CleanSectionInterval })},
+	{"clean-sessions-interval", "CLEAN_SESSIONS_INTERVAL", "interval between expired sessions clean ups", durationOption(func(c *Config) *Duration { return &c.Tasks.CleanSessionsInterval })},
+	{"purge-trash-interval", "PURGE_TRASH_INTERVAL", "interval between trash purges", durationOption(func(c *Config) *Duration { return &c.Tasks.PurgeTrashInterval })},
+	{"trash-retention", "TRASH_RETENTION", "how long deleted presentations and sections are kept", durationOption(func(c *Config) *Duration { return &c.Tasks.TrashRetention })},
+
+	{"log-level", "LOG_LEVEL", "minimum log level (debug, info, warn, error)", stringOption(func(c *Config) *string { return &c.Log.Level })},
+	{"log-format", "LOG_FORMAT", "log format (json, text)", stringOption(func(c *Config) *string { return &c.Log.Format })},
+
+	{"metrics-addr", "METRICS_ADDR", "address to serve the metrics on, such as 127.0.0.1:9100, they are not served if empty", stringOption(func(c *Config) *string { return &c.Metrics.Addr })},
+}
+
+func stringOption(field func(*Config) *string) optionValue {
+	return optionValue{set: func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}}
+}
+
+func boolOption(field func(*Config) *bool) optionValue {
+	return optionValue{boolean: true, set: func(c *Config, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+
+		*field(c) = b
+		return nil
+	}}
+}
+
+func intOption(field func(*Config) *int) optionValue {
+	return optionValue{set: func(c *Config, value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+
+		*field(c) = i
+		return nil
+	}}
+}
+
+func int32Option(field func(*Config) *int32) optionValue {
+	return optionValue{set: func(c *Config, value string) error {
+		i, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return err
+		}
+
+		*field(c) = int32(i)
+		return nil
+	}}
+}
+
+func durationOption(field func(*Config) *Duration) optionValue {
+	return optionValue{set: func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+
+		*field(c) = Duration(d)
+		return nil
+	}}
+}
+
+// Duration is a time.Duration written as "10s" in configuration files.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var value string
+	if err := json.Unmarshal(b, &value); err != nil {
+		return fmt.Errorf("duration must be a string like \"5s\": %w", err)
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
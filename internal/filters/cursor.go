@@ -0,0 +1,141 @@
+package filters
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+// CursorValues are the values of the sortable fields of a row, a cursor holds
+// them for the row it points to.
+type CursorValues struct {
+	ID        int64         `json:"id"`
+	Name      string        `json:"name,omitempty"`
+	Duration  time.Duration `json:"duration,omitempty"`
+	Position  int16         `json:"position,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+// Cursor points to a row of a list, the page it is used for starts right after
+// the row, or right before it when Backward is set. It is only valid for the
+// sort order it was created with.
+type Cursor struct {
+	CursorValues
+	SortBy   string `json:"sort_by,omitempty"`
+	Backward bool   `json:"backward,omitempty"`
+}
+
+func (c Cursor) Encode() string {
+	encoded, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func DecodeCursor(encoded string) (Cursor, error) {
+	var cursor Cursor
+
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, err
+	}
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return cursor, err
+	}
+
+	return cursor, nil
+}
+
+// FromRequestWithCursor is FromRequest for lists that can also be paginated
+// with the cursors of their PageInfo, given in the cursor query parameter.
+func FromRequestWithCursor(
+	r *http.Request,
+	defaultPageSize int32,
+	safeSortFields ...string,
+) (Filters, validation.Validator) {
+	f, v := FromRequest(r, defaultPageSize, safeSortFields...)
+
+	encoded := r.URL.Query().Get("cursor")
+	if encoded == "" {
+		return f, v
+	}
+
+	cursor, err := DecodeCursor(encoded)
+	switch {
+	case err != nil:
+		v.AddErrors("cursor", "not a valid cursor")
+	case cursor.SortBy != f.SortBy:
+		v.AddErrors("cursor", "cursor was created for a different sort_by")
+	case r.URL.Query().Has("page"):
+		v.AddErrors("page", "can not be used with a cursor")
+	default:
+		f.Cursor = &cursor
+	}
+
+	return f, v
+}
+
+// QueryCursor is the cursor the query starts from, its values are zero when
+// there is none.
+func (f Filters) QueryCursor() (*int64, CursorValues) {
+	if f.Cursor == nil {
+		return nil, CursorValues{}
+	}
+
+	return &f.Cursor.ID, f.Cursor.CursorValues
+}
+
+// QueryIDDirection is the direction of the ID, which breaks the ties left by
+// the sort fields.
+func (f Filters) QueryIDDirection() string {
+	if f.backward() {
+		return "ASC"
+	}
+
+	return "DESC"
+}
+
+// QueryCursorLimit is QueryLimit with an extra row, which tells CursorPage
+// whether there are more rows after the page.
+func (f Filters) QueryCursorLimit() int32 {
+	return f.PageSize + 1
+}
+
+func (f Filters) backward() bool {
+	return f.Cursor != nil && f.Cursor.Backward
+}
+
+// CursorPage turns the rows queried with QueryCursorLimit into a page, setting
+// the cursors of info to the rows before and after it. values returns the
+// cursor values of a row.
+func CursorPage[T any](f Filters, rows []T, info PageInfo, values func(T) CursorValues) ([]T, PageInfo) {
+	more := len(rows) > int(f.PageSize)
+	if more {
+		rows = rows[:f.PageSize]
+	}
+	if f.backward() {
+		// backward pages are queried in the opposite order
+		slices.Reverse(rows)
+	}
+	if len(rows) == 0 {
+		return rows, info
+	}
+
+	hasNext, hasPrev := more, f.Cursor != nil || f.QueryOffset() > 0
+	if f.backward() {
+		hasNext, hasPrev = true, more
+	}
+
+	if hasNext {
+		next := Cursor{CursorValues: values(rows[len(rows)-1]), SortBy: f.SortBy}.Encode()
+		info.NextCursor = &next
+	}
+	if hasPrev {
+		prev := Cursor{CursorValues: values(rows[0]), SortBy: f.SortBy, Backward: true}.Encode()
+		info.PrevCursor = &prev
+	}
+
+	return rows, info
+}
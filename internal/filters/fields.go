@@ -0,0 +1,78 @@
+package filters
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+// FieldFilters narrows down the items of a list, every filter is optional.
+// Search is a full text search whose syntax depends on the store, Postgres
+// accepts the web search syntax while the other stores match every word.
+type FieldFilters struct {
+	NameContains *string
+	MinDuration  *time.Duration
+	MaxDuration  *time.Duration
+	CreatedAfter *time.Time
+	Search       *string
+}
+
+func FieldFiltersFromRequest(r *http.Request) (FieldFilters, validation.Validator) {
+	v := validation.New()
+
+	minDuration, err := helpers.QueryDuration(r, "min_duration")
+	if err != nil {
+		v.AddErrors("min_duration", "not a valid duration")
+	}
+
+	maxDuration, err := helpers.QueryDuration(r, "max_duration")
+	if err != nil {
+		v.AddErrors("max_duration", "not a valid duration")
+	}
+
+	createdAfter, err := helpers.QueryTime(r, "created_after")
+	if err != nil {
+		v.AddErrors("created_after", "not a valid RFC 3339 time")
+	}
+
+	result := FieldFilters{
+		NameContains: helpers.QueryString(r, "name_contains"),
+		MinDuration:  minDuration,
+		MaxDuration:  maxDuration,
+		CreatedAfter: createdAfter,
+		Search:       helpers.QueryString(r, "search"),
+	}
+	result.Validate(v)
+
+	return result, v
+}
+
+func (f FieldFilters) Validate(v validation.Validator) {
+	if f.NameContains != nil {
+		v.Check(
+			"name_contains",
+			f.NameContains,
+			validation.StringCheckNotEmpty("can not be empty"),
+			validation.StringCheckMaxLen(50, "maximum length is 50 characters"),
+		)
+	}
+	if f.Search != nil {
+		v.Check(
+			"search",
+			f.Search,
+			validation.StringCheckNotEmpty("can not be empty"),
+			validation.StringCheckMaxLen(200, "maximum length is 200 characters"),
+		)
+	}
+	if f.MinDuration != nil {
+		v.Check("min_duration", f.MinDuration, validation.DurationCheckPositive("can not be negative"))
+	}
+	if f.MaxDuration != nil {
+		v.Check("max_duration", f.MaxDuration, validation.DurationCheckPositive("can not be negative"))
+	}
+	if f.MinDuration != nil && f.MaxDuration != nil && *f.MinDuration > *f.MaxDuration {
+		v.AddErrors("min_duration", "can not be greater than max_duration")
+	}
+}
@@ -1,6 +1,7 @@
 package filters
 
 import (
+	"fmt"
 	"math"
 	"net/http"
 	"strings"
@@ -9,16 +10,23 @@ import (
 	"github.com/PabloVarg/presentation-timer/internal/validation"
 )
 
+// MaxSortFields is how many comma separated fields sort_by can hold.
+const MaxSortFields = 3
+
 type Filters struct {
 	Page           int32
 	PageSize       int32
 	SortBy         string
 	SafeSortFields []string
+	// Cursor is set when paginating with a cursor instead of a page number.
+	Cursor *Cursor
 }
 
 type PageInfo struct {
-	TotalPages int64 `json:"total_pages"`
-	TotalItems int64 `json:"total_items"`
+	TotalPages int64   `json:"total_pages"`
+	TotalItems int64   `json:"total_items"`
+	NextCursor *string `json:"next_cursor,omitempty"`
+	PrevCursor *string `json:"prev_cursor,omitempty"`
 }
 
 func FromRequest(r *http.Request, defaultPageSize int32, safeSortFields ...string) (Filters, validation.Validator) {
@@ -61,11 +69,40 @@ func (f Filters) Validate(v validation.Validator) {
 	if f.SortBy == "" {
 		return
 	}
-	v.Check(
-		"sort_by",
-		strings.TrimPrefix(f.SortBy, "-"),
-		validation.StringCheckIn(f.SafeSortFields, "invalid value"),
-	)
+
+	fields := f.SortFields()
+	if len(fields) > MaxSortFields {
+		v.AddErrors("sort_by", fmt.Sprintf("at most %d fields can be given", MaxSortFields))
+		return
+	}
+
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		field = strings.TrimPrefix(field, "-")
+
+		v.Check(
+			"sort_by",
+			field,
+			validation.StringCheckIn(f.SafeSortFields, fmt.Sprintf("invalid value %q", field)),
+		)
+		if seen[field] {
+			v.AddErrors("sort_by", fmt.Sprintf("%q is repeated", field))
+		}
+		seen[field] = true
+	}
+}
+
+// SortFields splits SortBy into its fields, each of them prefixed with - when
+// sorting in descending order.
+func (f Filters) SortFields() []string {
+	var fields []string
+	for _, field := range strings.Split(f.SortBy, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
 }
 
 func (f Filters) PageInfo(totalRows int64) PageInfo {
@@ -80,17 +117,34 @@ func (f Filters) QueryLimit() int32 {
 }
 
 func (f Filters) QueryOffset() int32 {
+	if f.Cursor != nil {
+		return 0
+	}
+
 	return (f.Page - 1) * f.PageSize
 }
 
-func (f Filters) QuerySortDirection() string {
-	if strings.HasPrefix(f.SortBy, "-") {
+// QuerySortDirection is the direction of the i-th sort field, counting from
+// 0. It is reversed when paginating backward from a cursor.
+func (f Filters) QuerySortDirection(i int) string {
+	descending := false
+	if fields := f.SortFields(); i < len(fields) {
+		descending = strings.HasPrefix(fields[i], "-")
+	}
+	if descending != f.backward() {
 		return "DESC"
 	}
 
 	return "ASC"
 }
 
-func (f Filters) QuerySortBy() string {
-	return strings.TrimPrefix(f.SortBy, "-")
+// QuerySortBy is the i-th sort field counting from 0, it is empty when less
+// fields were given.
+func (f Filters) QuerySortBy(i int) string {
+	fields := f.SortFields()
+	if i >= len(fields) {
+		return ""
+	}
+
+	return strings.TrimPrefix(fields[i], "-")
 }
@@ -0,0 +1,89 @@
+package filters
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+// Fieldset is the shape requested for a resource. Fields are the members to
+// return, every member when empty, and Include the related resources to embed
+// in it. Included members are always returned.
+type Fieldset struct {
+	Fields  []string
+	Include []string
+}
+
+func FieldsetFromRequest(r *http.Request, safeFields []string, safeIncludes []string) (Fieldset, validation.Validator) {
+	v := validation.New()
+
+	result := Fieldset{
+		Fields:  splitList(r.URL.Query().Get("fields")),
+		Include: splitList(r.URL.Query().Get("include")),
+	}
+	result.Validate(v, safeFields, safeIncludes)
+
+	return result, v
+}
+
+func (f Fieldset) Validate(v validation.Validator, safeFields []string, safeIncludes []string) {
+	for _, field := range f.Fields {
+		v.Check("fields", field, validation.StringCheckIn(safeFields, fmt.Sprintf("invalid value %q", field)))
+	}
+	for _, include := range f.Include {
+		v.Check("include", include, validation.StringCheckIn(safeIncludes, fmt.Sprintf("invalid value %q", include)))
+	}
+}
+
+// Includes reports whether the related resource name was asked to be embedded.
+func (f Fieldset) Includes(name string) bool {
+	return slices.Contains(f.Include, name)
+}
+
+// Default reports whether the resource is returned as it is, without
+// selecting fields or embedding anything.
+func (f Fieldset) Default() bool {
+	return len(f.Fields) == 0 && len(f.Include) == 0
+}
+
+// Select converts value to its JSON members and keeps the requested ones,
+// embedded is added to them keyed by the name of the included resource.
+func (f Fieldset) Select(value any, embedded map[string]any) (map[string]any, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var members map[string]any
+	if err := json.Unmarshal(encoded, &members); err != nil {
+		return nil, err
+	}
+
+	if len(f.Fields) != 0 {
+		for key := range members {
+			if !slices.Contains(f.Fields, key) {
+				delete(members, key)
+			}
+		}
+	}
+	for key, value := range embedded {
+		members[key] = value
+	}
+
+	return members, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" && !slices.Contains(items, item) {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
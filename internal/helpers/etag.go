@@ -0,0 +1,65 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ETag returns the entity tag of a resource at version.
+func ETag(version int32) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ETagMatch reports whether etag is in the list of an If-Match or
+// If-None-Match header. Weak tags are only equal to each other when weak is
+// set, as If-None-Match compares them.
+func ETagMatch(header, etag string, weak bool) bool {
+	if weak {
+		etag = strings.TrimPrefix(etag, "W/")
+	} else if strings.HasPrefix(etag, "W/") {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if weak {
+			tag = strings.TrimPrefix(tag, "W/")
+		}
+
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriteJSONETag is WriteJSON for GET responses, it sets the ETag header and
+// writes 304 Not Modified without a body when it matches If-None-Match. When
+// etag is empty a weak one is derived from the body.
+func WriteJSONETag(w http.ResponseWriter, r *http.Request, status int, data any, etag string) error {
+	res, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	if etag == "" {
+		sum := sha256.Sum256(res)
+		etag = fmt.Sprintf(`W/"%s"`, hex.EncodeToString(sum[:16]))
+	}
+	w.Header().Set("ETag", etag)
+
+	if header := r.Header.Get("If-None-Match"); header != "" && ETagMatch(header, etag, true) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(res)
+	return nil
+}
@@ -1,8 +1,12 @@
 package helpers
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/validation"
 )
 
 func QueryInt32(r *http.Request, key string, defaultValue int32) (int32, error) {
@@ -17,3 +21,54 @@ func QueryInt32(r *http.Request, key string, defaultValue int32) (int32, error)
 
 	return int32(intValue), nil
 }
+
+func QueryBool(r *http.Request, key string, defaultValue bool) (bool, validation.Validator) {
+	v := validation.New()
+
+	if r.URL.Query().Get(key) == "" {
+		return defaultValue, v
+	}
+
+	value, err := strconv.ParseBool(r.URL.Query().Get(key))
+	if err != nil {
+		v.AddErrors(key, fmt.Sprintf("%s must be true or false", key))
+		return false, v
+	}
+
+	return value, v
+}
+
+func QueryString(r *http.Request, key string) *string {
+	if !r.URL.Query().Has(key) {
+		return nil
+	}
+
+	value := r.URL.Query().Get(key)
+	return &value
+}
+
+func QueryDuration(r *http.Request, key string) (*time.Duration, error) {
+	if r.URL.Query().Get(key) == "" {
+		return nil, nil
+	}
+
+	duration, err := time.ParseDuration(r.URL.Query().Get(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return &duration, nil
+}
+
+func QueryTime(r *http.Request, key string) (*time.Time, error) {
+	if r.URL.Query().Get(key) == "" {
+		return nil, nil
+	}
+
+	value, err := time.Parse(time.RFC3339, r.URL.Query().Get(key))
+	if err != nil {
+		return nil, err
+	}
+
+	return &value, nil
+}
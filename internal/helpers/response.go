@@ -20,6 +20,19 @@ func BadRequest(w http.ResponseWriter, message string) {
 	})
 }
 
+func Unauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	WriteJSON(w, http.StatusUnauthorized, ErrorResponse{
+		Error: message,
+	})
+}
+
+func Forbidden(w http.ResponseWriter, message string) {
+	WriteJSON(w, http.StatusForbidden, ErrorResponse{
+		Error: message,
+	})
+}
+
 func UnprocessableContent(w http.ResponseWriter, messages map[string][]string) {
 	WriteJSON(w, http.StatusUnprocessableEntity, UnprocessableErrorResponse{
 		ErrorResponse: ErrorResponse{
@@ -35,3 +48,9 @@ func InternalError(w http.ResponseWriter, logger *slog.Logger, err error) {
 		Error: "an error has ocurred",
 	})
 }
+
+func PreconditionFailed(w http.ResponseWriter, message string) {
+	WriteJSON(w, http.StatusPreconditionFailed, ErrorResponse{
+		Error: message,
+	})
+}
@@ -0,0 +1,127 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log/slog"
+
+	"github.com/PabloVarg/presentation-timer/migrations"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+// Migrator applies the embedded migrations of a storage backend.
+type Migrator struct {
+	logger   *slog.Logger
+	provider *goose.Provider
+}
+
+// NewPostgres returns a Migrator for postgres, it takes an advisory lock while
+// migrating so replicas starting at the same time don't race each other.
+func NewPostgres(logger *slog.Logger, db *sql.DB) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	return newMigrator(
+		logger,
+		goose.DialectPostgres,
+		db,
+		migrations.Postgres,
+		goose.WithSessionLocker(locker),
+	)
+}
+
+func NewSQLite(logger *slog.Logger, db *sql.DB) (*Migrator, error) {
+	fsys, err := fs.Sub(migrations.SQLite, "sqlite")
+	if err != nil {
+		return nil, err
+	}
+
+	return newMigrator(logger, goose.DialectSQLite3, db, fsys)
+}
+
+func newMigrator(
+	logger *slog.Logger,
+	dialect goose.Dialect,
+	db *sql.DB,
+	fsys fs.FS,
+	opts ...goose.ProviderOption,
+) (*Migrator, error) {
+	provider, err := goose.NewProvider(dialect, db, fsys, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		logger:   logger,
+		provider: provider,
+	}, nil
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	results, err := m.provider.Up(ctx)
+	m.logResults(results...)
+
+	return err
+}
+
+// Down rolls back the last applied migration.
+func (m *Migrator) Down(ctx context.Context) error {
+	result, err := m.provider.Down(ctx)
+	if result != nil {
+		m.logResults(result)
+	}
+
+	return err
+}
+
+// Status reports every known migration and whether it has been applied.
+func (m *Migrator) Status(ctx context.Context) ([]string, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(statuses))
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.State == goose.StateApplied {
+			appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+
+		lines = append(lines, fmt.Sprintf("%-19s %s", appliedAt, status.Source.Path))
+	}
+
+	return lines, nil
+}
+
+// Version returns the version of the last applied migration.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	return m.provider.GetDBVersion(ctx)
+}
+
+func (m *Migrator) logResults(results ...*goose.MigrationResult) {
+	for _, result := range results {
+		if result.Error != nil {
+			m.logger.Error(
+				"migration failed",
+				"migration", result.Source.Path,
+				"direction", result.Direction,
+				"err", result.Error,
+			)
+			continue
+		}
+
+		m.logger.Info(
+			"migration applied",
+			"migration", result.Source.Path,
+			"direction", result.Direction,
+			"duration", result.Duration,
+		)
+	}
+}
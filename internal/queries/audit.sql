@@ -0,0 +1,30 @@
+-- name: CreateAuditEntry :exec
+INSERT INTO audit_entry (
+    presentation,
+    section,
+    actor,
+    action,
+    diff
+) VALUES (
+    @presentation,
+    sqlc.narg(section),
+    sqlc.narg(actor),
+    @action,
+    @diff
+);
+--
+-- name: GetAuditEntries :many
+select audit_entry.*, account.username actor_username
+from audit_entry
+left join account on account.id = audit_entry.actor
+where audit_entry.presentation = @presentation_id
+order by audit_entry.id desc
+limit @query_limit
+offset @query_offset
+;
+--
+-- name: GetAuditEntriesMetadata :one
+select count(*)
+from audit_entry
+where presentation = @presentation_id
+;
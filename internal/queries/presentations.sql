@@ -1,51 +1,283 @@
 -- name: GetPresentations :many
 select presentation.*, coalesce(sum(section.duration), '0 seconds')::interval duration
 from presentation
-left join section on presentation.id = section.presentation
+left join section on presentation.id = section.presentation and section.deleted_at is null
+where presentation.deleted_at is null and presentation.workspace = @workspace and (
+    sqlc.narg(account)::bigint is null
+    or (
+        not @shared_with_me::bool
+        and presentation.owner = sqlc.narg(account)
+    )
+    or exists (
+        select 1
+        from presentation_grant
+        where presentation_grant.presentation = presentation.id
+            and presentation_grant.account = sqlc.narg(account)
+    )
+) and (
+    sqlc.narg(name_contains)::text is null
+    or strpos(lower(presentation.name), lower(sqlc.narg(name_contains))) > 0
+) and (
+    sqlc.narg(created_after)::timestamptz is null
+    or presentation.created_at > sqlc.narg(created_after)
+) and (
+    sqlc.narg(search)::text is null
+    or to_tsvector('simple', presentation.name) @@ websearch_to_tsquery('simple', sqlc.narg(search))
+    or exists (
+        select 1
+        from section searched
+        where searched.presentation = presentation.id and searched.deleted_at is null
+            and to_tsvector('simple', searched.name) @@ websearch_to_tsquery('simple', sqlc.narg(search))
+    )
+)
 group by presentation.id
+having (
+    sqlc.narg(min_duration)::interval is null
+    or coalesce(sum(section.duration), '0 seconds') >= sqlc.narg(min_duration)
+) and (
+    sqlc.narg(max_duration)::interval is null
+    or coalesce(sum(section.duration), '0 seconds') <= sqlc.narg(max_duration)
+) and (
+    sqlc.narg(cursor_id)::bigint is null
+    or (
+        case @sort_by_1::text
+            when 'name' then case when presentation.name > @cursor_name::text then 1 when presentation.name < @cursor_name::text then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), '0 seconds') > @cursor_duration::interval then 1 when coalesce(sum(section.duration), '0 seconds') < @cursor_duration::interval then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > @cursor_created_at::timestamptz then 1 when presentation.created_at < @cursor_created_at::timestamptz then -1 else 0 end
+            else 0
+        end * case when @direction_1::text = 'DESC' then -1 else 1 end,
+        case @sort_by_2::text
+            when 'name' then case when presentation.name > @cursor_name::text then 1 when presentation.name < @cursor_name::text then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), '0 seconds') > @cursor_duration::interval then 1 when coalesce(sum(section.duration), '0 seconds') < @cursor_duration::interval then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > @cursor_created_at::timestamptz then 1 when presentation.created_at < @cursor_created_at::timestamptz then -1 else 0 end
+            else 0
+        end * case when @direction_2::text = 'DESC' then -1 else 1 end,
+        case @sort_by_3::text
+            when 'name' then case when presentation.name > @cursor_name::text then 1 when presentation.name < @cursor_name::text then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), '0 seconds') > @cursor_duration::interval then 1 when coalesce(sum(section.duration), '0 seconds') < @cursor_duration::interval then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > @cursor_created_at::timestamptz then 1 when presentation.created_at < @cursor_created_at::timestamptz then -1 else 0 end
+            else 0
+        end * case when @direction_3::text = 'DESC' then -1 else 1 end,
+        (case when presentation.id > sqlc.narg(cursor_id) then 1 when presentation.id < sqlc.narg(cursor_id) then -1 else 0 end)
+            * case when @id_direction::text = 'DESC' then -1 else 1 end
+    ) > (0, 0, 0, 0)
+)
 order by
     case
-        when @direction::text = 'ASC' and @sort_by::text = 'name' then presentation.name
+        when @direction_1::text = 'ASC' and @sort_by_1::text = 'name'
+        then presentation.name
+    end asc,
+    case
+        when @direction_1::text = 'DESC' and @sort_by_1::text = 'name'
+        then presentation.name
+    end desc,
+    case
+        when @direction_1::text = 'ASC' and @sort_by_1::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end asc,
+    case
+        when @direction_1::text = 'DESC' and @sort_by_1::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end desc,
+    case
+        when @direction_1::text = 'ASC' and @sort_by_1::text = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when @direction_1::text = 'DESC' and @sort_by_1::text = 'created_at'
+        then presentation.created_at
+    end desc,
+    case
+        when @direction_2::text = 'ASC' and @sort_by_2::text = 'name'
+        then presentation.name
+    end asc,
+    case
+        when @direction_2::text = 'DESC' and @sort_by_2::text = 'name'
+        then presentation.name
+    end desc,
+    case
+        when @direction_2::text = 'ASC' and @sort_by_2::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end asc,
+    case
+        when @direction_2::text = 'DESC' and @sort_by_2::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end desc,
+    case
+        when @direction_2::text = 'ASC' and @sort_by_2::text = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when @direction_2::text = 'DESC' and @sort_by_2::text = 'created_at'
+        then presentation.created_at
+    end desc,
+    case
+        when @direction_3::text = 'ASC' and @sort_by_3::text = 'name'
+        then presentation.name
     end asc,
     case
-        when @direction::text = 'DESC' and @sort_by::text = 'name'
+        when @direction_3::text = 'DESC' and @sort_by_3::text = 'name'
         then presentation.name
     end desc,
-    presentation.id desc
+    case
+        when @direction_3::text = 'ASC' and @sort_by_3::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end asc,
+    case
+        when @direction_3::text = 'DESC' and @sort_by_3::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end desc,
+    case
+        when @direction_3::text = 'ASC' and @sort_by_3::text = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when @direction_3::text = 'DESC' and @sort_by_3::text = 'created_at'
+        then presentation.created_at
+    end desc,
+    case when @id_direction::text = 'ASC' then presentation.id end asc,
+    case when @id_direction::text = 'DESC' then presentation.id end desc
 limit @query_limit
 offset @query_offset
 ;
 --
 -- name: GetPresentationsMetadata :one
 select count(*)
-from presentation
+from (
+    select presentation.id
+    from presentation
+    left join section on presentation.id = section.presentation and section.deleted_at is null
+    where presentation.deleted_at is null and presentation.workspace = @workspace and (
+        sqlc.narg(account)::bigint is null
+        or (
+            not @shared_with_me::bool
+            and presentation.owner = sqlc.narg(account)
+        )
+        or exists (
+            select 1
+            from presentation_grant
+            where presentation_grant.presentation = presentation.id
+                and presentation_grant.account = sqlc.narg(account)
+        )
+    ) and (
+        sqlc.narg(name_contains)::text is null
+        or strpos(lower(presentation.name), lower(sqlc.narg(name_contains))) > 0
+    ) and (
+        sqlc.narg(created_after)::timestamptz is null
+        or presentation.created_at > sqlc.narg(created_after)
+    ) and (
+        sqlc.narg(search)::text is null
+        or to_tsvector('simple', presentation.name) @@ websearch_to_tsquery('simple', sqlc.narg(search))
+        or exists (
+            select 1
+            from section searched
+            where searched.presentation = presentation.id and searched.deleted_at is null
+                and to_tsvector('simple', searched.name) @@ websearch_to_tsquery('simple', sqlc.narg(search))
+        )
+    )
+    group by presentation.id
+    having (
+        sqlc.narg(min_duration)::interval is null
+        or coalesce(sum(section.duration), '0 seconds') >= sqlc.narg(min_duration)
+    ) and (
+        sqlc.narg(max_duration)::interval is null
+        or coalesce(sum(section.duration), '0 seconds') <= sqlc.narg(max_duration)
+    )
+) filtered
 ;
 --
 -- name: GetPresentation :one
 select *
 from presentation
-where id = @id
+where id = @id and deleted_at is null
+;
+--
+-- name: LockPresentation :one
+select *
+from presentation
+where id = @id and deleted_at is null for update
 ;
 --
 -- name: CreatePresentation :one
 INSERT INTO presentation(
-    name
+    workspace,
+    name,
+    owner
 ) VALUES (
-    @name
+    @workspace,
+    @name,
+    sqlc.narg(owner)
 )
 RETURNING *;
 --
 -- name: UpdatePresentation :execrows
 UPDATE presentation
-SET name = @name
-WHERE id = @id;
+SET name = @name, version = version + 1
+WHERE id = @id and deleted_at is null;
 --
 -- name: PatchPresentation :execrows
 UPDATE presentation
-SET name = COALESCE(sqlc.narg('name'), name)
-WHERE id = @id;
+SET name = COALESCE(sqlc.narg('name'), name), version = version + 1
+WHERE id = @id and deleted_at is null;
 --
 -- name: DeletePresentation :execrows
+update presentation
+set deleted_at = @now::timestamptz, version = version + 1
+where id = @id and deleted_at is null
+;
+--
+-- name: RestorePresentation :execrows
+update presentation
+set deleted_at = null, version = version + 1
+where id = @id and deleted_at is not null
+;
+--
+-- name: GetDeletedPresentations :many
+select *
+from presentation
+where presentation.deleted_at is not null and presentation.workspace = @workspace and (
+    sqlc.narg(account)::bigint is null
+    or presentation.owner = sqlc.narg(account)
+)
+order by presentation.deleted_at desc, presentation.id desc
+;
+--
+-- name: PurgePresentations :execrows
 delete from presentation
-where id = @id
+where deleted_at < @before::timestamptz
+;
+--
+-- name: GetPresentationAccess :one
+select presentation.owner, presentation.deleted_at, presentation_grant.role
+from presentation
+left join presentation_grant
+    on presentation_grant.presentation = presentation.id
+    and presentation_grant.account = @account
+where presentation.id = @presentation_id and presentation.workspace = @workspace
+;
+--
+-- name: GetGrants :many
+select presentation_grant.account, account.username, presentation_grant.role
+from presentation_grant
+inner join account on account.id = presentation_grant.account
+where presentation_grant.presentation = @presentation_id
+order by account.username
+;
+--
+-- name: UpsertGrant :exec
+INSERT INTO presentation_grant (
+    presentation,
+    account,
+    role
+) VALUES (
+    @presentation,
+    @account,
+    @role
+)
+ON CONFLICT (presentation, account) DO UPDATE
+SET role = excluded.role;
+--
+-- name: DeleteGrant :execrows
+delete from presentation_grant
+where presentation = @presentation and account = @account
 ;
@@ -1,23 +1,145 @@
 -- name: GetSections :many
 select *
 from section
-where presentation = @presentation_id
+where presentation = @presentation_id and deleted_at is null and (
+    sqlc.narg(name_contains)::text is null
+    or strpos(lower(name), lower(sqlc.narg(name_contains))) > 0
+) and (
+    sqlc.narg(min_duration)::interval is null or duration >= sqlc.narg(min_duration)
+) and (
+    sqlc.narg(max_duration)::interval is null or duration <= sqlc.narg(max_duration)
+) and (
+    sqlc.narg(created_after)::timestamptz is null or created_at > sqlc.narg(created_after)
+) and (
+    sqlc.narg(search)::text is null
+    or to_tsvector('simple', name) @@ websearch_to_tsquery('simple', sqlc.narg(search))
+) and (
+    sqlc.narg(cursor_id)::bigint is null
+    or (
+        case @sort_by_1::text
+            when 'name' then case when name > @cursor_name::text then 1 when name < @cursor_name::text then -1 else 0 end
+            when 'duration' then case when duration > @cursor_duration::interval then 1 when duration < @cursor_duration::interval then -1 else 0 end
+            when 'position' then case when position > @cursor_position::smallint then 1 when position < @cursor_position::smallint then -1 else 0 end
+            when 'created_at' then case when created_at > @cursor_created_at::timestamptz then 1 when created_at < @cursor_created_at::timestamptz then -1 else 0 end
+            else 0
+        end * case when @direction_1::text = 'DESC' then -1 else 1 end,
+        case @sort_by_2::text
+            when 'name' then case when name > @cursor_name::text then 1 when name < @cursor_name::text then -1 else 0 end
+            when 'duration' then case when duration > @cursor_duration::interval then 1 when duration < @cursor_duration::interval then -1 else 0 end
+            when 'position' then case when position > @cursor_position::smallint then 1 when position < @cursor_position::smallint then -1 else 0 end
+            when 'created_at' then case when created_at > @cursor_created_at::timestamptz then 1 when created_at < @cursor_created_at::timestamptz then -1 else 0 end
+            else 0
+        end * case when @direction_2::text = 'DESC' then -1 else 1 end,
+        case @sort_by_3::text
+            when 'name' then case when name > @cursor_name::text then 1 when name < @cursor_name::text then -1 else 0 end
+            when 'duration' then case when duration > @cursor_duration::interval then 1 when duration < @cursor_duration::interval then -1 else 0 end
+            when 'position' then case when position > @cursor_position::smallint then 1 when position < @cursor_position::smallint then -1 else 0 end
+            when 'created_at' then case when created_at > @cursor_created_at::timestamptz then 1 when created_at < @cursor_created_at::timestamptz then -1 else 0 end
+            else 0
+        end * case when @direction_3::text = 'DESC' then -1 else 1 end,
+        (case when id > sqlc.narg(cursor_id) then 1 when id < sqlc.narg(cursor_id) then -1 else 0 end)
+            * case when @id_direction::text = 'DESC' then -1 else 1 end
+    ) > (0, 0, 0, 0)
+)
 order by
-    case when @direction::text = 'ASC' and @sort_by::text = 'name' then name end asc,
-    case when @direction::text = 'DESC' and @sort_by::text = 'name' then name end desc,
     case
-        when @direction::text = 'ASC' and @sort_by::text = 'duration' then duration
+        when @direction_1::text = 'ASC' and @sort_by_1::text = 'name'
+        then name
+    end asc,
+    case
+        when @direction_1::text = 'DESC' and @sort_by_1::text = 'name'
+        then name
+    end desc,
+    case
+        when @direction_1::text = 'ASC' and @sort_by_1::text = 'duration'
+        then duration
+    end asc,
+    case
+        when @direction_1::text = 'DESC' and @sort_by_1::text = 'duration'
+        then duration
+    end desc,
+    case
+        when @direction_1::text = 'ASC' and @sort_by_1::text = 'position'
+        then position
+    end asc,
+    case
+        when @direction_1::text = 'DESC' and @sort_by_1::text = 'position'
+        then position
+    end desc,
+    case
+        when @direction_1::text = 'ASC' and @sort_by_1::text = 'created_at'
+        then created_at
+    end asc,
+    case
+        when @direction_1::text = 'DESC' and @sort_by_1::text = 'created_at'
+        then created_at
+    end desc,
+    case
+        when @direction_2::text = 'ASC' and @sort_by_2::text = 'name'
+        then name
+    end asc,
+    case
+        when @direction_2::text = 'DESC' and @sort_by_2::text = 'name'
+        then name
+    end desc,
+    case
+        when @direction_2::text = 'ASC' and @sort_by_2::text = 'duration'
+        then duration
+    end asc,
+    case
+        when @direction_2::text = 'DESC' and @sort_by_2::text = 'duration'
+        then duration
+    end desc,
+    case
+        when @direction_2::text = 'ASC' and @sort_by_2::text = 'position'
+        then position
+    end asc,
+    case
+        when @direction_2::text = 'DESC' and @sort_by_2::text = 'position'
+        then position
+    end desc,
+    case
+        when @direction_2::text = 'ASC' and @sort_by_2::text = 'created_at'
+        then created_at
     end asc,
     case
-        when @direction::text = 'DESC' and @sort_by::text = 'duration' then duration
+        when @direction_2::text = 'DESC' and @sort_by_2::text = 'created_at'
+        then created_at
     end desc,
     case
-        when @direction::text = 'ASC' and @sort_by::text = 'position' then position
+        when @direction_3::text = 'ASC' and @sort_by_3::text = 'name'
+        then name
     end asc,
     case
-        when @direction::text = 'DESC' and @sort_by::text = 'position' then position
+        when @direction_3::text = 'DESC' and @sort_by_3::text = 'name'
+        then name
     end desc,
-    id desc
+    case
+        when @direction_3::text = 'ASC' and @sort_by_3::text = 'duration'
+        then duration
+    end asc,
+    case
+        when @direction_3::text = 'DESC' and @sort_by_3::text = 'duration'
+        then duration
+    end desc,
+    case
+        when @direction_3::text = 'ASC' and @sort_by_3::text = 'position'
+        then position
+    end asc,
+    case
+        when @direction_3::text = 'DESC' and @sort_by_3::text = 'position'
+        then position
+    end desc,
+    case
+        when @direction_3::text = 'ASC' and @sort_by_3::text = 'created_at'
+        then created_at
+    end asc,
+    case
+        when @direction_3::text = 'DESC' and @sort_by_3::text = 'created_at'
+        then created_at
+    end desc,
+    case when @id_direction::text = 'ASC' then id end asc,
+    case when @id_direction::text = 'DESC' then id end desc
 limit @query_limit
 offset @query_offset
 ;
@@ -25,13 +147,31 @@ offset @query_offset
 -- name: GetSectionsMetadata :one
 select count(*)
 from section
-where presentation = @presentation_id
+where presentation = @presentation_id and deleted_at is null and (
+    sqlc.narg(name_contains)::text is null
+    or strpos(lower(name), lower(sqlc.narg(name_contains))) > 0
+) and (
+    sqlc.narg(min_duration)::interval is null or duration >= sqlc.narg(min_duration)
+) and (
+    sqlc.narg(max_duration)::interval is null or duration <= sqlc.narg(max_duration)
+) and (
+    sqlc.narg(created_after)::timestamptz is null or created_at > sqlc.narg(created_after)
+) and (
+    sqlc.narg(search)::text is null
+    or to_tsvector('simple', name) @@ websearch_to_tsquery('simple', sqlc.narg(search))
+)
 ;
 --
 -- name: GetSection :one
 select *
 from section
-where id = @id
+where id = @id and deleted_at is null
+;
+--
+-- name: LockSection :one
+select *
+from section
+where id = @id and deleted_at is null for update
 ;
 --
 -- name: CreateSection :one
@@ -52,28 +192,68 @@ UPDATE section
 SET
     name = @name,
     duration = @duration,
-    position = @position
+    position = @position,
+    version = version + 1
 WHERE
-    id = @id;
+    id = @id and deleted_at is null;
 --
 -- name: PatchSection :execrows
 UPDATE section
 SET
     name = COALESCE(sqlc.narg(name), name),
     duration = COALESCE(sqlc.narg(duration), duration),
-    position = COALESCE(sqlc.narg(position), position)
+    position = COALESCE(sqlc.narg(position), position),
+    version = version + 1
 WHERE
-    id = @id;
+    id = @id and deleted_at is null;
 --
 -- name: DeleteSection :execrows
+update section
+set deleted_at = @now::timestamptz, version = version + 1
+where id = @id and deleted_at is null
+;
+--
+-- name: GetDeletedSection :one
+select *
+from section
+where id = @id and deleted_at is not null
+;
+--
+-- name: RestoreSection :execrows
+update section
+set deleted_at = null, position = @position, version = version + 1
+where id = @id and deleted_at is not null
+;
+--
+-- name: GetDeletedSections :many
+select section.*
+from section
+inner join presentation on presentation.id = section.presentation
+where section.deleted_at is not null
+    and presentation.deleted_at is null
+    and presentation.workspace = @workspace
+    and (
+        sqlc.narg(account)::bigint is null
+        or presentation.owner = sqlc.narg(account)
+        or exists (
+            select 1
+            from presentation_grant
+            where presentation_grant.presentation = presentation.id
+                and presentation_grant.account = sqlc.narg(account)
+        )
+    )
+order by section.deleted_at desc, section.id desc
+;
+--
+-- name: PurgeSections :execrows
 delete from section
-where id = @id
+where deleted_at < @before::timestamptz
 ;
 --
 -- name: MaxPosition :one
 select coalesce(max(position), 0)::smallint
 from section
-where presentation = @presentation_id
+where presentation = @presentation_id and deleted_at is null
 ;
 --
 -- name: CleanPositions :exec
@@ -86,19 +266,18 @@ with
         select id, row_number() over (order by position) as new_position
         from section o
         where o.presentation = (select i.presentation from section i where i.id = @id)
+            and o.deleted_at is null
     )
     update section
-    set position = ordered.new_position
+    set position = ordered.new_position, version = section.version + 1
 from ordered
-where section.id = ordered.id
+where section.id = ordered.id and section.position <> ordered.new_position
 ;
 --
--- name: MoveSection :exec
-update section s
-set position = case when s.id <> $1 then position - ($2::int / abs($2)) when id = $1 then position + $2 end
-where position between least(position + $2, position) and greatest(position + $2, position) and presentation = (
-    select sp.presentation from section sp where sp.id = $1
-)
+-- name: SetSectionPosition :exec
+update section
+set position = @position, version = version + 1
+where id = @id and deleted_at is null and position <> @position
 ;
 --
 -- name: GetSectionsByPosition :many
@@ -106,7 +285,7 @@ with
     ordered as (
         select s.id, row_number() over (order by s.position) as new_position
         from section s
-        where s.presentation = @presentation_id
+        where s.presentation = @presentation_id and s.deleted_at is null
     )
 select o.*
 from section o
@@ -114,3 +293,10 @@ inner join ordered ord on ord.id = o.id
 where o.presentation = @presentation_id
 order by ord.new_position
 ;
+--
+-- name: GetSectionsOfPresentations :many
+select *
+from section
+where presentation = any(@presentation_ids::bigint[]) and deleted_at is null
+order by presentation, position, id
+;
@@ -0,0 +1,31 @@
+-- name: CreateShareLink :one
+INSERT INTO share_link (
+    presentation,
+    token_hash,
+    expires_at
+) VALUES (
+    @presentation,
+    @token_hash,
+    sqlc.narg(expires_at)
+) RETURNING *;
+--
+-- name: GetShareLinks :many
+select *
+from share_link
+where presentation = @presentation_id
+order by id
+;
+--
+-- name: DeleteShareLink :execrows
+delete from share_link
+where id = @id and presentation = @presentation
+;
+--
+-- name: GetValidShareLink :one
+select share_link.*
+from share_link
+inner join presentation on presentation.id = share_link.presentation
+where share_link.token_hash = @token_hash
+    and (share_link.expires_at is null or share_link.expires_at > @now::timestamptz)
+    and presentation.deleted_at is null
+;
@@ -0,0 +1,115 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: audit.sql
+
+package queries
+
+import (
+	"context"
+	"time"
+)
+
+const createAuditEntry = `-- name: CreateAuditEntry :exec
+INSERT INTO audit_entry (
+    presentation,
+    section,
+    actor,
+    action,
+    diff
+) VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+`
+
+type CreateAuditEntryParams struct {
+	Presentation int64  `json:"presentation"`
+	Section      *int64 `json:"section"`
+	Actor        *int64 `json:"actor"`
+	Action       string `json:"action"`
+	Diff         []byte `json:"diff"`
+}
+
+func (q *Queries) CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) error {
+	_, err := q.db.Exec(ctx, createAuditEntry,
+		arg.Presentation,
+		arg.Section,
+		arg.Actor,
+		arg.Action,
+		arg.Diff,
+	)
+	return err
+}
+
+const getAuditEntries = `-- name: GetAuditEntries :many
+select audit_entry.id, audit_entry.presentation, audit_entry.section, audit_entry.actor, audit_entry.action, audit_entry.diff, audit_entry.created_at, account.username actor_username
+from audit_entry
+left join account on account.id = audit_entry.actor
+where audit_entry.presentation = $1
+order by audit_entry.id desc
+limit $3
+offset $2
+`
+
+type GetAuditEntriesParams struct {
+	PresentationID int64 `json:"presentation_id"`
+	QueryOffset    int32 `json:"query_offset"`
+	QueryLimit     int32 `json:"query_limit"`
+}
+
+type GetAuditEntriesRow struct {
+	ID            int64     `json:"id"`
+	Presentation  int64     `json:"presentation"`
+	Section       *int64    `json:"section"`
+	Actor         *int64    `json:"actor"`
+	Action        string    `json:"action"`
+	Diff          []byte    `json:"diff"`
+	CreatedAt     time.Time `json:"created_at"`
+	ActorUsername *string   `json:"actor_username"`
+}
+
+func (q *Queries) GetAuditEntries(ctx context.Context, arg GetAuditEntriesParams) ([]GetAuditEntriesRow, error) {
+	rows, err := q.db.Query(ctx, getAuditEntries, arg.PresentationID, arg.QueryOffset, arg.QueryLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAuditEntriesRow
+	for rows.Next() {
+		var i GetAuditEntriesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Presentation,
+			&i.Section,
+			&i.Actor,
+			&i.Action,
+			&i.Diff,
+			&i.CreatedAt,
+			&i.ActorUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAuditEntriesMetadata = `-- name: GetAuditEntriesMetadata :one
+select count(*)
+from audit_entry
+where presentation = $1
+`
+
+func (q *Queries) GetAuditEntriesMetadata(ctx context.Context, presentationID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, getAuditEntriesMetadata, presentationID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
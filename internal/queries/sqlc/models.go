@@ -8,9 +8,56 @@ import (
 	"time"
 )
 
+type Account struct {
+	ID           int64     `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash []byte    `json:"password_hash"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ApiKey struct {
+	ID         int64      `json:"id"`
+	Account    int64      `json:"account"`
+	Name       string     `json:"name"`
+	KeyHash    []byte     `json:"key_hash"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Workspace  int64      `json:"workspace"`
+}
+
+type AuditEntry struct {
+	ID           int64     `json:"id"`
+	Presentation int64     `json:"presentation"`
+	Section      *int64    `json:"section"`
+	Actor        *int64    `json:"actor"`
+	Action       string    `json:"action"`
+	Diff         []byte    `json:"diff"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 type Presentation struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Owner     *int64     `json:"owner"`
+	Workspace int64      `json:"workspace"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	Version   int32      `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type PresentationGrant struct {
+	Presentation int64  `json:"presentation"`
+	Account      int64  `json:"account"`
+	Role         string `json:"role"`
+}
+
+type PresentationVersion struct {
+	Presentation int64     `json:"presentation"`
+	Version      int32     `json:"version"`
+	Name         string    `json:"name"`
+	Sections     []byte    `json:"sections"`
+	Actor        *int64    `json:"actor"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type Section struct {
@@ -19,4 +66,34 @@ type Section struct {
 	Name         string        `json:"name"`
 	Duration     time.Duration `json:"duration"`
 	Position     int16         `json:"position"`
+	DeletedAt    *time.Time    `json:"deleted_at"`
+	Version      int32         `json:"version"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type Session struct {
+	TokenHash []byte    `json:"token_hash"`
+	Account   int64     `json:"account"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ShareLink struct {
+	ID           int64      `json:"id"`
+	Presentation int64      `json:"presentation"`
+	TokenHash    []byte     `json:"token_hash"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
+
+type Workspace struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WorkspaceMember struct {
+	Workspace int64  `json:"workspace"`
+	Account   int64  `json:"account"`
+	Role      string `json:"role"`
 }
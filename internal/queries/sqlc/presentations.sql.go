@@ -7,87 +7,405 @@ package queries
 
 import (
 	"context"
-
 	"time"
 )
 
 const createPresentation = `-- name: CreatePresentation :one
 INSERT INTO presentation(
-    name
+    workspace,
+    name,
+    owner
 ) VALUES (
-    $1
+    $1,
+    $2,
+    $3
 )
-RETURNING id, name
+RETURNING id, name, owner, workspace, deleted_at, version, created_at
 `
 
-func (q *Queries) CreatePresentation(ctx context.Context, name string) (Presentation, error) {
-	row := q.db.QueryRow(ctx, createPresentation, name)
+type CreatePresentationParams struct {
+	Workspace int64  `json:"workspace"`
+	Name      string `json:"name"`
+	Owner     *int64 `json:"owner"`
+}
+
+func (q *Queries) CreatePresentation(ctx context.Context, arg CreatePresentationParams) (Presentation, error) {
+	row := q.db.QueryRow(ctx, createPresentation, arg.Workspace, arg.Name, arg.Owner)
 	var i Presentation
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Owner,
+		&i.Workspace,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
 	return i, err
 }
 
+const deleteGrant = `-- name: DeleteGrant :execrows
+delete from presentation_grant
+where presentation = $1 and account = $2
+`
+
+type DeleteGrantParams struct {
+	Presentation int64 `json:"presentation"`
+	Account      int64 `json:"account"`
+}
+
+func (q *Queries) DeleteGrant(ctx context.Context, arg DeleteGrantParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteGrant, arg.Presentation, arg.Account)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deletePresentation = `-- name: DeletePresentation :execrows
-delete from presentation
-where id = $1
+update presentation
+set deleted_at = $1::timestamptz, version = version + 1
+where id = $2 and deleted_at is null
 `
 
-func (q *Queries) DeletePresentation(ctx context.Context, id int64) (int64, error) {
-	result, err := q.db.Exec(ctx, deletePresentation, id)
+type DeletePresentationParams struct {
+	Now time.Time `json:"now"`
+	ID  int64     `json:"id"`
+}
+
+func (q *Queries) DeletePresentation(ctx context.Context, arg DeletePresentationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePresentation, arg.Now, arg.ID)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
+const getDeletedPresentations = `-- name: GetDeletedPresentations :many
+select id, name, owner, workspace, deleted_at, version, created_at
+from presentation
+where presentation.deleted_at is not null and presentation.workspace = $1 and (
+    $2::bigint is null
+    or presentation.owner = $2
+)
+order by presentation.deleted_at desc, presentation.id desc
+`
+
+type GetDeletedPresentationsParams struct {
+	Workspace int64  `json:"workspace"`
+	Account   *int64 `json:"account"`
+}
+
+func (q *Queries) GetDeletedPresentations(ctx context.Context, arg GetDeletedPresentationsParams) ([]Presentation, error) {
+	rows, err := q.db.Query(ctx, getDeletedPresentations, arg.Workspace, arg.Account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Presentation
+	for rows.Next() {
+		var i Presentation
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Owner,
+			&i.Workspace,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGrants = `-- name: GetGrants :many
+select presentation_grant.account, account.username, presentation_grant.role
+from presentation_grant
+inner join account on account.id = presentation_grant.account
+where presentation_grant.presentation = $1
+order by account.username
+`
+
+type GetGrantsRow struct {
+	Account  int64  `json:"account"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (q *Queries) GetGrants(ctx context.Context, presentationID int64) ([]GetGrantsRow, error) {
+	rows, err := q.db.Query(ctx, getGrants, presentationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGrantsRow
+	for rows.Next() {
+		var i GetGrantsRow
+		if err := rows.Scan(&i.Account, &i.Username, &i.Role); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getPresentation = `-- name: GetPresentation :one
-select id, name
+select id, name, owner, workspace, deleted_at, version, created_at
 from presentation
-where id = $1
+where id = $1 and deleted_at is null
 `
 
 func (q *Queries) GetPresentation(ctx context.Context, id int64) (Presentation, error) {
 	row := q.db.QueryRow(ctx, getPresentation, id)
 	var i Presentation
-	err := row.Scan(&i.ID, &i.Name)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Owner,
+		&i.Workspace,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPresentationAccess = `-- name: GetPresentationAccess :one
+select presentation.owner, presentation.deleted_at, presentation_grant.role
+from presentation
+left join presentation_grant
+    on presentation_grant.presentation = presentation.id
+    and presentation_grant.account = $1
+where presentation.id = $2 and presentation.workspace = $3
+`
+
+type GetPresentationAccessParams struct {
+	Account        int64 `json:"account"`
+	PresentationID int64 `json:"presentation_id"`
+	Workspace      int64 `json:"workspace"`
+}
+
+type GetPresentationAccessRow struct {
+	Owner     *int64     `json:"owner"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	Role      *string    `json:"role"`
+}
+
+func (q *Queries) GetPresentationAccess(ctx context.Context, arg GetPresentationAccessParams) (GetPresentationAccessRow, error) {
+	row := q.db.QueryRow(ctx, getPresentationAccess, arg.Account, arg.PresentationID, arg.Workspace)
+	var i GetPresentationAccessRow
+	err := row.Scan(&i.Owner, &i.DeletedAt, &i.Role)
 	return i, err
 }
 
 const getPresentations = `-- name: GetPresentations :many
-select presentation.id, presentation.name, coalesce(sum(section.duration), '0 seconds')::interval duration
+select presentation.id, presentation.name, presentation.owner, presentation.workspace, presentation.deleted_at, presentation.version, presentation.created_at, coalesce(sum(section.duration), '0 seconds')::interval duration
 from presentation
-left join section on presentation.id = section.presentation
+left join section on presentation.id = section.presentation and section.deleted_at is null
+where presentation.deleted_at is null and presentation.workspace = $1 and (
+    $2::bigint is null
+    or (
+        not $3::bool
+        and presentation.owner = $2
+    )
+    or exists (
+        select 1
+        from presentation_grant
+        where presentation_grant.presentation = presentation.id
+            and presentation_grant.account = $2
+    )
+) and (
+    $4::text is null
+    or strpos(lower(presentation.name), lower($4)) > 0
+) and (
+    $5::timestamptz is null
+    or presentation.created_at > $5
+) and (
+    $6::text is null
+    or to_tsvector('simple', presentation.name) @@ websearch_to_tsquery('simple', $6)
+    or exists (
+        select 1
+        from section searched
+        where searched.presentation = presentation.id and searched.deleted_at is null
+            and to_tsvector('simple', searched.name) @@ websearch_to_tsquery('simple', $6)
+    )
+)
 group by presentation.id
+having (
+    $7::interval is null
+    or coalesce(sum(section.duration), '0 seconds') >= $7
+) and (
+    $8::interval is null
+    or coalesce(sum(section.duration), '0 seconds') <= $8
+) and (
+    $9::bigint is null
+    or (
+        case $10::text
+            when 'name' then case when presentation.name > $11::text then 1 when presentation.name < $11::text then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), '0 seconds') > $12::interval then 1 when coalesce(sum(section.duration), '0 seconds') < $12::interval then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > $13::timestamptz then 1 when presentation.created_at < $13::timestamptz then -1 else 0 end
+            else 0
+        end * case when $14::text = 'DESC' then -1 else 1 end,
+        case $15::text
+            when 'name' then case when presentation.name > $11::text then 1 when presentation.name < $11::text then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), '0 seconds') > $12::interval then 1 when coalesce(sum(section.duration), '0 seconds') < $12::interval then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > $13::timestamptz then 1 when presentation.created_at < $13::timestamptz then -1 else 0 end
+            else 0
+        end * case when $16::text = 'DESC' then -1 else 1 end,
+        case $17::text
+            when 'name' then case when presentation.name > $11::text then 1 when presentation.name < $11::text then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), '0 seconds') > $12::interval then 1 when coalesce(sum(section.duration), '0 seconds') < $12::interval then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > $13::timestamptz then 1 when presentation.created_at < $13::timestamptz then -1 else 0 end
+            else 0
+        end * case when $18::text = 'DESC' then -1 else 1 end,
+        (case when presentation.id > $9 then 1 when presentation.id < $9 then -1 else 0 end)
+            * case when $19::text = 'DESC' then -1 else 1 end
+    ) > (0, 0, 0, 0)
+)
 order by
     case
-        when $1::text = 'ASC' and $2::text = 'name' then presentation.name
+        when $14::text = 'ASC' and $10::text = 'name'
+        then presentation.name
+    end asc,
+    case
+        when $14::text = 'DESC' and $10::text = 'name'
+        then presentation.name
+    end desc,
+    case
+        when $14::text = 'ASC' and $10::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end asc,
+    case
+        when $14::text = 'DESC' and $10::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end desc,
+    case
+        when $14::text = 'ASC' and $10::text = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when $14::text = 'DESC' and $10::text = 'created_at'
+        then presentation.created_at
+    end desc,
+    case
+        when $16::text = 'ASC' and $15::text = 'name'
+        then presentation.name
+    end asc,
+    case
+        when $16::text = 'DESC' and $15::text = 'name'
+        then presentation.name
+    end desc,
+    case
+        when $16::text = 'ASC' and $15::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
     end asc,
     case
-        when $1::text = 'DESC' and $2::text = 'name'
+        when $16::text = 'DESC' and $15::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end desc,
+    case
+        when $16::text = 'ASC' and $15::text = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when $16::text = 'DESC' and $15::text = 'created_at'
+        then presentation.created_at
+    end desc,
+    case
+        when $18::text = 'ASC' and $17::text = 'name'
+        then presentation.name
+    end asc,
+    case
+        when $18::text = 'DESC' and $17::text = 'name'
         then presentation.name
     end desc,
-    presentation.id desc
-limit $4
-offset $3
+    case
+        when $18::text = 'ASC' and $17::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end asc,
+    case
+        when $18::text = 'DESC' and $17::text = 'duration'
+        then coalesce(sum(section.duration), '0 seconds')
+    end desc,
+    case
+        when $18::text = 'ASC' and $17::text = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when $18::text = 'DESC' and $17::text = 'created_at'
+        then presentation.created_at
+    end desc,
+    case when $19::text = 'ASC' then presentation.id end asc,
+    case when $19::text = 'DESC' then presentation.id end desc
+limit $21
+offset $20
 `
 
 type GetPresentationsParams struct {
-	Direction   string `json:"direction"`
-	SortBy      string `json:"sort_by"`
-	QueryOffset int32  `json:"query_offset"`
-	QueryLimit  int32  `json:"query_limit"`
+	Workspace       int64          `json:"workspace"`
+	Account         *int64         `json:"account"`
+	SharedWithMe    bool           `json:"shared_with_me"`
+	NameContains    *string        `json:"name_contains"`
+	CreatedAfter    *time.Time     `json:"created_after"`
+	Search          *string        `json:"search"`
+	MinDuration     *time.Duration `json:"min_duration"`
+	MaxDuration     *time.Duration `json:"max_duration"`
+	CursorID        *int64         `json:"cursor_id"`
+	SortBy1         string         `json:"sort_by_1"`
+	CursorName      string         `json:"cursor_name"`
+	CursorDuration  time.Duration  `json:"cursor_duration"`
+	CursorCreatedAt time.Time      `json:"cursor_created_at"`
+	Direction1      string         `json:"direction_1"`
+	SortBy2         string         `json:"sort_by_2"`
+	Direction2      string         `json:"direction_2"`
+	SortBy3         string         `json:"sort_by_3"`
+	Direction3      string         `json:"direction_3"`
+	IDDirection     string         `json:"id_direction"`
+	QueryOffset     int32          `json:"query_offset"`
+	QueryLimit      int32          `json:"query_limit"`
 }
 
 type GetPresentationsRow struct {
-	ID       int64         `json:"id"`
-	Name     string        `json:"name"`
-	Duration time.Duration `json:"duration"`
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Owner     *int64        `json:"owner"`
+	Workspace int64         `json:"workspace"`
+	DeletedAt *time.Time    `json:"deleted_at"`
+	Version   int32         `json:"version"`
+	CreatedAt time.Time     `json:"created_at"`
+	Duration  time.Duration `json:"duration"`
 }
 
 func (q *Queries) GetPresentations(ctx context.Context, arg GetPresentationsParams) ([]GetPresentationsRow, error) {
 	rows, err := q.db.Query(ctx, getPresentations,
-		arg.Direction,
-		arg.SortBy,
+		arg.Workspace,
+		arg.Account,
+		arg.SharedWithMe,
+		arg.NameContains,
+		arg.CreatedAfter,
+		arg.Search,
+		arg.MinDuration,
+		arg.MaxDuration,
+		arg.CursorID,
+		arg.SortBy1,
+		arg.CursorName,
+		arg.CursorDuration,
+		arg.CursorCreatedAt,
+		arg.Direction1,
+		arg.SortBy2,
+		arg.Direction2,
+		arg.SortBy3,
+		arg.Direction3,
+		arg.IDDirection,
 		arg.QueryOffset,
 		arg.QueryLimit,
 	)
@@ -98,7 +416,16 @@ func (q *Queries) GetPresentations(ctx context.Context, arg GetPresentationsPara
 	var items []GetPresentationsRow
 	for rows.Next() {
 		var i GetPresentationsRow
-		if err := rows.Scan(&i.ID, &i.Name, &i.Duration); err != nil {
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Owner,
+			&i.Workspace,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+			&i.Duration,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -111,20 +438,101 @@ func (q *Queries) GetPresentations(ctx context.Context, arg GetPresentationsPara
 
 const getPresentationsMetadata = `-- name: GetPresentationsMetadata :one
 select count(*)
-from presentation
+from (
+    select presentation.id
+    from presentation
+    left join section on presentation.id = section.presentation and section.deleted_at is null
+    where presentation.deleted_at is null and presentation.workspace = $1 and (
+        $2::bigint is null
+        or (
+            not $3::bool
+            and presentation.owner = $2
+        )
+        or exists (
+            select 1
+            from presentation_grant
+            where presentation_grant.presentation = presentation.id
+                and presentation_grant.account = $2
+        )
+    ) and (
+        $4::text is null
+        or strpos(lower(presentation.name), lower($4)) > 0
+    ) and (
+        $5::timestamptz is null
+        or presentation.created_at > $5
+    ) and (
+        $6::text is null
+        or to_tsvector('simple', presentation.name) @@ websearch_to_tsquery('simple', $6)
+        or exists (
+            select 1
+            from section searched
+            where searched.presentation = presentation.id and searched.deleted_at is null
+                and to_tsvector('simple', searched.name) @@ websearch_to_tsquery('simple', $6)
+        )
+    )
+    group by presentation.id
+    having (
+        $7::interval is null
+        or coalesce(sum(section.duration), '0 seconds') >= $7
+    ) and (
+        $8::interval is null
+        or coalesce(sum(section.duration), '0 seconds') <= $8
+    )
+) filtered
 `
 
-func (q *Queries) GetPresentationsMetadata(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, getPresentationsMetadata)
+type GetPresentationsMetadataParams struct {
+	Workspace    int64          `json:"workspace"`
+	Account      *int64         `json:"account"`
+	SharedWithMe bool           `json:"shared_with_me"`
+	NameContains *string        `json:"name_contains"`
+	CreatedAfter *time.Time     `json:"created_after"`
+	Search       *string        `json:"search"`
+	MinDuration  *time.Duration `json:"min_duration"`
+	MaxDuration  *time.Duration `json:"max_duration"`
+}
+
+func (q *Queries) GetPresentationsMetadata(ctx context.Context, arg GetPresentationsMetadataParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getPresentationsMetadata,
+		arg.Workspace,
+		arg.Account,
+		arg.SharedWithMe,
+		arg.NameContains,
+		arg.CreatedAfter,
+		arg.Search,
+		arg.MinDuration,
+		arg.MaxDuration,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const lockPresentation = `-- name: LockPresentation :one
+select id, name, owner, workspace, deleted_at, version, created_at
+from presentation
+where id = $1 and deleted_at is null for update
+`
+
+func (q *Queries) LockPresentation(ctx context.Context, id int64) (Presentation, error) {
+	row := q.db.QueryRow(ctx, lockPresentation, id)
+	var i Presentation
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Owner,
+		&i.Workspace,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const patchPresentation = `-- name: PatchPresentation :execrows
 UPDATE presentation
-SET name = COALESCE($1, name)
-WHERE id = $2
+SET name = COALESCE($1, name), version = version + 1
+WHERE id = $2 and deleted_at is null
 `
 
 type PatchPresentationParams struct {
@@ -140,10 +548,37 @@ func (q *Queries) PatchPresentation(ctx context.Context, arg PatchPresentationPa
 	return result.RowsAffected(), nil
 }
 
+const purgePresentations = `-- name: PurgePresentations :execrows
+delete from presentation
+where deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgePresentations(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgePresentations, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restorePresentation = `-- name: RestorePresentation :execrows
+update presentation
+set deleted_at = null, version = version + 1
+where id = $1 and deleted_at is not null
+`
+
+func (q *Queries) RestorePresentation(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, restorePresentation, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updatePresentation = `-- name: UpdatePresentation :execrows
 UPDATE presentation
-SET name = $1
-WHERE id = $2
+SET name = $1, version = version + 1
+WHERE id = $2 and deleted_at is null
 `
 
 type UpdatePresentationParams struct {
@@ -158,3 +593,28 @@ func (q *Queries) UpdatePresentation(ctx context.Context, arg UpdatePresentation
 	}
 	return result.RowsAffected(), nil
 }
+
+const upsertGrant = `-- name: UpsertGrant :exec
+INSERT INTO presentation_grant (
+    presentation,
+    account,
+    role
+) VALUES (
+    $1,
+    $2,
+    $3
+)
+ON CONFLICT (presentation, account) DO UPDATE
+SET role = excluded.role
+`
+
+type UpsertGrantParams struct {
+	Presentation int64  `json:"presentation"`
+	Account      int64  `json:"account"`
+	Role         string `json:"role"`
+}
+
+func (q *Queries) UpsertGrant(ctx context.Context, arg UpsertGrantParams) error {
+	_, err := q.db.Exec(ctx, upsertGrant, arg.Presentation, arg.Account, arg.Role)
+	return err
+}
@@ -7,7 +7,6 @@ package queries
 
 import (
 	"context"
-
 	"time"
 )
 
@@ -26,11 +25,12 @@ with
         select id, row_number() over (order by position) as new_position
         from section o
         where o.presentation = (select i.presentation from section i where i.id = $1)
+            and o.deleted_at is null
     )
     update section
-    set position = ordered.new_position
+    set position = ordered.new_position, version = section.version + 1
 from ordered
-where section.id = ordered.id
+where section.id = ordered.id and section.position <> ordered.new_position
 `
 
 func (q *Queries) CleanPositionsBySectionGroup(ctx context.Context, id int64) error {
@@ -49,7 +49,7 @@ INSERT INTO section (
     $2,
     $3,
     $4
-) RETURNING id, presentation, name, duration, position
+) RETURNING id, presentation, name, duration, position, deleted_at, version, created_at
 `
 
 type CreateSectionParams struct {
@@ -73,27 +73,112 @@ func (q *Queries) CreateSection(ctx context.Context, arg CreateSectionParams) (S
 		&i.Name,
 		&i.Duration,
 		&i.Position,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const deleteSection = `-- name: DeleteSection :execrows
-delete from section
-where id = $1
+update section
+set deleted_at = $1::timestamptz, version = version + 1
+where id = $2 and deleted_at is null
 `
 
-func (q *Queries) DeleteSection(ctx context.Context, id int64) (int64, error) {
-	result, err := q.db.Exec(ctx, deleteSection, id)
+type DeleteSectionParams struct {
+	Now time.Time `json:"now"`
+	ID  int64     `json:"id"`
+}
+
+func (q *Queries) DeleteSection(ctx context.Context, arg DeleteSectionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteSection, arg.Now, arg.ID)
 	if err != nil {
 		return 0, err
 	}
 	return result.RowsAffected(), nil
 }
 
+const getDeletedSection = `-- name: GetDeletedSection :one
+select id, presentation, name, duration, position, deleted_at, version, created_at
+from section
+where id = $1 and deleted_at is not null
+`
+
+func (q *Queries) GetDeletedSection(ctx context.Context, id int64) (Section, error) {
+	row := q.db.QueryRow(ctx, getDeletedSection, id)
+	var i Section
+	err := row.Scan(
+		&i.ID,
+		&i.Presentation,
+		&i.Name,
+		&i.Duration,
+		&i.Position,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDeletedSections = `-- name: GetDeletedSections :many
+select section.id, section.presentation, section.name, section.duration, section.position, section.deleted_at, section.version, section.created_at
+from section
+inner join presentation on presentation.id = section.presentation
+where section.deleted_at is not null
+    and presentation.deleted_at is null
+    and presentation.workspace = $1
+    and (
+        $2::bigint is null
+        or presentation.owner = $2
+        or exists (
+            select 1
+            from presentation_grant
+            where presentation_grant.presentation = presentation.id
+                and presentation_grant.account = $2
+        )
+    )
+order by section.deleted_at desc, section.id desc
+`
+
+type GetDeletedSectionsParams struct {
+	Workspace int64  `json:"workspace"`
+	Account   *int64 `json:"account"`
+}
+
+func (q *Queries) GetDeletedSections(ctx context.Context, arg GetDeletedSectionsParams) ([]Section, error) {
+	rows, err := q.db.Query(ctx, getDeletedSections, arg.Workspace, arg.Account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Section
+	for rows.Next() {
+		var i Section
+		if err := rows.Scan(
+			&i.ID,
+			&i.Presentation,
+			&i.Name,
+			&i.Duration,
+			&i.Position,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getSection = `-- name: GetSection :one
-select id, presentation, name, duration, position
+select id, presentation, name, duration, position, deleted_at, version, created_at
 from section
-where id = $1
+where id = $1 and deleted_at is null
 `
 
 func (q *Queries) GetSection(ctx context.Context, id int64) (Section, error) {
@@ -105,47 +190,202 @@ func (q *Queries) GetSection(ctx context.Context, id int64) (Section, error) {
 		&i.Name,
 		&i.Duration,
 		&i.Position,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getSections = `-- name: GetSections :many
-select id, presentation, name, duration, position
+select id, presentation, name, duration, position, deleted_at, version, created_at
 from section
-where presentation = $1
+where presentation = $1 and deleted_at is null and (
+    $2::text is null
+    or strpos(lower(name), lower($2)) > 0
+) and (
+    $3::interval is null or duration >= $3
+) and (
+    $4::interval is null or duration <= $4
+) and (
+    $5::timestamptz is null or created_at > $5
+) and (
+    $6::text is null
+    or to_tsvector('simple', name) @@ websearch_to_tsquery('simple', $6)
+) and (
+    $7::bigint is null
+    or (
+        case $8::text
+            when 'name' then case when name > $9::text then 1 when name < $9::text then -1 else 0 end
+            when 'duration' then case when duration > $10::interval then 1 when duration < $10::interval then -1 else 0 end
+            when 'position' then case when position > $11::smallint then 1 when position < $11::smallint then -1 else 0 end
+            when 'created_at' then case when created_at > $12::timestamptz then 1 when created_at < $12::timestamptz then -1 else 0 end
+            else 0
+        end * case when $13::text = 'DESC' then -1 else 1 end,
+        case $14::text
+            when 'name' then case when name > $9::text then 1 when name < $9::text then -1 else 0 end
+            when 'duration' then case when duration > $10::interval then 1 when duration < $10::interval then -1 else 0 end
+            when 'position' then case when position > $11::smallint then 1 when position < $11::smallint then -1 else 0 end
+            when 'created_at' then case when created_at > $12::timestamptz then 1 when created_at < $12::timestamptz then -1 else 0 end
+            else 0
+        end * case when $15::text = 'DESC' then -1 else 1 end,
+        case $16::text
+            when 'name' then case when name > $9::text then 1 when name < $9::text then -1 else 0 end
+            when 'duration' then case when duration > $10::interval then 1 when duration < $10::interval then -1 else 0 end
+            when 'position' then case when position > $11::smallint then 1 when position < $11::smallint then -1 else 0 end
+            when 'created_at' then case when created_at > $12::timestamptz then 1 when created_at < $12::timestamptz then -1 else 0 end
+            else 0
+        end * case when $17::text = 'DESC' then -1 else 1 end,
+        (case when id > $7 then 1 when id < $7 then -1 else 0 end)
+            * case when $18::text = 'DESC' then -1 else 1 end
+    ) > (0, 0, 0, 0)
+)
 order by
-    case when $2::text = 'ASC' and $3::text = 'name' then name end asc,
-    case when $2::text = 'DESC' and $3::text = 'name' then name end desc,
     case
-        when $2::text = 'ASC' and $3::text = 'duration' then duration
+        when $13::text = 'ASC' and $8::text = 'name'
+        then name
+    end asc,
+    case
+        when $13::text = 'DESC' and $8::text = 'name'
+        then name
+    end desc,
+    case
+        when $13::text = 'ASC' and $8::text = 'duration'
+        then duration
+    end asc,
+    case
+        when $13::text = 'DESC' and $8::text = 'duration'
+        then duration
+    end desc,
+    case
+        when $13::text = 'ASC' and $8::text = 'position'
+        then position
+    end asc,
+    case
+        when $13::text = 'DESC' and $8::text = 'position'
+        then position
+    end desc,
+    case
+        when $13::text = 'ASC' and $8::text = 'created_at'
+        then created_at
+    end asc,
+    case
+        when $13::text = 'DESC' and $8::text = 'created_at'
+        then created_at
+    end desc,
+    case
+        when $15::text = 'ASC' and $14::text = 'name'
+        then name
+    end asc,
+    case
+        when $15::text = 'DESC' and $14::text = 'name'
+        then name
+    end desc,
+    case
+        when $15::text = 'ASC' and $14::text = 'duration'
+        then duration
+    end asc,
+    case
+        when $15::text = 'DESC' and $14::text = 'duration'
+        then duration
+    end desc,
+    case
+        when $15::text = 'ASC' and $14::text = 'position'
+        then position
+    end asc,
+    case
+        when $15::text = 'DESC' and $14::text = 'position'
+        then position
+    end desc,
+    case
+        when $15::text = 'ASC' and $14::text = 'created_at'
+        then created_at
+    end asc,
+    case
+        when $15::text = 'DESC' and $14::text = 'created_at'
+        then created_at
+    end desc,
+    case
+        when $17::text = 'ASC' and $16::text = 'name'
+        then name
+    end asc,
+    case
+        when $17::text = 'DESC' and $16::text = 'name'
+        then name
+    end desc,
+    case
+        when $17::text = 'ASC' and $16::text = 'duration'
+        then duration
     end asc,
     case
-        when $2::text = 'DESC' and $3::text = 'duration' then duration
+        when $17::text = 'DESC' and $16::text = 'duration'
+        then duration
     end desc,
     case
-        when $2::text = 'ASC' and $3::text = 'position' then position
+        when $17::text = 'ASC' and $16::text = 'position'
+        then position
     end asc,
     case
-        when $2::text = 'DESC' and $3::text = 'position' then position
+        when $17::text = 'DESC' and $16::text = 'position'
+        then position
     end desc,
-    id desc
-limit $5
-offset $4
+    case
+        when $17::text = 'ASC' and $16::text = 'created_at'
+        then created_at
+    end asc,
+    case
+        when $17::text = 'DESC' and $16::text = 'created_at'
+        then created_at
+    end desc,
+    case when $18::text = 'ASC' then id end asc,
+    case when $18::text = 'DESC' then id end desc
+limit $20
+offset $19
 `
 
 type GetSectionsParams struct {
-	PresentationID int64  `json:"presentation_id"`
-	Direction      string `json:"direction"`
-	SortBy         string `json:"sort_by"`
-	QueryOffset    int32  `json:"query_offset"`
-	QueryLimit     int32  `json:"query_limit"`
+	PresentationID  int64          `json:"presentation_id"`
+	NameContains    *string        `json:"name_contains"`
+	MinDuration     *time.Duration `json:"min_duration"`
+	MaxDuration     *time.Duration `json:"max_duration"`
+	CreatedAfter    *time.Time     `json:"created_after"`
+	Search          *string        `json:"search"`
+	CursorID        *int64         `json:"cursor_id"`
+	SortBy1         string         `json:"sort_by_1"`
+	CursorName      string         `json:"cursor_name"`
+	CursorDuration  time.Duration  `json:"cursor_duration"`
+	CursorPosition  int16          `json:"cursor_position"`
+	CursorCreatedAt time.Time      `json:"cursor_created_at"`
+	Direction1      string         `json:"direction_1"`
+	SortBy2         string         `json:"sort_by_2"`
+	Direction2      string         `json:"direction_2"`
+	SortBy3         string         `json:"sort_by_3"`
+	Direction3      string         `json:"direction_3"`
+	IDDirection     string         `json:"id_direction"`
+	QueryOffset     int32          `json:"query_offset"`
+	QueryLimit      int32          `json:"query_limit"`
 }
 
 func (q *Queries) GetSections(ctx context.Context, arg GetSectionsParams) ([]Section, error) {
 	rows, err := q.db.Query(ctx, getSections,
 		arg.PresentationID,
-		arg.Direction,
-		arg.SortBy,
+		arg.NameContains,
+		arg.MinDuration,
+		arg.MaxDuration,
+		arg.CreatedAfter,
+		arg.Search,
+		arg.CursorID,
+		arg.SortBy1,
+		arg.CursorName,
+		arg.CursorDuration,
+		arg.CursorPosition,
+		arg.CursorCreatedAt,
+		arg.Direction1,
+		arg.SortBy2,
+		arg.Direction2,
+		arg.SortBy3,
+		arg.Direction3,
+		arg.IDDirection,
 		arg.QueryOffset,
 		arg.QueryLimit,
 	)
@@ -162,6 +402,9 @@ func (q *Queries) GetSections(ctx context.Context, arg GetSectionsParams) ([]Sec
 			&i.Name,
 			&i.Duration,
 			&i.Position,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -178,9 +421,9 @@ with
     ordered as (
         select s.id, row_number() over (order by s.position) as new_position
         from section s
-        where s.presentation = $1
+        where s.presentation = $1 and s.deleted_at is null
     )
-select o.id, o.presentation, o.name, o.duration, o.position
+select o.id, o.presentation, o.name, o.duration, o.position, o.deleted_at, o.version, o.created_at
 from section o
 inner join ordered ord on ord.id = o.id
 where o.presentation = $1
@@ -202,6 +445,9 @@ func (q *Queries) GetSectionsByPosition(ctx context.Context, presentationID int6
 			&i.Name,
 			&i.Duration,
 			&i.Position,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -216,20 +462,106 @@ func (q *Queries) GetSectionsByPosition(ctx context.Context, presentationID int6
 const getSectionsMetadata = `-- name: GetSectionsMetadata :one
 select count(*)
 from section
-where presentation = $1
+where presentation = $1 and deleted_at is null and (
+    $2::text is null
+    or strpos(lower(name), lower($2)) > 0
+) and (
+    $3::interval is null or duration >= $3
+) and (
+    $4::interval is null or duration <= $4
+) and (
+    $5::timestamptz is null or created_at > $5
+) and (
+    $6::text is null
+    or to_tsvector('simple', name) @@ websearch_to_tsquery('simple', $6)
+)
 `
 
-func (q *Queries) GetSectionsMetadata(ctx context.Context, presentationID int64) (int64, error) {
-	row := q.db.QueryRow(ctx, getSectionsMetadata, presentationID)
+type GetSectionsMetadataParams struct {
+	PresentationID int64          `json:"presentation_id"`
+	NameContains   *string        `json:"name_contains"`
+	MinDuration    *time.Duration `json:"min_duration"`
+	MaxDuration    *time.Duration `json:"max_duration"`
+	CreatedAfter   *time.Time     `json:"created_after"`
+	Search         *string        `json:"search"`
+}
+
+func (q *Queries) GetSectionsMetadata(ctx context.Context, arg GetSectionsMetadataParams) (int64, error) {
+	row := q.db.QueryRow(ctx, getSectionsMetadata,
+		arg.PresentationID,
+		arg.NameContains,
+		arg.MinDuration,
+		arg.MaxDuration,
+		arg.CreatedAfter,
+		arg.Search,
+	)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
 }
 
+const getSectionsOfPresentations = `-- name: GetSectionsOfPresentations :many
+select id, presentation, name, duration, position, deleted_at, version, created_at
+from section
+where presentation = any($1::bigint[]) and deleted_at is null
+order by presentation, position, id
+`
+
+func (q *Queries) GetSectionsOfPresentations(ctx context.Context, presentationIds []int64) ([]Section, error) {
+	rows, err := q.db.Query(ctx, getSectionsOfPresentations, presentationIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Section
+	for rows.Next() {
+		var i Section
+		if err := rows.Scan(
+			&i.ID,
+			&i.Presentation,
+			&i.Name,
+			&i.Duration,
+			&i.Position,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockSection = `-- name: LockSection :one
+select id, presentation, name, duration, position, deleted_at, version, created_at
+from section
+where id = $1 and deleted_at is null for update
+`
+
+func (q *Queries) LockSection(ctx context.Context, id int64) (Section, error) {
+	row := q.db.QueryRow(ctx, lockSection, id)
+	var i Section
+	err := row.Scan(
+		&i.ID,
+		&i.Presentation,
+		&i.Name,
+		&i.Duration,
+		&i.Position,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const maxPosition = `-- name: MaxPosition :one
 select coalesce(max(position), 0)::smallint
 from section
-where presentation = $1
+where presentation = $1 and deleted_at is null
 `
 
 func (q *Queries) MaxPosition(ctx context.Context, presentationID int64) (int16, error) {
@@ -239,32 +571,15 @@ func (q *Queries) MaxPosition(ctx context.Context, presentationID int64) (int16,
 	return column_1, err
 }
 
-const moveSection = `-- name: MoveSection :exec
-update section s
-set position = case when s.id <> $1 then position - ($2::int / abs($2)) when id = $1 then position + $2 end
-where position between least(position + $2, position) and greatest(position + $2, position) and presentation = (
-    select sp.presentation from section sp where sp.id = $1
-)
-`
-
-type MoveSectionParams struct {
-	ID      int64 `json:"id"`
-	Column2 int32 `json:"column_2"`
-}
-
-func (q *Queries) MoveSection(ctx context.Context, arg MoveSectionParams) error {
-	_, err := q.db.Exec(ctx, moveSection, arg.ID, arg.Column2)
-	return err
-}
-
 const patchSection = `-- name: PatchSection :execrows
 UPDATE section
 SET
     name = COALESCE($1, name),
     duration = COALESCE($2, duration),
-    position = COALESCE($3, position)
+    position = COALESCE($3, position),
+    version = version + 1
 WHERE
-    id = $4
+    id = $4 and deleted_at is null
 `
 
 type PatchSectionParams struct {
@@ -287,14 +602,63 @@ func (q *Queries) PatchSection(ctx context.Context, arg PatchSectionParams) (int
 	return result.RowsAffected(), nil
 }
 
+const purgeSections = `-- name: PurgeSections :execrows
+delete from section
+where deleted_at < $1::timestamptz
+`
+
+func (q *Queries) PurgeSections(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, purgeSections, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreSection = `-- name: RestoreSection :execrows
+update section
+set deleted_at = null, position = $1, version = version + 1
+where id = $2 and deleted_at is not null
+`
+
+type RestoreSectionParams struct {
+	Position int16 `json:"position"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) RestoreSection(ctx context.Context, arg RestoreSectionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreSection, arg.Position, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setSectionPosition = `-- name: SetSectionPosition :exec
+update section
+set position = $1, version = version + 1
+where id = $2 and deleted_at is null and position <> $1
+`
+
+type SetSectionPositionParams struct {
+	Position int16 `json:"position"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) SetSectionPosition(ctx context.Context, arg SetSectionPositionParams) error {
+	_, err := q.db.Exec(ctx, setSectionPosition, arg.Position, arg.ID)
+	return err
+}
+
 const updateSection = `-- name: UpdateSection :execrows
 UPDATE section
 SET
     name = $1,
     duration = $2,
-    position = $3
+    position = $3,
+    version = version + 1
 WHERE
-    id = $4
+    id = $4 and deleted_at is null
 `
 
 type UpdateSectionParams struct {
@@ -0,0 +1,163 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: versions.sql
+
+package queries
+
+import (
+	"context"
+	"time"
+)
+
+const createPresentationVersion = `-- name: CreatePresentationVersion :one
+INSERT INTO presentation_version (
+    presentation,
+    version,
+    name,
+    sections,
+    actor
+) VALUES (
+    $1,
+    (
+        select coalesce(max(version), 0) + 1
+        from presentation_version
+        where presentation = $1
+    ),
+    $2,
+    $3,
+    $4
+) RETURNING presentation, version, name, sections, actor, created_at
+`
+
+type CreatePresentationVersionParams struct {
+	Presentation int64  `json:"presentation"`
+	Name         string `json:"name"`
+	Sections     []byte `json:"sections"`
+	Actor        *int64 `json:"actor"`
+}
+
+func (q *Queries) CreatePresentationVersion(ctx context.Context, arg CreatePresentationVersionParams) (PresentationVersion, error) {
+	row := q.db.QueryRow(ctx, createPresentationVersion,
+		arg.Presentation,
+		arg.Name,
+		arg.Sections,
+		arg.Actor,
+	)
+	var i PresentationVersion
+	err := row.Scan(
+		&i.Presentation,
+		&i.Version,
+		&i.Name,
+		&i.Sections,
+		&i.Actor,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPresentationVersion = `-- name: GetPresentationVersion :one
+select presentation_version.presentation, presentation_version.version, presentation_version.name, presentation_version.sections, presentation_version.actor, presentation_version.created_at, account.username actor_username
+from presentation_version
+left join account on account.id = presentation_version.actor
+where presentation_version.presentation = $1
+    and presentation_version.version = $2
+`
+
+type GetPresentationVersionParams struct {
+	PresentationID int64 `json:"presentation_id"`
+	Version        int32 `json:"version"`
+}
+
+type GetPresentationVersionRow struct {
+	Presentation  int64     `json:"presentation"`
+	Version       int32     `json:"version"`
+	Name          string    `json:"name"`
+	Sections      []byte    `json:"sections"`
+	Actor         *int64    `json:"actor"`
+	CreatedAt     time.Time `json:"created_at"`
+	ActorUsername *string   `json:"actor_username"`
+}
+
+func (q *Queries) GetPresentationVersion(ctx context.Context, arg GetPresentationVersionParams) (GetPresentationVersionRow, error) {
+	row := q.db.QueryRow(ctx, getPresentationVersion, arg.PresentationID, arg.Version)
+	var i GetPresentationVersionRow
+	err := row.Scan(
+		&i.Presentation,
+		&i.Version,
+		&i.Name,
+		&i.Sections,
+		&i.Actor,
+		&i.CreatedAt,
+		&i.ActorUsername,
+	)
+	return i, err
+}
+
+const getPresentationVersions = `-- name: GetPresentationVersions :many
+select
+    presentation_version.presentation,
+    presentation_version.version,
+    presentation_version.name,
+    presentation_version.created_at,
+    account.username actor_username
+from presentation_version
+left join account on account.id = presentation_version.actor
+where presentation_version.presentation = $1
+order by presentation_version.version desc
+limit $3
+offset $2
+`
+
+type GetPresentationVersionsParams struct {
+	PresentationID int64 `json:"presentation_id"`
+	QueryOffset    int32 `json:"query_offset"`
+	QueryLimit     int32 `json:"query_limit"`
+}
+
+type GetPresentationVersionsRow struct {
+	Presentation  int64     `json:"presentation"`
+	Version       int32     `json:"version"`
+	Name          string    `json:"name"`
+	CreatedAt     time.Time `json:"created_at"`
+	ActorUsername *string   `json:"actor_username"`
+}
+
+func (q *Queries) GetPresentationVersions(ctx context.Context, arg GetPresentationVersionsParams) ([]GetPresentationVersionsRow, error) {
+	rows, err := q.db.Query(ctx, getPresentationVersions, arg.PresentationID, arg.QueryOffset, arg.QueryLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPresentationVersionsRow
+	for rows.Next() {
+		var i GetPresentationVersionsRow
+		if err := rows.Scan(
+			&i.Presentation,
+			&i.Version,
+			&i.Name,
+			&i.CreatedAt,
+			&i.ActorUsername,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPresentationVersionsMetadata = `-- name: GetPresentationVersionsMetadata :one
+select count(*)
+from presentation_version
+where presentation = $1
+`
+
+func (q *Queries) GetPresentationVersionsMetadata(ctx context.Context, presentationID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, getPresentationVersionsMetadata, presentationID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
@@ -0,0 +1,76 @@
+-- name: CreateAccount :one
+INSERT INTO account (
+    username,
+    password_hash
+) VALUES (
+    @username,
+    @password_hash
+) RETURNING *;
+--
+-- name: GetAccount :one
+select *
+from account
+where id = @id;
+--
+-- name: GetAccountByUsername :one
+select *
+from account
+where username = @username;
+--
+-- name: CountAccounts :one
+select count(*)
+from account;
+--
+-- name: CreateSession :exec
+INSERT INTO session (
+    token_hash,
+    account,
+    expires_at
+) VALUES (
+    @token_hash,
+    @account,
+    @expires_at
+);
+--
+-- name: GetSessionAccount :one
+select account.*
+from session
+inner join account on account.id = session.account
+where session.token_hash = @token_hash and session.expires_at > @now;
+--
+-- name: DeleteSession :execrows
+delete from session
+where token_hash = @token_hash;
+--
+-- name: DeleteExpiredSessions :execrows
+delete from session
+where expires_at <= @now;
+--
+-- name: CreateAPIKey :one
+INSERT INTO api_key (
+    account,
+    workspace,
+    name,
+    key_hash
+) VALUES (
+    @account,
+    @workspace,
+    @name,
+    @key_hash
+) RETURNING *;
+--
+-- name: GetAPIKeys :many
+select *
+from api_key
+where account = @account and workspace = @workspace
+order by id;
+--
+-- name: DeleteAPIKey :execrows
+delete from api_key
+where id = @id and account = @account;
+--
+-- name: UseAPIKey :one
+update api_key
+set last_used_at = @now
+where key_hash = @key_hash
+returning account, workspace;
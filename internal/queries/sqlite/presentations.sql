@@ -0,0 +1,325 @@
+-- name: GetPresentations :many
+with params as (
+    select
+        cast(@direction_1 as text) as direction_1,
+        cast(@sort_by_1 as text) as sort_by_1,
+        cast(@direction_2 as text) as direction_2,
+        cast(@sort_by_2 as text) as sort_by_2,
+        cast(@direction_3 as text) as direction_3,
+        cast(@sort_by_3 as text) as sort_by_3,
+        cast(@id_direction as text) as id_direction,
+        cast(sqlc.narg(cursor_id) as integer) as cursor_id,
+        cast(@cursor_name as text) as cursor_name,
+        cast(@cursor_duration as interval) as cursor_duration,
+        cast(@cursor_created_at as text) as cursor_created_at,
+        cast(@workspace as integer) as workspace,
+        cast(sqlc.narg(account) as integer) as account,
+        cast(@shared_with_me as boolean) as shared_with_me,
+        cast(sqlc.narg(name_contains) as text) as name_contains,
+        cast(sqlc.narg(created_after) as text) as created_after,
+        cast(sqlc.narg(search) as text) as search,
+        cast(sqlc.narg(min_duration) as interval) as min_duration,
+        cast(sqlc.narg(max_duration) as interval) as max_duration
+)
+select presentation.*, cast(coalesce(sum(section.duration), 0) as interval) duration
+from presentation
+cross join params
+left join section on presentation.id = section.presentation and section.deleted_at is null
+where presentation.deleted_at is null and presentation.workspace = params.workspace and (
+    params.account is null
+    or (
+        not params.shared_with_me
+        and presentation.owner = params.account
+    )
+    or exists (
+        select 1
+        from presentation_grant
+        where presentation_grant.presentation = presentation.id
+            and presentation_grant.account = params.account
+    )
+) and (
+    params.name_contains is null
+    or instr(lower(presentation.name), lower(params.name_contains)) > 0
+) and (
+    params.created_after is null or presentation.created_at > params.created_after
+) and (
+    params.search is null
+    or not exists (
+        select 1
+        from json_each(params.search) term
+        where instr(lower(presentation.name), term.value) = 0
+    )
+    or exists (
+        select 1
+        from section searched
+        where searched.presentation = presentation.id and searched.deleted_at is null
+            and not exists (
+                select 1
+                from json_each(params.search) term
+                where instr(lower(searched.name), term.value) = 0
+            )
+    )
+)
+group by presentation.id
+having (
+    params.min_duration is null or coalesce(sum(section.duration), 0) >= params.min_duration
+) and (
+    params.max_duration is null or coalesce(sum(section.duration), 0) <= params.max_duration
+) and (
+    params.cursor_id is null
+    or (
+        case params.sort_by_1
+            when 'name' then case when presentation.name > params.cursor_name then 1 when presentation.name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), 0) > params.cursor_duration then 1 when coalesce(sum(section.duration), 0) < params.cursor_duration then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > params.cursor_created_at then 1 when presentation.created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_1 = 'DESC' then -1 else 1 end,
+        case params.sort_by_2
+            when 'name' then case when presentation.name > params.cursor_name then 1 when presentation.name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), 0) > params.cursor_duration then 1 when coalesce(sum(section.duration), 0) < params.cursor_duration then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > params.cursor_created_at then 1 when presentation.created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_2 = 'DESC' then -1 else 1 end,
+        case params.sort_by_3
+            when 'name' then case when presentation.name > params.cursor_name then 1 when presentation.name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), 0) > params.cursor_duration then 1 when coalesce(sum(section.duration), 0) < params.cursor_duration then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > params.cursor_created_at then 1 when presentation.created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_3 = 'DESC' then -1 else 1 end,
+        (case when presentation.id > params.cursor_id then 1 when presentation.id < params.cursor_id then -1 else 0 end)
+            * case when params.id_direction = 'DESC' then -1 else 1 end
+    ) > (0, 0, 0, 0)
+)
+order by
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'name'
+        then presentation.name
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'name'
+        then presentation.name
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'created_at'
+        then presentation.created_at
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'name'
+        then presentation.name
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'name'
+        then presentation.name
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'created_at'
+        then presentation.created_at
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'name'
+        then presentation.name
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'name'
+        then presentation.name
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'created_at'
+        then presentation.created_at
+    end desc,
+    case when params.id_direction = 'ASC' then presentation.id end asc,
+    case when params.id_direction = 'DESC' then presentation.id end desc
+limit @query_limit
+offset @query_offset;
+--
+-- name: GetPresentationsMetadata :one
+with params as (
+    select
+        cast(@workspace as integer) as workspace,
+        cast(sqlc.narg(account) as integer) as account,
+        cast(@shared_with_me as boolean) as shared_with_me,
+        cast(sqlc.narg(name_contains) as text) as name_contains,
+        cast(sqlc.narg(created_after) as text) as created_after,
+        cast(sqlc.narg(search) as text) as search,
+        cast(sqlc.narg(min_duration) as interval) as min_duration,
+        cast(sqlc.narg(max_duration) as interval) as max_duration
+)
+select count(*)
+from (
+    select presentation.id
+    from presentation
+    cross join params
+    left join section on presentation.id = section.presentation and section.deleted_at is null
+    where presentation.deleted_at is null and presentation.workspace = params.workspace and (
+        params.account is null
+        or (
+            not params.shared_with_me
+            and presentation.owner = params.account
+        )
+        or exists (
+            select 1
+            from presentation_grant
+            where presentation_grant.presentation = presentation.id
+                and presentation_grant.account = params.account
+        )
+    ) and (
+        params.name_contains is null
+        or instr(lower(presentation.name), lower(params.name_contains)) > 0
+    ) and (
+        params.created_after is null or presentation.created_at > params.created_after
+    ) and (
+        params.search is null
+        or not exists (
+            select 1
+            from json_each(params.search) term
+            where instr(lower(presentation.name), term.value) = 0
+        )
+        or exists (
+            select 1
+            from section searched
+            where searched.presentation = presentation.id and searched.deleted_at is null
+                and not exists (
+                    select 1
+                    from json_each(params.search) term
+                    where instr(lower(searched.name), term.value) = 0
+                )
+        )
+    )
+    group by presentation.id
+    having (
+        params.min_duration is null or coalesce(sum(section.duration), 0) >= params.min_duration
+    ) and (
+        params.max_duration is null or coalesce(sum(section.duration), 0) <= params.max_duration
+    )
+) filtered;
+--
+-- name: GetPresentation :one
+select *
+from presentation
+where id = @id and deleted_at is null;
+--
+-- name: LockPresentation :one
+select *
+from presentation
+where id = @id and deleted_at is null;
+--
+-- name: CreatePresentation :one
+INSERT INTO presentation(
+    workspace,
+    name,
+    owner,
+    created_at
+) VALUES (
+    @workspace,
+    @name,
+    sqlc.narg(owner),
+    @now
+)
+RETURNING *;
+--
+-- name: UpdatePresentation :execrows
+UPDATE presentation
+SET name = @name, version = version + 1
+WHERE id = @id and deleted_at is null;
+--
+-- name: PatchPresentation :execrows
+UPDATE presentation
+SET name = COALESCE(sqlc.narg('name'), name), version = version + 1
+WHERE id = @id and deleted_at is null;
+--
+-- name: DeletePresentation :execrows
+update presentation
+set deleted_at = @now, version = version + 1
+where id = @id and deleted_at is null;
+--
+-- name: RestorePresentation :execrows
+update presentation
+set deleted_at = null, version = version + 1
+where id = @id and deleted_at is not null;
+--
+-- name: GetDeletedPresentations :many
+with params as (
+    select
+        cast(@workspace as integer) as workspace,
+        cast(sqlc.narg(account) as integer) as account
+)
+select presentation.*
+from presentation
+cross join params
+where presentation.deleted_at is not null and presentation.workspace = params.workspace and (
+    params.account is null
+    or presentation.owner = params.account
+)
+order by presentation.deleted_at desc, presentation.id desc;
+--
+-- name: PurgePresentations :execrows
+delete from presentation
+where deleted_at < @before;
+--
+-- name: GetPresentationAccess :one
+select presentation.owner, presentation.deleted_at, presentation_grant.role
+from presentation
+left join presentation_grant
+    on presentation_grant.presentation = presentation.id
+    and presentation_grant.account = @account
+where presentation.id = @presentation_id and presentation.workspace = @workspace;
+--
+-- name: GetGrants :many
+select presentation_grant.account, account.username, presentation_grant.role
+from presentation_grant
+inner join account on account.id = presentation_grant.account
+where presentation_grant.presentation = @presentation_id
+order by account.username;
+--
+-- name: UpsertGrant :exec
+INSERT INTO presentation_grant (
+    presentation,
+    account,
+    role
+) VALUES (
+    @presentation,
+    @account,
+    @role
+)
+ON CONFLICT (presentation, account) DO UPDATE
+SET role = excluded.role;
+--
+-- name: DeleteGrant :execrows
+delete from presentation_grant
+where presentation = @presentation and account = @account;
@@ -0,0 +1,322 @@
+-- name: GetSections :many
+with params as (
+    select
+        cast(@direction_1 as text) as direction_1,
+        cast(@sort_by_1 as text) as sort_by_1,
+        cast(@direction_2 as text) as direction_2,
+        cast(@sort_by_2 as text) as sort_by_2,
+        cast(@direction_3 as text) as direction_3,
+        cast(@sort_by_3 as text) as sort_by_3,
+        cast(@id_direction as text) as id_direction,
+        cast(sqlc.narg(cursor_id) as integer) as cursor_id,
+        cast(@cursor_name as text) as cursor_name,
+        cast(@cursor_duration as interval) as cursor_duration,
+        cast(@cursor_position as integer) as cursor_position,
+        cast(@cursor_created_at as text) as cursor_created_at,
+        cast(@presentation_id as integer) as presentation_id,
+        cast(sqlc.narg(name_contains) as text) as name_contains,
+        cast(sqlc.narg(min_duration) as interval) as min_duration,
+        cast(sqlc.narg(max_duration) as interval) as max_duration,
+        cast(sqlc.narg(created_after) as text) as created_after,
+        cast(sqlc.narg(search) as text) as search
+)
+select section.*
+from section
+cross join params
+where presentation = params.presentation_id and deleted_at is null and (
+    params.name_contains is null or instr(lower(name), lower(params.name_contains)) > 0
+) and (
+    params.min_duration is null or duration >= params.min_duration
+) and (
+    params.max_duration is null or duration <= params.max_duration
+) and (
+    params.created_after is null or created_at > params.created_after
+) and (
+    params.search is null
+    or not exists (
+        select 1
+        from json_each(params.search) term
+        where instr(lower(name), term.value) = 0
+    )
+) and (
+    params.cursor_id is null
+    or (
+        case params.sort_by_1
+            when 'name' then case when name > params.cursor_name then 1 when name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when duration > params.cursor_duration then 1 when duration < params.cursor_duration then -1 else 0 end
+            when 'position' then case when position > params.cursor_position then 1 when position < params.cursor_position then -1 else 0 end
+            when 'created_at' then case when created_at > params.cursor_created_at then 1 when created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_1 = 'DESC' then -1 else 1 end,
+        case params.sort_by_2
+            when 'name' then case when name > params.cursor_name then 1 when name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when duration > params.cursor_duration then 1 when duration < params.cursor_duration then -1 else 0 end
+            when 'position' then case when position > params.cursor_position then 1 when position < params.cursor_position then -1 else 0 end
+            when 'created_at' then case when created_at > params.cursor_created_at then 1 when created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_2 = 'DESC' then -1 else 1 end,
+        case params.sort_by_3
+            when 'name' then case when name > params.cursor_name then 1 when name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when duration > params.cursor_duration then 1 when duration < params.cursor_duration then -1 else 0 end
+            when 'position' then case when position > params.cursor_position then 1 when position < params.cursor_position then -1 else 0 end
+            when 'created_at' then case when created_at > params.cursor_created_at then 1 when created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_3 = 'DESC' then -1 else 1 end,
+        (case when id > params.cursor_id then 1 when id < params.cursor_id then -1 else 0 end)
+            * case when params.id_direction = 'DESC' then -1 else 1 end
+    ) > (0, 0, 0, 0)
+)
+order by
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'name'
+        then name
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'name'
+        then name
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'duration'
+        then duration
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'duration'
+        then duration
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'position'
+        then position
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'position'
+        then position
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'created_at'
+        then created_at
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'created_at'
+        then created_at
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'name'
+        then name
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'name'
+        then name
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'duration'
+        then duration
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'duration'
+        then duration
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'position'
+        then position
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'position'
+        then position
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'created_at'
+        then created_at
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'created_at'
+        then created_at
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'name'
+        then name
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'name'
+        then name
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'duration'
+        then duration
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'duration'
+        then duration
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'position'
+        then position
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'position'
+        then position
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'created_at'
+        then created_at
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'created_at'
+        then created_at
+    end desc,
+    case when params.id_direction = 'ASC' then id end asc,
+    case when params.id_direction = 'DESC' then id end desc
+limit @query_limit
+offset @query_offset;
+--
+-- name: GetSectionsMetadata :one
+with params as (
+    select
+        cast(@presentation_id as integer) as presentation_id,
+        cast(sqlc.narg(name_contains) as text) as name_contains,
+        cast(sqlc.narg(min_duration) as interval) as min_duration,
+        cast(sqlc.narg(max_duration) as interval) as max_duration,
+        cast(sqlc.narg(created_after) as text) as created_after,
+        cast(sqlc.narg(search) as text) as search
+)
+select count(*)
+from section
+cross join params
+where presentation = params.presentation_id and deleted_at is null and (
+    params.name_contains is null or instr(lower(name), lower(params.name_contains)) > 0
+) and (
+    params.min_duration is null or duration >= params.min_duration
+) and (
+    params.max_duration is null or duration <= params.max_duration
+) and (
+    params.created_after is null or created_at > params.created_after
+) and (
+    params.search is null
+    or not exists (
+        select 1
+        from json_each(params.search) term
+        where instr(lower(name), term.value) = 0
+    )
+);
+--
+-- name: GetSection :one
+select *
+from section
+where id = @id and deleted_at is null;
+--
+-- name: LockSection :one
+select *
+from section
+where id = @id and deleted_at is null;
+--
+-- name: CreateSection :one
+INSERT INTO section (
+    presentation,
+    name,
+    duration,
+    position,
+    created_at
+) VALUES (
+    @presentation,
+    @name,
+    cast(@duration as interval),
+    @position,
+    @now
+) RETURNING *;
+--
+-- name: UpdateSection :execrows
+UPDATE section
+SET
+    name = @name,
+    duration = cast(@duration as interval),
+    position = @position,
+    version = version + 1
+WHERE
+    id = @id and deleted_at is null;
+--
+-- name: PatchSection :execrows
+UPDATE section
+SET
+    name = COALESCE(sqlc.narg(name), name),
+    duration = COALESCE(cast(sqlc.narg(duration) as interval), duration),
+    position = COALESCE(sqlc.narg(position), position),
+    version = version + 1
+WHERE
+    id = @id and deleted_at is null;
+--
+-- name: DeleteSection :execrows
+update section
+set deleted_at = @now, version = version + 1
+where id = @id and deleted_at is null;
+--
+-- name: GetDeletedSection :one
+select *
+from section
+where id = @id and deleted_at is not null;
+--
+-- name: GetSectionPresentation :one
+select presentation
+from section
+where id = @id;
+--
+-- name: RestoreSection :execrows
+update section
+set deleted_at = null, position = @position, version = version + 1
+where id = @id and deleted_at is not null;
+--
+-- name: GetDeletedSections :many
+with params as (
+    select
+        cast(@workspace as integer) as workspace,
+        cast(sqlc.narg(account) as integer) as account
+)
+select section.*
+from section
+cross join params
+inner join presentation on presentation.id = section.presentation
+where section.deleted_at is not null
+    and presentation.deleted_at is null
+    and presentation.workspace = params.workspace
+    and (
+        params.account is null
+        or presentation.owner = params.account
+        or exists (
+            select 1
+            from presentation_grant
+            where presentation_grant.presentation = presentation.id
+                and presentation_grant.account = params.account
+        )
+    )
+order by section.deleted_at desc, section.id desc;
+--
+-- name: PurgeSections :execrows
+delete from section
+where deleted_at < @before;
+--
+-- name: MaxPosition :one
+select cast(coalesce(max(position), 0) as integer)
+from section
+where presentation = @presentation_id and deleted_at is null;
+--
+-- name: GetAllSectionsByPosition :many
+select section.id, section.presentation, section.position
+from section
+inner join presentation on presentation.id = section.presentation
+where section.deleted_at is null and presentation.deleted_at is null
+order by section.presentation, section.position, section.id;
+--
+-- name: SetPosition :exec
+update section
+set position = @position, version = version + 1
+where id = @id;
+--
+-- name: GetSectionsByPosition :many
+select *
+from section
+where presentation = @presentation_id and deleted_at is null
+order by position, id;
+--
+-- name: GetSectionsOfPresentations :many
+select *
+from section
+where presentation in (sqlc.slice('presentation_ids')) and deleted_at is null
+order by presentation, position, id;
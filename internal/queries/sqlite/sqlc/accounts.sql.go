@@ -0,0 +1,285 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: accounts.sql
+
+package sqlitequeries
+
+import (
+	"context"
+	"time"
+)
+
+const countAccounts = `-- name: CountAccounts :one
+select count(*)
+from account
+`
+
+func (q *Queries) CountAccounts(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countAccounts)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_key (
+    account,
+    workspace,
+    name,
+    key_hash
+) VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4
+) RETURNING id, account, name, key_hash, created_at, last_used_at, workspace
+`
+
+type CreateAPIKeyParams struct {
+	Account   int64  `json:"account"`
+	Workspace int64  `json:"workspace"`
+	Name      string `json:"name"`
+	KeyHash   []byte `json:"key_hash"`
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (ApiKey, error) {
+	row := q.db.QueryRowContext(ctx, createAPIKey,
+		arg.Account,
+		arg.Workspace,
+		arg.Name,
+		arg.KeyHash,
+	)
+	var i ApiKey
+	err := row.Scan(
+		&i.ID,
+		&i.Account,
+		&i.Name,
+		&i.KeyHash,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+		&i.Workspace,
+	)
+	return i, err
+}
+
+const createAccount = `-- name: CreateAccount :one
+INSERT INTO account (
+    username,
+    password_hash
+) VALUES (
+    ?1,
+    ?2
+) RETURNING id, username, password_hash, created_at
+`
+
+type CreateAccountParams struct {
+	Username     string `json:"username"`
+	PasswordHash []byte `json:"password_hash"`
+}
+
+func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, createAccount, arg.Username, arg.PasswordHash)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSession = `-- name: CreateSession :exec
+INSERT INTO session (
+    token_hash,
+    account,
+    expires_at
+) VALUES (
+    ?1,
+    ?2,
+    ?3
+)
+`
+
+type CreateSessionParams struct {
+	TokenHash []byte    `json:"token_hash"`
+	Account   int64     `json:"account"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.ExecContext(ctx, createSession, arg.TokenHash, arg.Account, arg.ExpiresAt)
+	return err
+}
+
+const deleteAPIKey = `-- name: DeleteAPIKey :execrows
+delete from api_key
+where id = ?1 and account = ?2
+`
+
+type DeleteAPIKeyParams struct {
+	ID      int64 `json:"id"`
+	Account int64 `json:"account"`
+}
+
+func (q *Queries) DeleteAPIKey(ctx context.Context, arg DeleteAPIKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteAPIKey, arg.ID, arg.Account)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredSessions = `-- name: DeleteExpiredSessions :execrows
+delete from session
+where expires_at <= ?1
+`
+
+func (q *Queries) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredSessions, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSession = `-- name: DeleteSession :execrows
+delete from session
+where token_hash = ?1
+`
+
+func (q *Queries) DeleteSession(ctx context.Context, tokenHash []byte) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSession, tokenHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAPIKeys = `-- name: GetAPIKeys :many
+select id, account, name, key_hash, created_at, last_used_at, workspace
+from api_key
+where account = ?1 and workspace = ?2
+order by id
+`
+
+type GetAPIKeysParams struct {
+	Account   int64 `json:"account"`
+	Workspace int64 `json:"workspace"`
+}
+
+func (q *Queries) GetAPIKeys(ctx context.Context, arg GetAPIKeysParams) ([]ApiKey, error) {
+	rows, err := q.db.QueryContext(ctx, getAPIKeys, arg.Account, arg.Workspace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApiKey
+	for rows.Next() {
+		var i ApiKey
+		if err := rows.Scan(
+			&i.ID,
+			&i.Account,
+			&i.Name,
+			&i.KeyHash,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+			&i.Workspace,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAccount = `-- name: GetAccount :one
+select id, username, password_hash, created_at
+from account
+where id = ?1
+`
+
+func (q *Queries) GetAccount(ctx context.Context, id int64) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccount, id)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAccountByUsername = `-- name: GetAccountByUsername :one
+select id, username, password_hash, created_at
+from account
+where username = ?1
+`
+
+func (q *Queries) GetAccountByUsername(ctx context.Context, username string) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getAccountByUsername, username)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSessionAccount = `-- name: GetSessionAccount :one
+select account.id, account.username, account.password_hash, account.created_at
+from session
+inner join account on account.id = session.account
+where session.token_hash = ?1 and session.expires_at > ?2
+`
+
+type GetSessionAccountParams struct {
+	TokenHash []byte    `json:"token_hash"`
+	Now       time.Time `json:"now"`
+}
+
+func (q *Queries) GetSessionAccount(ctx context.Context, arg GetSessionAccountParams) (Account, error) {
+	row := q.db.QueryRowContext(ctx, getSessionAccount, arg.TokenHash, arg.Now)
+	var i Account
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.PasswordHash,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const useAPIKey = `-- name: UseAPIKey :one
+update api_key
+set last_used_at = ?1
+where key_hash = ?2
+returning account, workspace
+`
+
+type UseAPIKeyParams struct {
+	Now     *time.Time `json:"now"`
+	KeyHash []byte     `json:"key_hash"`
+}
+
+type UseAPIKeyRow struct {
+	Account   int64 `json:"account"`
+	Workspace int64 `json:"workspace"`
+}
+
+func (q *Queries) UseAPIKey(ctx context.Context, arg UseAPIKeyParams) (UseAPIKeyRow, error) {
+	row := q.db.QueryRowContext(ctx, useAPIKey, arg.Now, arg.KeyHash)
+	var i UseAPIKeyRow
+	err := row.Scan(&i.Account, &i.Workspace)
+	return i, err
+}
@@ -0,0 +1,688 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: presentations.sql
+
+package sqlitequeries
+
+import (
+	"context"
+	"time"
+)
+
+const createPresentation = `-- name: CreatePresentation :one
+INSERT INTO presentation(
+    workspace,
+    name,
+    owner,
+    created_at
+) VALUES (
+    ?1,
+    ?2,
+    ?3,
+    ?4
+)
+RETURNING id, name, owner, workspace, deleted_at, version, created_at
+`
+
+type CreatePresentationParams struct {
+	Workspace int64     `json:"workspace"`
+	Name      string    `json:"name"`
+	Owner     *int64    `json:"owner"`
+	Now       time.Time `json:"now"`
+}
+
+func (q *Queries) CreatePresentation(ctx context.Context, arg CreatePresentationParams) (Presentation, error) {
+	row := q.db.QueryRowContext(ctx, createPresentation,
+		arg.Workspace,
+		arg.Name,
+		arg.Owner,
+		arg.Now,
+	)
+	var i Presentation
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Owner,
+		&i.Workspace,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteGrant = `-- name: DeleteGrant :execrows
+delete from presentation_grant
+where presentation = ?1 and account = ?2
+`
+
+type DeleteGrantParams struct {
+	Presentation int64 `json:"presentation"`
+	Account      int64 `json:"account"`
+}
+
+func (q *Queries) DeleteGrant(ctx context.Context, arg DeleteGrantParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteGrant, arg.Presentation, arg.Account)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deletePresentation = `-- name: DeletePresentation :execrows
+update presentation
+set deleted_at = ?1, version = version + 1
+where id = ?2 and deleted_at is null
+`
+
+type DeletePresentationParams struct {
+	Now *time.Time `json:"now"`
+	ID  int64      `json:"id"`
+}
+
+func (q *Queries) DeletePresentation(ctx context.Context, arg DeletePresentationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deletePresentation, arg.Now, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getDeletedPresentations = `-- name: GetDeletedPresentations :many
+with params as (
+    select
+        cast(?1 as integer) as workspace,
+        cast(?2 as integer) as account
+)
+select presentation.id, presentation.name, presentation.owner, presentation.workspace, presentation.deleted_at, presentation.version, presentation.created_at
+from presentation
+cross join params
+where presentation.deleted_at is not null and presentation.workspace = params.workspace and (
+    params.account is null
+    or presentation.owner = params.account
+)
+order by presentation.deleted_at desc, presentation.id desc
+`
+
+type GetDeletedPresentationsParams struct {
+	Workspace int64  `json:"workspace"`
+	Account   *int64 `json:"account"`
+}
+
+func (q *Queries) GetDeletedPresentations(ctx context.Context, arg GetDeletedPresentationsParams) ([]Presentation, error) {
+	rows, err := q.db.QueryContext(ctx, getDeletedPresentations, arg.Workspace, arg.Account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Presentation
+	for rows.Next() {
+		var i Presentation
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Owner,
+			&i.Workspace,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getGrants = `-- name: GetGrants :many
+select presentation_grant.account, account.username, presentation_grant.role
+from presentation_grant
+inner join account on account.id = presentation_grant.account
+where presentation_grant.presentation = ?1
+order by account.username
+`
+
+type GetGrantsRow struct {
+	Account  int64  `json:"account"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (q *Queries) GetGrants(ctx context.Context, presentationID int64) ([]GetGrantsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getGrants, presentationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetGrantsRow
+	for rows.Next() {
+		var i GetGrantsRow
+		if err := rows.Scan(&i.Account, &i.Username, &i.Role); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPresentation = `-- name: GetPresentation :one
+select id, name, owner, workspace, deleted_at, version, created_at
+from presentation
+where id = ?1 and deleted_at is null
+`
+
+func (q *Queries) GetPresentation(ctx context.Context, id int64) (Presentation, error) {
+	row := q.db.QueryRowContext(ctx, getPresentation, id)
+	var i Presentation
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Owner,
+		&i.Workspace,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getPresentationAccess = `-- name: GetPresentationAccess :one
+select presentation.owner, presentation.deleted_at, presentation_grant.role
+from presentation
+left join presentation_grant
+    on presentation_grant.presentation = presentation.id
+    and presentation_grant.account = ?1
+where presentation.id = ?2 and presentation.workspace = ?3
+`
+
+type GetPresentationAccessParams struct {
+	Account        int64 `json:"account"`
+	PresentationID int64 `json:"presentation_id"`
+	Workspace      int64 `json:"workspace"`
+}
+
+type GetPresentationAccessRow struct {
+	Owner     *int64     `json:"owner"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	Role      *string    `json:"role"`
+}
+
+func (q *Queries) GetPresentationAccess(ctx context.Context, arg GetPresentationAccessParams) (GetPresentationAccessRow, error) {
+	row := q.db.QueryRowContext(ctx, getPresentationAccess, arg.Account, arg.PresentationID, arg.Workspace)
+	var i GetPresentationAccessRow
+	err := row.Scan(&i.Owner, &i.DeletedAt, &i.Role)
+	return i, err
+}
+
+const getPresentations = `-- name: GetPresentations :many
+with params as (
+    select
+        cast(?3 as text) as direction_1,
+        cast(?4 as text) as sort_by_1,
+        cast(?5 as text) as direction_2,
+        cast(?6 as text) as sort_by_2,
+        cast(?7 as text) as direction_3,
+        cast(?8 as text) as sort_by_3,
+        cast(?9 as text) as id_direction,
+        cast(?10 as integer) as cursor_id,
+        cast(?11 as text) as cursor_name,
+        cast(?12 as interval) as cursor_duration,
+        cast(?13 as text) as cursor_created_at,
+        cast(?14 as integer) as workspace,
+        cast(?15 as integer) as account,
+        cast(?16 as boolean) as shared_with_me,
+        cast(?17 as text) as name_contains,
+        cast(?18 as text) as created_after,
+        cast(?19 as text) as search,
+        cast(?20 as interval) as min_duration,
+        cast(?21 as interval) as max_duration
+)
+select presentation.id, presentation.name, presentation.owner, presentation.workspace, presentation.deleted_at, presentation.version, presentation.created_at, cast(coalesce(sum(section.duration), 0) as interval) duration
+from presentation
+cross join params
+left join section on presentation.id = section.presentation and section.deleted_at is null
+where presentation.deleted_at is null and presentation.workspace = params.workspace and (
+    params.account is null
+    or (
+        not params.shared_with_me
+        and presentation.owner = params.account
+    )
+    or exists (
+        select 1
+        from presentation_grant
+        where presentation_grant.presentation = presentation.id
+            and presentation_grant.account = params.account
+    )
+) and (
+    params.name_contains is null
+    or instr(lower(presentation.name), lower(params.name_contains)) > 0
+) and (
+    params.created_after is null or presentation.created_at > params.created_after
+) and (
+    params.search is null
+    or not exists (
+        select 1
+        from json_each(params.search) term
+        where instr(lower(presentation.name), term.value) = 0
+    )
+    or exists (
+        select 1
+        from section searched
+        where searched.presentation = presentation.id and searched.deleted_at is null
+            and not exists (
+                select 1
+                from json_each(params.search) term
+                where instr(lower(searched.name), term.value) = 0
+            )
+    )
+)
+group by presentation.id
+having (
+    params.min_duration is null or coalesce(sum(section.duration), 0) >= params.min_duration
+) and (
+    params.max_duration is null or coalesce(sum(section.duration), 0) <= params.max_duration
+) and (
+    params.cursor_id is null
+    or (
+        case params.sort_by_1
+            when 'name' then case when presentation.name > params.cursor_name then 1 when presentation.name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), 0) > params.cursor_duration then 1 when coalesce(sum(section.duration), 0) < params.cursor_duration then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > params.cursor_created_at then 1 when presentation.created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_1 = 'DESC' then -1 else 1 end,
+        case params.sort_by_2
+            when 'name' then case when presentation.name > params.cursor_name then 1 when presentation.name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), 0) > params.cursor_duration then 1 when coalesce(sum(section.duration), 0) < params.cursor_duration then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > params.cursor_created_at then 1 when presentation.created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_2 = 'DESC' then -1 else 1 end,
+        case params.sort_by_3
+            when 'name' then case when presentation.name > params.cursor_name then 1 when presentation.name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when coalesce(sum(section.duration), 0) > params.cursor_duration then 1 when coalesce(sum(section.duration), 0) < params.cursor_duration then -1 else 0 end
+            when 'created_at' then case when presentation.created_at > params.cursor_created_at then 1 when presentation.created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_3 = 'DESC' then -1 else 1 end,
+        (case when presentation.id > params.cursor_id then 1 when presentation.id < params.cursor_id then -1 else 0 end)
+            * case when params.id_direction = 'DESC' then -1 else 1 end
+    ) > (0, 0, 0, 0)
+)
+order by
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'name'
+        then presentation.name
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'name'
+        then presentation.name
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'created_at'
+        then presentation.created_at
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'name'
+        then presentation.name
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'name'
+        then presentation.name
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'created_at'
+        then presentation.created_at
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'name'
+        then presentation.name
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'name'
+        then presentation.name
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'duration'
+        then coalesce(sum(section.duration), 0)
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'created_at'
+        then presentation.created_at
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'created_at'
+        then presentation.created_at
+    end desc,
+    case when params.id_direction = 'ASC' then presentation.id end asc,
+    case when params.id_direction = 'DESC' then presentation.id end desc
+limit ?2
+offset ?1
+`
+
+type GetPresentationsParams struct {
+	QueryOffset     int64          `json:"query_offset"`
+	QueryLimit      int64          `json:"query_limit"`
+	Direction1      string         `json:"direction_1"`
+	SortBy1         string         `json:"sort_by_1"`
+	Direction2      string         `json:"direction_2"`
+	SortBy2         string         `json:"sort_by_2"`
+	Direction3      string         `json:"direction_3"`
+	SortBy3         string         `json:"sort_by_3"`
+	IDDirection     string         `json:"id_direction"`
+	CursorID        *int64         `json:"cursor_id"`
+	CursorName      string         `json:"cursor_name"`
+	CursorDuration  time.Duration  `json:"cursor_duration"`
+	CursorCreatedAt string         `json:"cursor_created_at"`
+	Workspace       int64          `json:"workspace"`
+	Account         *int64         `json:"account"`
+	SharedWithMe    bool           `json:"shared_with_me"`
+	NameContains    *string        `json:"name_contains"`
+	CreatedAfter    *string        `json:"created_after"`
+	Search          *string        `json:"search"`
+	MinDuration     *time.Duration `json:"min_duration"`
+	MaxDuration     *time.Duration `json:"max_duration"`
+}
+
+type GetPresentationsRow struct {
+	ID        int64         `json:"id"`
+	Name      string        `json:"name"`
+	Owner     *int64        `json:"owner"`
+	Workspace int64         `json:"workspace"`
+	DeletedAt *time.Time    `json:"deleted_at"`
+	Version   int32         `json:"version"`
+	CreatedAt time.Time     `json:"created_at"`
+	Duration  time.Duration `json:"duration"`
+}
+
+func (q *Queries) GetPresentations(ctx context.Context, arg GetPresentationsParams) ([]GetPresentationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPresentations,
+		arg.QueryOffset,
+		arg.QueryLimit,
+		arg.Direction1,
+		arg.SortBy1,
+		arg.Direction2,
+		arg.SortBy2,
+		arg.Direction3,
+		arg.SortBy3,
+		arg.IDDirection,
+		arg.CursorID,
+		arg.CursorName,
+		arg.CursorDuration,
+		arg.CursorCreatedAt,
+		arg.Workspace,
+		arg.Account,
+		arg.SharedWithMe,
+		arg.NameContains,
+		arg.CreatedAfter,
+		arg.Search,
+		arg.MinDuration,
+		arg.MaxDuration,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPresentationsRow
+	for rows.Next() {
+		var i GetPresentationsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Owner,
+			&i.Workspace,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+			&i.Duration,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPresentationsMetadata = `-- name: GetPresentationsMetadata :one
+with params as (
+    select
+        cast(?1 as integer) as workspace,
+        cast(?2 as integer) as account,
+        cast(?3 as boolean) as shared_with_me,
+        cast(?4 as text) as name_contains,
+        cast(?5 as text) as created_after,
+        cast(?6 as text) as search,
+        cast(?7 as interval) as min_duration,
+        cast(?8 as interval) as max_duration
+)
+select count(*)
+from (
+    select presentation.id
+    from presentation
+    cross join params
+    left join section on presentation.id = section.presentation and section.deleted_at is null
+    where presentation.deleted_at is null and presentation.workspace = params.workspace and (
+        params.account is null
+        or (
+            not params.shared_with_me
+            and presentation.owner = params.account
+        )
+        or exists (
+            select 1
+            from presentation_grant
+            where presentation_grant.presentation = presentation.id
+                and presentation_grant.account = params.account
+        )
+    ) and (
+        params.name_contains is null
+        or instr(lower(presentation.name), lower(params.name_contains)) > 0
+    ) and (
+        params.created_after is null or presentation.created_at > params.created_after
+    ) and (
+        params.search is null
+        or not exists (
+            select 1
+            from json_each(params.search) term
+            where instr(lower(presentation.name), term.value) = 0
+        )
+        or exists (
+            select 1
+            from section searched
+            where searched.presentation = presentation.id and searched.deleted_at is null
+                and not exists (
+                    select 1
+                    from json_each(params.search) term
+                    where instr(lower(searched.name), term.value) = 0
+                )
+        )
+    )
+    group by presentation.id
+    having (
+        params.min_duration is null or coalesce(sum(section.duration), 0) >= params.min_duration
+    ) and (
+        params.max_duration is null or coalesce(sum(section.duration), 0) <= params.max_duration
+    )
+) filtered
+`
+
+type GetPresentationsMetadataParams struct {
+	Workspace    int64          `json:"workspace"`
+	Account      *int64         `json:"account"`
+	SharedWithMe bool           `json:"shared_with_me"`
+	NameContains *string        `json:"name_contains"`
+	CreatedAfter *string        `json:"created_after"`
+	Search       *string        `json:"search"`
+	MinDuration  *time.Duration `json:"min_duration"`
+	MaxDuration  *time.Duration `json:"max_duration"`
+}
+
+func (q *Queries) GetPresentationsMetadata(ctx context.Context, arg GetPresentationsMetadataParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getPresentationsMetadata,
+		arg.Workspace,
+		arg.Account,
+		arg.SharedWithMe,
+		arg.NameContains,
+		arg.CreatedAfter,
+		arg.Search,
+		arg.MinDuration,
+		arg.MaxDuration,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const lockPresentation = `-- name: LockPresentation :one
+select id, name, owner, workspace, deleted_at, version, created_at
+from presentation
+where id = ?1 and deleted_at is null
+`
+
+func (q *Queries) LockPresentation(ctx context.Context, id int64) (Presentation, error) {
+	row := q.db.QueryRowContext(ctx, lockPresentation, id)
+	var i Presentation
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Owner,
+		&i.Workspace,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const patchPresentation = `-- name: PatchPresentation :execrows
+UPDATE presentation
+SET name = COALESCE(?1, name), version = version + 1
+WHERE id = ?2 and deleted_at is null
+`
+
+type PatchPresentationParams struct {
+	Name *string `json:"name"`
+	ID   int64   `json:"id"`
+}
+
+func (q *Queries) PatchPresentation(ctx context.Context, arg PatchPresentationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, patchPresentation, arg.Name, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgePresentations = `-- name: PurgePresentations :execrows
+delete from presentation
+where deleted_at < ?1
+`
+
+func (q *Queries) PurgePresentations(ctx context.Context, before *time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgePresentations, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restorePresentation = `-- name: RestorePresentation :execrows
+update presentation
+set deleted_at = null, version = version + 1
+where id = ?1 and deleted_at is not null
+`
+
+func (q *Queries) RestorePresentation(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restorePresentation, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updatePresentation = `-- name: UpdatePresentation :execrows
+UPDATE presentation
+SET name = ?1, version = version + 1
+WHERE id = ?2 and deleted_at is null
+`
+
+type UpdatePresentationParams struct {
+	Name string `json:"name"`
+	ID   int64  `json:"id"`
+}
+
+func (q *Queries) UpdatePresentation(ctx context.Context, arg UpdatePresentationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updatePresentation, arg.Name, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const upsertGrant = `-- name: UpsertGrant :exec
+INSERT INTO presentation_grant (
+    presentation,
+    account,
+    role
+) VALUES (
+    ?1,
+    ?2,
+    ?3
+)
+ON CONFLICT (presentation, account) DO UPDATE
+SET role = excluded.role
+`
+
+type UpsertGrantParams struct {
+	Presentation int64  `json:"presentation"`
+	Account      int64  `json:"account"`
+	Role         string `json:"role"`
+}
+
+func (q *Queries) UpsertGrant(ctx context.Context, arg UpsertGrantParams) error {
+	_, err := q.db.ExecContext(ctx, upsertGrant, arg.Presentation, arg.Account, arg.Role)
+	return err
+}
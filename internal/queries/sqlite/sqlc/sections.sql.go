@@ -0,0 +1,768 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: sections.sql
+
+package sqlitequeries
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const createSection = `-- name: CreateSection :one
+INSERT INTO section (
+    presentation,
+    name,
+    duration,
+    position,
+    created_at
+) VALUES (
+    ?1,
+    ?2,
+    cast(?3 as interval),
+    ?4,
+    ?5
+) RETURNING id, presentation, name, duration, position, deleted_at, version, created_at
+`
+
+type CreateSectionParams struct {
+	Presentation int64         `json:"presentation"`
+	Name         string        `json:"name"`
+	Duration     time.Duration `json:"duration"`
+	Position     int64         `json:"position"`
+	Now          time.Time     `json:"now"`
+}
+
+func (q *Queries) CreateSection(ctx context.Context, arg CreateSectionParams) (Section, error) {
+	row := q.db.QueryRowContext(ctx, createSection,
+		arg.Presentation,
+		arg.Name,
+		arg.Duration,
+		arg.Position,
+		arg.Now,
+	)
+	var i Section
+	err := row.Scan(
+		&i.ID,
+		&i.Presentation,
+		&i.Name,
+		&i.Duration,
+		&i.Position,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteSection = `-- name: DeleteSection :execrows
+update section
+set deleted_at = ?1, version = version + 1
+where id = ?2 and deleted_at is null
+`
+
+type DeleteSectionParams struct {
+	Now *time.Time `json:"now"`
+	ID  int64      `json:"id"`
+}
+
+func (q *Queries) DeleteSection(ctx context.Context, arg DeleteSectionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSection, arg.Now, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getAllSectionsByPosition = `-- name: GetAllSectionsByPosition :many
+select section.id, section.presentation, section.position
+from section
+inner join presentation on presentation.id = section.presentation
+where section.deleted_at is null and presentation.deleted_at is null
+order by section.presentation, section.position, section.id
+`
+
+type GetAllSectionsByPositionRow struct {
+	ID           int64 `json:"id"`
+	Presentation int64 `json:"presentation"`
+	Position     int64 `json:"position"`
+}
+
+func (q *Queries) GetAllSectionsByPosition(ctx context.Context) ([]GetAllSectionsByPositionRow, error) {
+	rows, err := q.db.QueryContext(ctx, getAllSectionsByPosition)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetAllSectionsByPositionRow
+	for rows.Next() {
+		var i GetAllSectionsByPositionRow
+		if err := rows.Scan(&i.ID, &i.Presentation, &i.Position); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDeletedSection = `-- name: GetDeletedSection :one
+select id, presentation, name, duration, position, deleted_at, version, created_at
+from section
+where id = ?1 and deleted_at is not null
+`
+
+func (q *Queries) GetDeletedSection(ctx context.Context, id int64) (Section, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedSection, id)
+	var i Section
+	err := row.Scan(
+		&i.ID,
+		&i.Presentation,
+		&i.Name,
+		&i.Duration,
+		&i.Position,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getDeletedSections = `-- name: GetDeletedSections :many
+with params as (
+    select
+        cast(?1 as integer) as workspace,
+        cast(?2 as integer) as account
+)
+select section.id, section.presentation, section.name, section.duration, section.position, section.deleted_at, section.version, section.created_at
+from section
+cross join params
+inner join presentation on presentation.id = section.presentation
+where section.deleted_at is not null
+    and presentation.deleted_at is null
+    and presentation.workspace = params.workspace
+    and (
+        params.account is null
+        or presentation.owner = params.account
+        or exists (
+            select 1
+            from presentation_grant
+            where presentation_grant.presentation = presentation.id
+                and presentation_grant.account = params.account
+        )
+    )
+order by section.deleted_at desc, section.id desc
+`
+
+type GetDeletedSectionsParams struct {
+	Workspace int64  `json:"workspace"`
+	Account   *int64 `json:"account"`
+}
+
+func (q *Queries) GetDeletedSections(ctx context.Context, arg GetDeletedSectionsParams) ([]Section, error) {
+	rows, err := q.db.QueryContext(ctx, getDeletedSections, arg.Workspace, arg.Account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Section
+	for rows.Next() {
+		var i Section
+		if err := rows.Scan(
+			&i.ID,
+			&i.Presentation,
+			&i.Name,
+			&i.Duration,
+			&i.Position,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSection = `-- name: GetSection :one
+select id, presentation, name, duration, position, deleted_at, version, created_at
+from section
+where id = ?1 and deleted_at is null
+`
+
+func (q *Queries) GetSection(ctx context.Context, id int64) (Section, error) {
+	row := q.db.QueryRowContext(ctx, getSection, id)
+	var i Section
+	err := row.Scan(
+		&i.ID,
+		&i.Presentation,
+		&i.Name,
+		&i.Duration,
+		&i.Position,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getSectionPresentation = `-- name: GetSectionPresentation :one
+select presentation
+from section
+where id = ?1
+`
+
+func (q *Queries) GetSectionPresentation(ctx context.Context, id int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getSectionPresentation, id)
+	var presentation int64
+	err := row.Scan(&presentation)
+	return presentation, err
+}
+
+const getSections = `-- name: GetSections :many
+with params as (
+    select
+        cast(?3 as text) as direction_1,
+        cast(?4 as text) as sort_by_1,
+        cast(?5 as text) as direction_2,
+        cast(?6 as text) as sort_by_2,
+        cast(?7 as text) as direction_3,
+        cast(?8 as text) as sort_by_3,
+        cast(?9 as text) as id_direction,
+        cast(?10 as integer) as cursor_id,
+        cast(?11 as text) as cursor_name,
+        cast(?12 as interval) as cursor_duration,
+        cast(?13 as integer) as cursor_position,
+        cast(?14 as text) as cursor_created_at,
+        cast(?15 as integer) as presentation_id,
+        cast(?16 as text) as name_contains,
+        cast(?17 as interval) as min_duration,
+        cast(?18 as interval) as max_duration,
+        cast(?19 as text) as created_after,
+        cast(?20 as text) as search
+)
+select section.id, section.presentation, section.name, section.duration, section.position, section.deleted_at, section.version, section.created_at
+from section
+cross join params
+where presentation = params.presentation_id and deleted_at is null and (
+    params.name_contains is null or instr(lower(name), lower(params.name_contains)) > 0
+) and (
+    params.min_duration is null or duration >= params.min_duration
+) and (
+    params.max_duration is null or duration <= params.max_duration
+) and (
+    params.created_after is null or created_at > params.created_after
+) and (
+    params.search is null
+    or not exists (
+        select 1
+        from json_each(params.search) term
+        where instr(lower(name), term.value) = 0
+    )
+) and (
+    params.cursor_id is null
+    or (
+        case params.sort_by_1
+            when 'name' then case when name > params.cursor_name then 1 when name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when duration > params.cursor_duration then 1 when duration < params.cursor_duration then -1 else 0 end
+            when 'position' then case when position > params.cursor_position then 1 when position < params.cursor_position then -1 else 0 end
+            when 'created_at' then case when created_at > params.cursor_created_at then 1 when created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_1 = 'DESC' then -1 else 1 end,
+        case params.sort_by_2
+            when 'name' then case when name > params.cursor_name then 1 when name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when duration > params.cursor_duration then 1 when duration < params.cursor_duration then -1 else 0 end
+            when 'position' then case when position > params.cursor_position then 1 when position < params.cursor_position then -1 else 0 end
+            when 'created_at' then case when created_at > params.cursor_created_at then 1 when created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_2 = 'DESC' then -1 else 1 end,
+        case params.sort_by_3
+            when 'name' then case when name > params.cursor_name then 1 when name < params.cursor_name then -1 else 0 end
+            when 'duration' then case when duration > params.cursor_duration then 1 when duration < params.cursor_duration then -1 else 0 end
+            when 'position' then case when position > params.cursor_position then 1 when position < params.cursor_position then -1 else 0 end
+            when 'created_at' then case when created_at > params.cursor_created_at then 1 when created_at < params.cursor_created_at then -1 else 0 end
+            else 0
+        end * case when params.direction_3 = 'DESC' then -1 else 1 end,
+        (case when id > params.cursor_id then 1 when id < params.cursor_id then -1 else 0 end)
+            * case when params.id_direction = 'DESC' then -1 else 1 end
+    ) > (0, 0, 0, 0)
+)
+order by
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'name'
+        then name
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'name'
+        then name
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'duration'
+        then duration
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'duration'
+        then duration
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'position'
+        then position
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'position'
+        then position
+    end desc,
+    case
+        when params.direction_1 = 'ASC' and params.sort_by_1 = 'created_at'
+        then created_at
+    end asc,
+    case
+        when params.direction_1 = 'DESC' and params.sort_by_1 = 'created_at'
+        then created_at
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'name'
+        then name
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'name'
+        then name
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'duration'
+        then duration
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'duration'
+        then duration
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'position'
+        then position
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'position'
+        then position
+    end desc,
+    case
+        when params.direction_2 = 'ASC' and params.sort_by_2 = 'created_at'
+        then created_at
+    end asc,
+    case
+        when params.direction_2 = 'DESC' and params.sort_by_2 = 'created_at'
+        then created_at
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'name'
+        then name
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'name'
+        then name
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'duration'
+        then duration
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'duration'
+        then duration
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'position'
+        then position
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'position'
+        then position
+    end desc,
+    case
+        when params.direction_3 = 'ASC' and params.sort_by_3 = 'created_at'
+        then created_at
+    end asc,
+    case
+        when params.direction_3 = 'DESC' and params.sort_by_3 = 'created_at'
+        then created_at
+    end desc,
+    case when params.id_direction = 'ASC' then id end asc,
+    case when params.id_direction = 'DESC' then id end desc
+limit ?2
+offset ?1
+`
+
+type GetSectionsParams struct {
+	QueryOffset     int64          `json:"query_offset"`
+	QueryLimit      int64          `json:"query_limit"`
+	Direction1      string         `json:"direction_1"`
+	SortBy1         string         `json:"sort_by_1"`
+	Direction2      string         `json:"direction_2"`
+	SortBy2         string         `json:"sort_by_2"`
+	Direction3      string         `json:"direction_3"`
+	SortBy3         string         `json:"sort_by_3"`
+	IDDirection     string         `json:"id_direction"`
+	CursorID        *int64         `json:"cursor_id"`
+	CursorName      string         `json:"cursor_name"`
+	CursorDuration  time.Duration  `json:"cursor_duration"`
+	CursorPosition  int64          `json:"cursor_position"`
+	CursorCreatedAt string         `json:"cursor_created_at"`
+	PresentationID  int64          `json:"presentation_id"`
+	NameContains    *string        `json:"name_contains"`
+	MinDuration     *time.Duration `json:"min_duration"`
+	MaxDuration     *time.Duration `json:"max_duration"`
+	CreatedAfter    *string        `json:"created_after"`
+	Search          *string        `json:"search"`
+}
+
+func (q *Queries) GetSections(ctx context.Context, arg GetSectionsParams) ([]Section, error) {
+	rows, err := q.db.QueryContext(ctx, getSections,
+		arg.QueryOffset,
+		arg.QueryLimit,
+		arg.Direction1,
+		arg.SortBy1,
+		arg.Direction2,
+		arg.SortBy2,
+		arg.Direction3,
+		arg.SortBy3,
+		arg.IDDirection,
+		arg.CursorID,
+		arg.CursorName,
+		arg.CursorDuration,
+		arg.CursorPosition,
+		arg.CursorCreatedAt,
+		arg.PresentationID,
+		arg.NameContains,
+		arg.MinDuration,
+		arg.MaxDuration,
+		arg.CreatedAfter,
+		arg.Search,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Section
+	for rows.Next() {
+		var i Section
+		if err := rows.Scan(
+			&i.ID,
+			&i.Presentation,
+			&i.Name,
+			&i.Duration,
+			&i.Position,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSectionsByPosition = `-- name: GetSectionsByPosition :many
+select id, presentation, name, duration, position, deleted_at, version, created_at
+from section
+where presentation = ?1 and deleted_at is null
+order by position, id
+`
+
+func (q *Queries) GetSectionsByPosition(ctx context.Context, presentationID int64) ([]Section, error) {
+	rows, err := q.db.QueryContext(ctx, getSectionsByPosition, presentationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Section
+	for rows.Next() {
+		var i Section
+		if err := rows.Scan(
+			&i.ID,
+			&i.Presentation,
+			&i.Name,
+			&i.Duration,
+			&i.Position,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getSectionsMetadata = `-- name: GetSectionsMetadata :one
+with params as (
+    select
+        cast(?1 as integer) as presentation_id,
+        cast(?2 as text) as name_contains,
+        cast(?3 as interval) as min_duration,
+        cast(?4 as interval) as max_duration,
+        cast(?5 as text) as created_after,
+        cast(?6 as text) as search
+)
+select count(*)
+from section
+cross join params
+where presentation = params.presentation_id and deleted_at is null and (
+    params.name_contains is null or instr(lower(name), lower(params.name_contains)) > 0
+) and (
+    params.min_duration is null or duration >= params.min_duration
+) and (
+    params.max_duration is null or duration <= params.max_duration
+) and (
+    params.created_after is null or created_at > params.created_after
+) and (
+    params.search is null
+    or not exists (
+        select 1
+        from json_each(params.search) term
+        where instr(lower(name), term.value) = 0
+    )
+)
+`
+
+type GetSectionsMetadataParams struct {
+	PresentationID int64          `json:"presentation_id"`
+	NameContains   *string        `json:"name_contains"`
+	MinDuration    *time.Duration `json:"min_duration"`
+	MaxDuration    *time.Duration `json:"max_duration"`
+	CreatedAfter   *string        `json:"created_after"`
+	Search         *string        `json:"search"`
+}
+
+func (q *Queries) GetSectionsMetadata(ctx context.Context, arg GetSectionsMetadataParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getSectionsMetadata,
+		arg.PresentationID,
+		arg.NameContains,
+		arg.MinDuration,
+		arg.MaxDuration,
+		arg.CreatedAfter,
+		arg.Search,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const getSectionsOfPresentations = `-- name: GetSectionsOfPresentations :many
+select id, presentation, name, duration, position, deleted_at, version, created_at
+from section
+where presentation in (/*SLICE:presentation_ids*/?) and deleted_at is null
+order by presentation, position, id
+`
+
+func (q *Queries) GetSectionsOfPresentations(ctx context.Context, presentationIds []int64) ([]Section, error) {
+	query := getSectionsOfPresentations
+	var queryParams []interface{}
+	if len(presentationIds) > 0 {
+		for _, v := range presentationIds {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:presentation_ids*/?", strings.Repeat(",?", len(presentationIds))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:presentation_ids*/?", "NULL", 1)
+	}
+	rows, err := q.db.QueryContext(ctx, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Section
+	for rows.Next() {
+		var i Section
+		if err := rows.Scan(
+			&i.ID,
+			&i.Presentation,
+			&i.Name,
+			&i.Duration,
+			&i.Position,
+			&i.DeletedAt,
+			&i.Version,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockSection = `-- name: LockSection :one
+select id, presentation, name, duration, position, deleted_at, version, created_at
+from section
+where id = ?1 and deleted_at is null
+`
+
+func (q *Queries) LockSection(ctx context.Context, id int64) (Section, error) {
+	row := q.db.QueryRowContext(ctx, lockSection, id)
+	var i Section
+	err := row.Scan(
+		&i.ID,
+		&i.Presentation,
+		&i.Name,
+		&i.Duration,
+		&i.Position,
+		&i.DeletedAt,
+		&i.Version,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const maxPosition = `-- name: MaxPosition :one
+select cast(coalesce(max(position), 0) as integer)
+from section
+where presentation = ?1 and deleted_at is null
+`
+
+func (q *Queries) MaxPosition(ctx context.Context, presentationID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, maxPosition, presentationID)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const patchSection = `-- name: PatchSection :execrows
+UPDATE section
+SET
+    name = COALESCE(?1, name),
+    duration = COALESCE(cast(?2 as interval), duration),
+    position = COALESCE(?3, position),
+    version = version + 1
+WHERE
+    id = ?4 and deleted_at is null
+`
+
+type PatchSectionParams struct {
+	Name     *string        `json:"name"`
+	Duration *time.Duration `json:"duration"`
+	Position *int64         `json:"position"`
+	ID       int64          `json:"id"`
+}
+
+func (q *Queries) PatchSection(ctx context.Context, arg PatchSectionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, patchSection,
+		arg.Name,
+		arg.Duration,
+		arg.Position,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const purgeSections = `-- name: PurgeSections :execrows
+delete from section
+where deleted_at < ?1
+`
+
+func (q *Queries) PurgeSections(ctx context.Context, before *time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, purgeSections, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreSection = `-- name: RestoreSection :execrows
+update section
+set deleted_at = null, position = ?1, version = version + 1
+where id = ?2 and deleted_at is not null
+`
+
+type RestoreSectionParams struct {
+	Position int64 `json:"position"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) RestoreSection(ctx context.Context, arg RestoreSectionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreSection, arg.Position, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setPosition = `-- name: SetPosition :exec
+update section
+set position = ?1, version = version + 1
+where id = ?2
+`
+
+type SetPositionParams struct {
+	Position int64 `json:"position"`
+	ID       int64 `json:"id"`
+}
+
+func (q *Queries) SetPosition(ctx context.Context, arg SetPositionParams) error {
+	_, err := q.db.ExecContext(ctx, setPosition, arg.Position, arg.ID)
+	return err
+}
+
+const updateSection = `-- name: UpdateSection :execrows
+UPDATE section
+SET
+    name = ?1,
+    duration = cast(?2 as interval),
+    position = ?3,
+    version = version + 1
+WHERE
+    id = ?4 and deleted_at is null
+`
+
+type UpdateSectionParams struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Position int64         `json:"position"`
+	ID       int64         `json:"id"`
+}
+
+func (q *Queries) UpdateSection(ctx context.Context, arg UpdateSectionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateSection,
+		arg.Name,
+		arg.Duration,
+		arg.Position,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
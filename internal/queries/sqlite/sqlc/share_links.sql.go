@@ -0,0 +1,123 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: share_links.sql
+
+package sqlitequeries
+
+import (
+	"context"
+	"time"
+)
+
+const createShareLink = `-- name: CreateShareLink :one
+INSERT INTO share_link (
+    presentation,
+    token_hash,
+    expires_at
+) VALUES (
+    ?1,
+    ?2,
+    ?3
+) RETURNING id, presentation, token_hash, created_at, expires_at
+`
+
+type CreateShareLinkParams struct {
+	Presentation int64      `json:"presentation"`
+	TokenHash    []byte     `json:"token_hash"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateShareLink(ctx context.Context, arg CreateShareLinkParams) (ShareLink, error) {
+	row := q.db.QueryRowContext(ctx, createShareLink, arg.Presentation, arg.TokenHash, arg.ExpiresAt)
+	var i ShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.Presentation,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const deleteShareLink = `-- name: DeleteShareLink :execrows
+delete from share_link
+where id = ?1 and presentation = ?2
+`
+
+type DeleteShareLinkParams struct {
+	ID           int64 `json:"id"`
+	Presentation int64 `json:"presentation"`
+}
+
+func (q *Queries) DeleteShareLink(ctx context.Context, arg DeleteShareLinkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteShareLink, arg.ID, arg.Presentation)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getShareLinks = `-- name: GetShareLinks :many
+select id, presentation, token_hash, created_at, expires_at
+from share_link
+where presentation = ?1
+order by id
+`
+
+func (q *Queries) GetShareLinks(ctx context.Context, presentationID int64) ([]ShareLink, error) {
+	rows, err := q.db.QueryContext(ctx, getShareLinks, presentationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ShareLink
+	for rows.Next() {
+		var i ShareLink
+		if err := rows.Scan(
+			&i.ID,
+			&i.Presentation,
+			&i.TokenHash,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getValidShareLink = `-- name: GetValidShareLink :one
+select share_link.id, share_link.presentation, share_link.token_hash, share_link.created_at, share_link.expires_at
+from share_link
+inner join presentation on presentation.id = share_link.presentation
+where share_link.token_hash = ?1
+    and (share_link.expires_at is null or share_link.expires_at > ?2)
+    and presentation.deleted_at is null
+`
+
+type GetValidShareLinkParams struct {
+	TokenHash []byte     `json:"token_hash"`
+	Now       *time.Time `json:"now"`
+}
+
+func (q *Queries) GetValidShareLink(ctx context.Context, arg GetValidShareLinkParams) (ShareLink, error) {
+	row := q.db.QueryRowContext(ctx, getValidShareLink, arg.TokenHash, arg.Now)
+	var i ShareLink
+	err := row.Scan(
+		&i.ID,
+		&i.Presentation,
+		&i.TokenHash,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
@@ -0,0 +1,200 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.27.0
+// source: workspaces.sql
+
+package sqlitequeries
+
+import (
+	"context"
+	"time"
+)
+
+const createWorkspace = `-- name: CreateWorkspace :one
+INSERT INTO workspace (
+    name
+) VALUES (
+    ?1
+) RETURNING id, name, created_at
+`
+
+func (q *Queries) CreateWorkspace(ctx context.Context, name string) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, createWorkspace, name)
+	var i Workspace
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const deleteWorkspaceMember = `-- name: DeleteWorkspaceMember :execrows
+delete from workspace_member
+where workspace = ?1 and account = ?2
+`
+
+type DeleteWorkspaceMemberParams struct {
+	Workspace int64 `json:"workspace"`
+	Account   int64 `json:"account"`
+}
+
+func (q *Queries) DeleteWorkspaceMember(ctx context.Context, arg DeleteWorkspaceMemberParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWorkspaceMember, arg.Workspace, arg.Account)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getDefaultWorkspace = `-- name: GetDefaultWorkspace :one
+select workspace, role
+from workspace_member
+where account = ?1
+order by workspace
+limit 1
+`
+
+type GetDefaultWorkspaceRow struct {
+	Workspace int64  `json:"workspace"`
+	Role      string `json:"role"`
+}
+
+func (q *Queries) GetDefaultWorkspace(ctx context.Context, account int64) (GetDefaultWorkspaceRow, error) {
+	row := q.db.QueryRowContext(ctx, getDefaultWorkspace, account)
+	var i GetDefaultWorkspaceRow
+	err := row.Scan(&i.Workspace, &i.Role)
+	return i, err
+}
+
+const getWorkspace = `-- name: GetWorkspace :one
+select id, name, created_at
+from workspace
+where id = ?1
+`
+
+func (q *Queries) GetWorkspace(ctx context.Context, id int64) (Workspace, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspace, id)
+	var i Workspace
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const getWorkspaceMember = `-- name: GetWorkspaceMember :one
+select role
+from workspace_member
+where workspace = ?1 and account = ?2
+`
+
+type GetWorkspaceMemberParams struct {
+	Workspace int64 `json:"workspace"`
+	Account   int64 `json:"account"`
+}
+
+func (q *Queries) GetWorkspaceMember(ctx context.Context, arg GetWorkspaceMemberParams) (string, error) {
+	row := q.db.QueryRowContext(ctx, getWorkspaceMember, arg.Workspace, arg.Account)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
+const getWorkspaceMembers = `-- name: GetWorkspaceMembers :many
+select workspace_member.account, account.username, workspace_member.role
+from workspace_member
+inner join account on account.id = workspace_member.account
+where workspace_member.workspace = ?1
+order by account.username
+`
+
+type GetWorkspaceMembersRow struct {
+	Account  int64  `json:"account"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+func (q *Queries) GetWorkspaceMembers(ctx context.Context, workspace int64) ([]GetWorkspaceMembersRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaceMembers, workspace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspaceMembersRow
+	for rows.Next() {
+		var i GetWorkspaceMembersRow
+		if err := rows.Scan(&i.Account, &i.Username, &i.Role); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getWorkspaces = `-- name: GetWorkspaces :many
+select workspace.id, workspace.name, workspace.created_at, workspace_member.role
+from workspace
+inner join workspace_member on workspace_member.workspace = workspace.id
+where workspace_member.account = ?1
+order by workspace.id
+`
+
+type GetWorkspacesRow struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Role      string    `json:"role"`
+}
+
+func (q *Queries) GetWorkspaces(ctx context.Context, account int64) ([]GetWorkspacesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getWorkspaces, account)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetWorkspacesRow
+	for rows.Next() {
+		var i GetWorkspacesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CreatedAt,
+			&i.Role,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const upsertWorkspaceMember = `-- name: UpsertWorkspaceMember :exec
+INSERT INTO workspace_member (
+    workspace,
+    account,
+    role
+) VALUES (
+    ?1,
+    ?2,
+    ?3
+)
+ON CONFLICT (workspace, account) DO UPDATE
+SET role = excluded.role
+`
+
+type UpsertWorkspaceMemberParams struct {
+	Workspace int64  `json:"workspace"`
+	Account   int64  `json:"account"`
+	Role      string `json:"role"`
+}
+
+func (q *Queries) UpsertWorkspaceMember(ctx context.Context, arg UpsertWorkspaceMemberParams) error {
+	_, err := q.db.ExecContext(ctx, upsertWorkspaceMember, arg.Workspace, arg.Account, arg.Role)
+	return err
+}
@@ -0,0 +1,44 @@
+-- name: CreatePresentationVersion :one
+INSERT INTO presentation_version (
+    presentation,
+    version,
+    name,
+    sections,
+    actor
+) VALUES (
+    @presentation,
+    (
+        select coalesce(max(version), 0) + 1
+        from presentation_version
+        where presentation = @presentation
+    ),
+    @name,
+    @sections,
+    sqlc.narg(actor)
+) RETURNING *;
+--
+-- name: GetPresentationVersions :many
+select
+    presentation_version.presentation,
+    presentation_version.version,
+    presentation_version.name,
+    presentation_version.created_at,
+    account.username actor_username
+from presentation_version
+left join account on account.id = presentation_version.actor
+where presentation_version.presentation = @presentation_id
+order by presentation_version.version desc
+limit @query_limit
+offset @query_offset;
+--
+-- name: GetPresentationVersionsMetadata :one
+select count(*)
+from presentation_version
+where presentation = @presentation_id;
+--
+-- name: GetPresentationVersion :one
+select presentation_version.*, account.username actor_username
+from presentation_version
+left join account on account.id = presentation_version.actor
+where presentation_version.presentation = @presentation_id
+    and presentation_version.version = @version;
@@ -0,0 +1,60 @@
+-- name: CreateWorkspace :one
+INSERT INTO workspace (
+    name
+) VALUES (
+    @name
+) RETURNING *;
+--
+-- name: GetWorkspace :one
+select *
+from workspace
+where id = @id
+;
+--
+-- name: GetWorkspaces :many
+select workspace.*, workspace_member.role
+from workspace
+inner join workspace_member on workspace_member.workspace = workspace.id
+where workspace_member.account = @account
+order by workspace.id
+;
+--
+-- name: GetWorkspaceMember :one
+select role
+from workspace_member
+where workspace = @workspace and account = @account
+;
+--
+-- name: GetDefaultWorkspace :one
+select workspace, role
+from workspace_member
+where account = @account
+order by workspace
+limit 1
+;
+--
+-- name: GetWorkspaceMembers :many
+select workspace_member.account, account.username, workspace_member.role
+from workspace_member
+inner join account on account.id = workspace_member.account
+where workspace_member.workspace = @workspace
+order by account.username
+;
+--
+-- name: UpsertWorkspaceMember :exec
+INSERT INTO workspace_member (
+    workspace,
+    account,
+    role
+) VALUES (
+    @workspace,
+    @account,
+    @role
+)
+ON CONFLICT (workspace, account) DO UPDATE
+SET role = excluded.role;
+--
+-- name: DeleteWorkspaceMember :execrows
+delete from workspace_member
+where workspace = @workspace and account = @account
+;
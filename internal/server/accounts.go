@@ -0,0 +1,412 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/auth"
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+type AccountResponse struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type APIKeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	Workspace  int64      `json:"workspace"`
+	// Key is only sent when the key is created
+	Key string `json:"key,omitempty"`
+}
+
+func CreateAccountHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		Username *string `json:"username"`
+		Password *string `json:"password"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		if err := helpers.ReadJSON(r.Body, &input); err != nil {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v := validation.New()
+		ValidateUsername(v, input.Username)
+		ValidatePassword(v, input.Password)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		passwordHash, err := auth.HashPassword(*input.Password)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		var account queries.Account
+		err = queriesStore.InTx(ctx, func(tx store.Store) error {
+			// concurrent signups could otherwise all count no accounts and
+			// become admins of the default workspace
+			if err := tx.LockAccounts(ctx); err != nil {
+				return err
+			}
+
+			accounts, err := tx.CountAccounts(ctx)
+			if err != nil {
+				return err
+			}
+			if !conf.AllowSignup && accounts > 0 {
+				return errSignupDisabled
+			}
+
+			account, err = tx.CreateAccount(ctx, queries.CreateAccountParams{
+				Username:     *input.Username,
+				PasswordHash: passwordHash,
+			})
+			if err != nil {
+				return err
+			}
+
+			// the first account administers the default workspace, which holds
+			// everything created while authentication was disabled
+			if accounts == 0 {
+				if err := tx.UpsertWorkspaceMember(ctx, queries.UpsertWorkspaceMemberParams{
+					Workspace: store.DefaultWorkspace,
+					Account:   account.ID,
+					Role:      WorkspaceRoleAdmin,
+				}); err != nil {
+					return err
+				}
+			}
+
+			// every account starts with a personal workspace it administers
+			workspace, err := tx.CreateWorkspace(ctx, account.Username)
+			if err != nil {
+				return err
+			}
+
+			return tx.UpsertWorkspaceMember(ctx, queries.UpsertWorkspaceMemberParams{
+				Workspace: workspace.ID,
+				Account:   account.ID,
+				Role:      WorkspaceRoleAdmin,
+			})
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, errSignupDisabled):
+				helpers.Forbidden(w, err.Error())
+			case errors.Is(err, store.ErrConflict):
+				v := validation.New()
+				v.AddErrors("username", "username is already taken")
+				helpers.UnprocessableContent(w, v.Errors())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusCreated, AccountResponse{
+			ID:        account.ID,
+			Username:  account.Username,
+			CreatedAt: account.CreatedAt,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+var errSignupDisabled = errors.New("signup is disabled")
+
+func GetCurrentAccountHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current, ok := auth.AccountFromContext(r.Context())
+		if !ok {
+			helpers.Unauthorized(w, "authentication required")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		account, err := queriesStore.GetAccount(ctx, current.ID)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, AccountResponse{
+			ID:        account.ID,
+			Username:  account.Username,
+			CreatedAt: account.CreatedAt,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func CreateSessionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		Username *string `json:"username"`
+		Password *string `json:"password"`
+	}
+
+	type output struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		if err := helpers.ReadJSON(r.Body, &input); err != nil {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v := validation.New()
+		v.Check("username", input.Username, validation.CheckPointerNotNil("username must be given"))
+		v.Check("password", input.Password, validation.CheckPointerNotNil("password must be given"))
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		account, err := queriesStore.GetAccountByUsername(ctx, *input.Username)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		var valid bool
+		if errors.Is(err, sql.ErrNoRows) {
+			valid = auth.CheckNoPassword(*input.Password)
+		} else {
+			valid = auth.CheckPassword(account.PasswordHash, *input.Password)
+		}
+		if !valid {
+			helpers.Unauthorized(w, "invalid username or password")
+			return
+		}
+
+		token, tokenHash, err := auth.NewToken(auth.SessionPrefix)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		expiresAt := time.Now().Add(conf.SessionTTL)
+		if err := queriesStore.CreateSession(ctx, queries.CreateSessionParams{
+			TokenHash: tokenHash,
+			Account:   account.ID,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusCreated, output{
+			Token:     token,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func DeleteCurrentSessionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := requestToken(r)
+		if token == "" || auth.IsAPIKey(token) {
+			http.NotFound(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		rows, err := queriesStore.DeleteSession(ctx, auth.HashToken(token))
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+		if rows == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func ListAPIKeysHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type output struct {
+		Data []APIKeyResponse `json:"data"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account, ok := auth.AccountFromContext(r.Context())
+		if !ok {
+			helpers.Unauthorized(w, "authentication required")
+			return
+		}
+
+		workspace, err := workspaceID(r)
+		if err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		keys, err := queriesStore.GetAPIKeys(ctx, queries.GetAPIKeysParams{
+			Account:   account.ID,
+			Workspace: workspace,
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		data := make([]APIKeyResponse, 0, len(keys))
+		for _, key := range keys {
+			data = append(data, APIKeyResponse{
+				ID:         key.ID,
+				Name:       key.Name,
+				CreatedAt:  key.CreatedAt,
+				LastUsedAt: key.LastUsedAt,
+				Workspace:  key.Workspace,
+			})
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{Data: data}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func CreateAPIKeyHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		Name *string `json:"name"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		account, ok := auth.AccountFromContext(r.Context())
+		if !ok {
+			helpers.Unauthorized(w, "authentication required")
+			return
+		}
+
+		if err := helpers.ReadJSON(r.Body, &input); err != nil {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v := validation.New()
+		ValidateAPIKeyName(v, input.Name)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		workspace, err := workspaceID(r)
+		if err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		token, keyHash, err := auth.NewToken(auth.APIKeyPrefix)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		key, err := queriesStore.CreateAPIKey(ctx, queries.CreateAPIKeyParams{
+			Account:   account.ID,
+			Workspace: workspace,
+			Name:      *input.Name,
+			KeyHash:   keyHash,
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusCreated, APIKeyResponse{
+			ID:         key.ID,
+			Name:       key.Name,
+			CreatedAt:  key.CreatedAt,
+			LastUsedAt: key.LastUsedAt,
+			Workspace:  key.Workspace,
+			Key:        token,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func DeleteAPIKeyHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account, ok := auth.AccountFromContext(r.Context())
+		if !ok {
+			helpers.Unauthorized(w, "authentication required")
+			return
+		}
+
+		ID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		rows, err := queriesStore.DeleteAPIKey(ctx, queries.DeleteAPIKeyParams{
+			ID:      ID,
+			Account: account.ID,
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+		if rows == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/PabloVarg/presentation-timer/internal/migrate"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+)
+
+func TestConcurrentFirstSignups(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	stores := map[string]func(t *testing.T) store.Store{
+		"memory": func(t *testing.T) store.Store {
+			return store.NewMemory()
+		},
+		"sqlite": func(t *testing.T) store.Store {
+			db, err := store.OpenSQLite(filepath.Join(t.TempDir(), "signup.db"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { db.Close() })
+
+			migrator, err := migrate.NewSQLite(logger, db)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := migrator.Up(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			return store.NewSQLite(db)
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			queriesStore := newStore(t)
+
+			// signup is closed, only the first account can be created
+			handler := routes(logger, queriesStore, DefaultConfig())
+
+			const signups = 10
+			statuses := make([]int, signups)
+
+			var wg sync.WaitGroup
+			for i := range signups {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+
+					body := fmt.Sprintf(`{"username": "user%d", "password": "secret123"}`, i)
+					w := httptest.NewRecorder()
+					handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/accounts", strings.NewReader(body)))
+					statuses[i] = w.Code
+				}()
+			}
+			wg.Wait()
+
+			created := 0
+			for i, status := range statuses {
+				switch status {
+				case http.StatusCreated:
+					created++
+				case http.StatusForbidden:
+				default:
+					t.Errorf("signup %d answered %d", i, status)
+				}
+			}
+			if created != 1 {
+				t.Errorf("%d accounts were created, want 1", created)
+			}
+
+			members, err := queriesStore.GetWorkspaceMembers(context.Background(), store.DefaultWorkspace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(members) != 1 || members[0].Role != WorkspaceRoleAdmin {
+				t.Errorf("default workspace members are %+v, want a single admin", members)
+			}
+		})
+	}
+}
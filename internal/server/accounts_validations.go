@@ -0,0 +1,37 @@
+package server
+
+import "github.com/PabloVarg/presentation-timer/internal/validation"
+
+func ValidateUsername(v validation.Validator, username *string) {
+	v.Check(
+		"username",
+		username,
+		validation.CheckPointerNotNil("username must be given"),
+		validation.StringCheckNotEmpty("username can not be empty"),
+		validation.StringCheckLength(3, 50, "username must be between 3 and 50 characters"),
+	)
+}
+
+// ValidatePassword checks the length of a new password, bcrypt ignores
+// anything past 72 bytes.
+func ValidatePassword(v validation.Validator, password *string) {
+	v.Check(
+		"password",
+		password,
+		validation.CheckPointerNotNil("password must be given"),
+		validation.StringCheckMinLen(8, "password must be at least 8 characters"),
+		func(value any) (bool, string) {
+			return password == nil || len(*password) <= 72, "password must be at most 72 bytes"
+		},
+	)
+}
+
+func ValidateAPIKeyName(v validation.Validator, name *string) {
+	v.Check(
+		"name",
+		name,
+		validation.CheckPointerNotNil("name must be given"),
+		validation.StringCheckNotEmpty("name can not be empty"),
+		validation.StringCheckMaxLen(50, "name must be at most 50 characters"),
+	)
+}
@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/filters"
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+)
+
+const AuditPageSize = 20
+
+// Actions recorded in the audit log.
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionPatch   = "patch"
+	AuditActionDelete  = "delete"
+	AuditActionMove    = "move"
+	AuditActionRestore = "restore"
+)
+
+type AuditEntryResponse struct {
+	ID      int64  `json:"id"`
+	Section *int64 `json:"section"`
+	// Actor is the username of the account that made the change, nil when
+	// authentication was disabled
+	Actor  *string `json:"actor"`
+	Action string  `json:"action"`
+	// Diff maps every changed field to its value before and after the change
+	Diff      json.RawMessage `json:"diff"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// AuditFieldChange is the value of a field before and after a change.
+type AuditFieldChange struct {
+	Before any `json:"before"`
+	After  any `json:"after"`
+}
+
+func ListAuditEntriesHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type output struct {
+		Data     []AuditEntryResponse `json:"data"`
+		PageInfo filters.PageInfo     `json:"page_info"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		f, v := filters.FromRequest(r, conf.AuditPageSize)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionView); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		entries, err := queriesStore.GetAuditEntries(ctx, queries.GetAuditEntriesParams{
+			PresentationID: presentationID,
+			QueryOffset:    f.QueryOffset(),
+			QueryLimit:     f.QueryLimit(),
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		totalRows, err := queriesStore.GetAuditEntriesMetadata(ctx, presentationID)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		data := make([]AuditEntryResponse, 0, len(entries))
+		for _, entry := range entries {
+			data = append(data, AuditEntryResponse{
+				ID:        entry.ID,
+				Section:   entry.Section,
+				Actor:     entry.ActorUsername,
+				Action:    entry.Action,
+				Diff:      entry.Diff,
+				CreatedAt: entry.CreatedAt,
+			})
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{
+			Data:     data,
+			PageInfo: f.PageInfo(totalRows),
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+// auditChange is a change made to a presentation, or to one of its sections
+// when Section is set. Before is nil for creations and After for deletions.
+type auditChange struct {
+	Presentation int64
+	Section      *int64
+	Action       string
+	Before       any
+	After        any
+}
+
+// recordAudit stores change in the audit log along with the account of r, it
+// must be called in the same transaction as the change.
+func recordAudit(ctx context.Context, tx store.Store, r *http.Request, change auditChange) error {
+	diff, err := auditDiff(change.Before, change.After)
+	if err != nil {
+		return err
+	}
+
+	return tx.CreateAuditEntry(ctx, queries.CreateAuditEntryParams{
+		Presentation: change.Presentation,
+		Section:      change.Section,
+		Actor:        accountID(r),
+		Action:       change.Action,
+		Diff:         diff,
+	})
+}
+
+// auditDiff returns the JSON encoded fields that differ between before and
+// after, as they are sent to clients.
+func auditDiff(before, after any) ([]byte, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := make(map[string]AuditFieldChange)
+	for field, value := range beforeFields {
+		if !reflect.DeepEqual(value, afterFields[field]) {
+			diff[field] = AuditFieldChange{Before: value, After: afterFields[field]}
+		}
+	}
+	for field, value := range afterFields {
+		if _, ok := beforeFields[field]; !ok && value != nil {
+			diff[field] = AuditFieldChange{After: value}
+		}
+	}
+
+	return json.Marshal(diff)
+}
+
+func auditFields(record any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if record == nil {
+		return fields, nil
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
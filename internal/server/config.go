@@ -0,0 +1,45 @@
+package server
+
+import "time"
+
+// Config holds the settings of the http server and its handlers.
+type Config struct {
+	Addr                  string
+	ReadTimeout           time.Duration
+	WriteTimeout          time.Duration
+	IdleTimeout           time.Duration
+	ShutdownTimeout       time.Duration
+	DBTimeout             time.Duration
+	PresentationsPageSize int32
+	SectionsPageSize      int32
+	AuditPageSize         int32
+	VersionsPageSize      int32
+	// AuthEnabled requires every request but signup and login to be
+	// authenticated.
+	AuthEnabled bool
+	SessionTTL  time.Duration
+	// AllowSignup lets anyone create an account, the first account can always
+	// be created.
+	AllowSignup bool
+	// MetricsAddr is where the metrics are served, on a listener of their own
+	// so they are not public along with the api. They are not served when it
+	// is empty.
+	MetricsAddr string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Addr:                  ":8000",
+		ReadTimeout:           10 * time.Second,
+		WriteTimeout:          30 * time.Second,
+		IdleTimeout:           time.Minute,
+		ShutdownTimeout:       5 * time.Second,
+		DBTimeout:             5 * time.Second,
+		PresentationsPageSize: PresentationsPageSize,
+		SectionsPageSize:      SectionsPageSize,
+		AuditPageSize:         AuditPageSize,
+		VersionsPageSize:      VersionsPageSize,
+		AuthEnabled:           true,
+		SessionTTL:            7 * 24 * time.Hour,
+	}
+}
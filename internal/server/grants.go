@@ -0,0 +1,173 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+func ListGrantsHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type output struct {
+		Data []queries.GetGrantsRow `json:"data"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "presentation_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionManage); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		grants, err := queriesStore.GetGrants(ctx, presentationID)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{Data: grants}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func PutGrantHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		Role *string `json:"role"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		presentationID, v := helpers.ParseID(r, "presentation_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		if err := helpers.ReadJSON(r.Body, &input); err != nil {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v = validation.New()
+		ValidateRole(v, input.Role)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionManage); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		account, err := queriesStore.GetAccountByUsername(ctx, r.PathValue("username"))
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				v := validation.New()
+				v.AddErrors("username", "account does not exist")
+				helpers.UnprocessableContent(w, v.Errors())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		workspace, err := workspaceID(r)
+		if err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		// presentations can only be shared within their workspace
+		if _, err := queriesStore.GetWorkspaceMember(ctx, queries.GetWorkspaceMemberParams{
+			Workspace: workspace,
+			Account:   account.ID,
+		}); err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				v := validation.New()
+				v.AddErrors("username", "account is not a member of the workspace")
+				helpers.UnprocessableContent(w, v.Errors())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := queriesStore.UpsertGrant(ctx, queries.UpsertGrantParams{
+			Presentation: presentationID,
+			Account:      account.ID,
+			Role:         *input.Role,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func DeleteGrantHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "presentation_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionManage); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		account, err := queriesStore.GetAccountByUsername(ctx, r.PathValue("username"))
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		rows, err := queriesStore.DeleteGrant(ctx, queries.DeleteGrantParams{
+			Presentation: presentationID,
+			Account:      account.ID,
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+		if rows == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,12 @@
+package server
+
+import "github.com/PabloVarg/presentation-timer/internal/validation"
+
+func ValidateRole(v validation.Validator, role *string) {
+	v.Check(
+		"role",
+		role,
+		validation.CheckPointerNotNil("role must be given"),
+		validation.StringCheckIn(Roles, "role must be one of viewer, editor or operator"),
+	)
+}
@@ -2,39 +2,76 @@ package server
 
 import (
 	"context"
+	"errors"
 	"log/slog"
 	"net/http"
 	"sync"
 	"time"
 
-	"github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/metrics"
+	"github.com/PabloVarg/presentation-timer/internal/store"
 )
 
-func ListenAndServe(ctx context.Context, wg *sync.WaitGroup, addr string, logger *slog.Logger, queriesStore *queries.Queries) {
+func ListenAndServe(ctx context.Context, wg *sync.WaitGroup, conf Config, logger *slog.Logger, queriesStore store.Store) {
 	server := http.Server{
-		Addr:         addr,
-		Handler:      routes(logger, queriesStore),
-		ReadTimeout:  10 * time.Second,
-		WriteTimeout: 30 * time.Second,
-		IdleTimeout:  time.Minute,
+		Addr:         conf.Addr,
+		Handler:      routes(logger, queriesStore, conf),
+		ReadTimeout:  conf.ReadTimeout,
+		WriteTimeout: conf.WriteTimeout,
+		IdleTimeout:  conf.IdleTimeout,
 	}
 
 	wg.Add(1)
-	go closeServer(ctx, wg, logger, &server)
+	go closeServer(ctx, wg, logger, &server, conf.ShutdownTimeout)
 
-	logger.InfoContext(ctx, "server listening", "on", addr)
+	if conf.MetricsAddr != "" {
+		serveMetrics(ctx, wg, conf, logger)
+	}
+
+	logger.InfoContext(ctx, "server listening", "on", conf.Addr)
 	if err := server.ListenAndServe(); err != nil {
 		logger.Error("server exited unexpectedly", "err", err)
 		return
 	}
 }
 
-func closeServer(ctx context.Context, wg *sync.WaitGroup, logger *slog.Logger, server *http.Server) {
+// serveMetrics serves the metrics on their own address in the background, so
+// they are only reachable where that address is.
+func serveMetrics(ctx context.Context, wg *sync.WaitGroup, conf Config, logger *slog.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	server := http.Server{
+		Addr:         conf.MetricsAddr,
+		Handler:      mux,
+		ReadTimeout:  conf.ReadTimeout,
+		WriteTimeout: conf.WriteTimeout,
+		IdleTimeout:  conf.IdleTimeout,
+	}
+
+	wg.Add(1)
+	go closeServer(ctx, wg, logger, &server, conf.ShutdownTimeout)
+
+	go func() {
+		logger.InfoContext(ctx, "metrics listening", "on", conf.MetricsAddr)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("metrics server exited unexpectedly", "err", err)
+		}
+	}()
+}
+
+func closeServer(
+	ctx context.Context,
+	wg *sync.WaitGroup,
+	logger *slog.Logger,
+	server *http.Server,
+	timeout time.Duration,
+) {
 	defer wg.Done()
 
 	<-ctx.Done()
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
 	if err := server.Shutdown(shutdownCtx); err != nil {
@@ -0,0 +1,260 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/auth"
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+	"github.com/gorilla/websocket"
+)
+
+// PublicRoutes are the patterns that can be requested without credentials.
+var PublicRoutes = []string{
+	"POST /accounts",
+	"POST /sessions",
+	"GET /openapi.json",
+	"GET /asyncapi.json",
+	"GET /docs",
+	"GET /ui/",
+}
+
+// ShareLinkRoutes are the patterns that can be requested with a share link.
+var ShareLinkRoutes = []string{
+	"GET /presentations/{presentation_id}/sections",
+	"/run/{id}",
+}
+
+// WorkspaceHeader selects the workspace a request acts on, sessions default
+// to the first workspace their account is a member of and API keys can only
+// act on the workspace they were created in.
+const WorkspaceHeader = "X-Workspace"
+
+// Authenticate resolves the credentials of every request routed by mux and
+// attaches the account, or the share link, to its context along with the
+// workspace the request acts on. Requests to routes outside of PublicRoutes
+// are rejected when they have no valid credentials.
+func Authenticate(logger *slog.Logger, queriesStore store.Store, conf Config, mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested, v := requestWorkspace(r)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		if !conf.AuthEnabled {
+			workspace := auth.Workspace{ID: store.DefaultWorkspace}
+			if requested != nil {
+				ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+				defer cancel()
+
+				if _, err := queriesStore.GetWorkspace(ctx, *requested); err != nil {
+					switch {
+					case errors.Is(err, sql.ErrNoRows):
+						v.AddErrors("workspace", "workspace does not exist")
+						helpers.UnprocessableContent(w, v.Errors())
+					default:
+						helpers.InternalError(w, logger, err)
+					}
+					return
+				}
+				workspace.ID = *requested
+			}
+
+			mux.ServeHTTP(w, r.WithContext(auth.WithWorkspace(r.Context(), workspace)))
+			return
+		}
+
+		_, pattern := mux.Handler(r)
+		if slices.Contains(PublicRoutes, pattern) {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		token := requestToken(r)
+		if token == "" {
+			helpers.Unauthorized(w, "authentication required")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if auth.IsShareLink(token) {
+			if !slices.Contains(ShareLinkRoutes, pattern) {
+				helpers.Unauthorized(w, "share links only give access to runs and their sections")
+				return
+			}
+
+			link, err := queriesStore.GetValidShareLink(ctx, queries.GetValidShareLinkParams{
+				TokenHash: auth.HashToken(token),
+				Now:       time.Now(),
+			})
+			if err != nil {
+				switch {
+				case errors.Is(err, sql.ErrNoRows):
+					helpers.Unauthorized(w, "invalid or expired credentials")
+				default:
+					helpers.InternalError(w, logger, err)
+				}
+				return
+			}
+
+			mux.ServeHTTP(w, r.WithContext(auth.WithShareLink(r.Context(), auth.ShareLink{
+				ID:             link.ID,
+				PresentationID: link.Presentation,
+				ExpiresAt:      link.ExpiresAt,
+			})))
+			return
+		}
+
+		account, keyWorkspace, err := tokenAccount(ctx, queriesStore, token)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				helpers.Unauthorized(w, "invalid or expired credentials")
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if keyWorkspace != nil {
+			if requested != nil && *requested != *keyWorkspace {
+				helpers.Forbidden(w, "the API key belongs to another workspace")
+				return
+			}
+			requested = keyWorkspace
+		}
+
+		requestCtx := auth.WithAccount(r.Context(), auth.Account{
+			ID:       account.ID,
+			Username: account.Username,
+		})
+
+		workspace, err := accountWorkspace(ctx, queriesStore, account.ID, requested)
+		switch {
+		case err == nil:
+			requestCtx = auth.WithWorkspace(requestCtx, workspace)
+		case errors.Is(err, sql.ErrNoRows):
+			// accounts that aren't members of any workspace can still manage
+			// their account and create workspaces
+			if requested != nil {
+				helpers.Forbidden(w, "not a member of the workspace")
+				return
+			}
+		default:
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		mux.ServeHTTP(w, r.WithContext(requestCtx))
+	})
+}
+
+// requestWorkspace returns the workspace selected by WorkspaceHeader, nil when
+// it is not set. Websocket upgrades may send it in the workspace query
+// parameter instead, see requestToken.
+func requestWorkspace(r *http.Request) (*int64, validation.Validator) {
+	v := validation.New()
+
+	value := r.Header.Get(WorkspaceHeader)
+	if value == "" && websocket.IsWebSocketUpgrade(r) {
+		value = r.URL.Query().Get("workspace")
+	}
+	if value == "" {
+		return nil, v
+	}
+
+	ID, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		v.AddErrors("workspace", fmt.Sprintf("%s is not an integer", value))
+		return nil, v
+	}
+
+	v.Check("workspace", ID, validation.IntCheckPositive("workspace is not positive"))
+	if !v.Valid() {
+		return nil, v
+	}
+
+	return &ID, v
+}
+
+// accountWorkspace returns the requested workspace, or the default workspace
+// of the account when requested is nil, along with the role the account has
+// in it. It returns sql.ErrNoRows when the account is not a member.
+func accountWorkspace(
+	ctx context.Context,
+	queriesStore store.Store,
+	accountID int64,
+	requested *int64,
+) (auth.Workspace, error) {
+	if requested == nil {
+		workspace, err := queriesStore.GetDefaultWorkspace(ctx, accountID)
+		if err != nil {
+			return auth.Workspace{}, err
+		}
+
+		return auth.Workspace{ID: workspace.Workspace, Role: workspace.Role}, nil
+	}
+
+	role, err := queriesStore.GetWorkspaceMember(ctx, queries.GetWorkspaceMemberParams{
+		Workspace: *requested,
+		Account:   accountID,
+	})
+	if err != nil {
+		return auth.Workspace{}, err
+	}
+
+	return auth.Workspace{ID: *requested, Role: role}, nil
+}
+
+// requestToken returns the bearer token of r. Browsers can't set headers on
+// websocket upgrades so these may send it in the token query parameter, as
+// can share links, which are handed out as URLs.
+func requestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+
+	token := r.URL.Query().Get("token")
+	if websocket.IsWebSocketUpgrade(r) || auth.IsShareLink(token) {
+		return token
+	}
+
+	return ""
+}
+
+// tokenAccount returns the account token belongs to and, for API keys, the
+// workspace the key was created in.
+func tokenAccount(ctx context.Context, queriesStore store.Store, token string) (queries.Account, *int64, error) {
+	if auth.IsAPIKey(token) {
+		key, err := queriesStore.UseAPIKey(ctx, queries.UseAPIKeyParams{
+			Now:     time.Now(),
+			KeyHash: auth.HashToken(token),
+		})
+		if err != nil {
+			return queries.Account{}, nil, err
+		}
+
+		account, err := queriesStore.GetAccount(ctx, key.Account)
+		return account, &key.Workspace, err
+	}
+
+	account, err := queriesStore.GetSessionAccount(ctx, queries.GetSessionAccountParams{
+		TokenHash: auth.HashToken(token),
+		Now:       time.Now(),
+	})
+	return account, nil, err
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/PabloVarg/presentation-timer/api"
+)
+
+// registeredRoutes returns the patterns given to mux.Handle in routes.go.
+func registeredRoutes(t *testing.T) []string {
+	t.Helper()
+
+	file, err := parser.ParseFile(token.NewFileSet(), "routes.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var patterns []string
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		selector, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok || selector.Sel.Name != "Handle" {
+			return true
+		}
+
+		literal, ok := call.Args[0].(*ast.BasicLit)
+		if !ok || literal.Kind != token.STRING {
+			t.Errorf("route pattern %v is not a string literal", call.Args[0])
+			return true
+		}
+
+		pattern, err := strconv.Unquote(literal.Value)
+		if err != nil {
+			t.Fatal(err)
+		}
+		patterns = append(patterns, pattern)
+
+		return true
+	})
+
+	if len(patterns) == 0 {
+		t.Fatal("no routes found in routes.go")
+	}
+
+	return patterns
+}
+
+func TestOpenAPIDescribesEveryRoute(t *testing.T) {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(api.OpenAPI, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, pattern := range registeredRoutes(t) {
+		method, path, ok := strings.Cut(pattern, " ")
+		if !ok {
+			// patterns without a method are websocket upgrades, which are GET
+			method, path = "GET", pattern
+		}
+
+		if _, ok := spec.Paths[path][strings.ToLower(method)]; !ok {
+			t.Errorf("%s %s is missing from the OpenAPI document", method, path)
+		}
+	}
+}
+
+func TestAPIDocumentReferencesExist(t *testing.T) {
+	for name, document := range map[string][]byte{
+		"openapi.json":  api.OpenAPI,
+		"asyncapi.json": api.AsyncAPI,
+	} {
+		t.Run(name, func(t *testing.T) {
+			checkReferences(t, document)
+		})
+	}
+}
+
+// checkReferences fails t for every local reference of document that points
+// to nothing.
+func checkReferences(t *testing.T, document []byte) {
+	var spec map[string]any
+	if err := json.Unmarshal(document, &spec); err != nil {
+		t.Fatal(err)
+	}
+
+	var walk func(value any)
+	walk = func(value any) {
+		switch value := value.(type) {
+		case map[string]any:
+			for key, child := range value {
+				if ref, ok := child.(string); ok && key == "$ref" {
+					if !resolves(spec, ref) {
+						t.Errorf("%s does not exist", ref)
+					}
+					continue
+				}
+
+				walk(child)
+			}
+		case []any:
+			for _, child := range value {
+				walk(child)
+			}
+		}
+	}
+	walk(spec)
+}
+
+// resolves reports whether the local reference ref points to a value of spec.
+func resolves(spec map[string]any, ref string) bool {
+	pointer, ok := strings.CutPrefix(ref, "#/")
+	if !ok {
+		return false
+	}
+
+	var current any = spec
+	for _, key := range strings.Split(pointer, "/") {
+		object, ok := current.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		if current, ok = object[key]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,299 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/filters"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+)
+
+// listPage is a page of a list endpoint, with the names of its rows.
+type listPage struct {
+	Data []struct {
+		Name string `json:"name"`
+	} `json:"data"`
+	PageInfo filters.PageInfo `json:"page_info"`
+}
+
+func (p listPage) names() []string {
+	names := make([]string, 0, len(p.Data))
+	for _, row := range p.Data {
+		names = append(names, row.Name)
+	}
+
+	return names
+}
+
+// newTestServer serves the routes with authentication disabled from a Memory
+// store.
+func newTestServer(t *testing.T) (http.Handler, *store.Memory) {
+	t.Helper()
+
+	conf := DefaultConfig()
+	conf.AuthEnabled = false
+	queriesStore := store.NewMemory()
+
+	return routes(slog.New(slog.NewTextHandler(io.Discard, nil)), queriesStore, conf), queriesStore
+}
+
+// seedPresentations creates a presentation for each name, with a section of
+// the given duration so they can be sorted by it.
+func seedPresentations(t *testing.T, queriesStore *store.Memory, durations map[string]time.Duration) {
+	t.Helper()
+
+	ctx := context.Background()
+	for name, duration := range durations {
+		presentation, err := queriesStore.CreatePresentation(ctx, queries.CreatePresentationParams{
+			Workspace: store.DefaultWorkspace,
+			Name:      name,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := queriesStore.CreateSection(ctx, queries.CreateSectionParams{
+			Presentation: presentation.ID,
+			Name:         "Section of " + name,
+			Duration:     duration,
+			Position:     1,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// getPage requests path with query, failing t unless it answers with status.
+func getPage(t *testing.T, handler http.Handler, path string, query url.Values, status int) listPage {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?"+query.Encode(), nil))
+	if w.Code != status {
+		t.Fatalf("GET %s?%s answered %d, want %d: %s", path, query.Encode(), w.Code, status, w.Body)
+	}
+
+	var page listPage
+	if status == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return page
+}
+
+func withCursor(query url.Values, cursor *string) url.Values {
+	query = maps.Clone(query)
+	query.Set("cursor", *cursor)
+
+	return query
+}
+
+func TestPresentationsCursorPagination(t *testing.T) {
+	durations := map[string]time.Duration{
+		"Alpha talk":   5 * time.Minute,
+		"Bravo talk":   3 * time.Minute,
+		"Charlie talk": 5 * time.Minute,
+		"Delta talk":   1 * time.Minute,
+		"Echo talk":    3 * time.Minute,
+	}
+
+	tests := []struct {
+		name   string
+		sortBy string
+		size   string
+		pages  [][]string
+	}{
+		{
+			name:   "by name",
+			sortBy: "name",
+			size:   "2",
+			pages:  [][]string{{"Alpha talk", "Bravo talk"}, {"Charlie talk", "Delta talk"}, {"Echo talk"}},
+		},
+		{
+			name:   "by name descending",
+			sortBy: "-name",
+			size:   "2",
+			pages:  [][]string{{"Echo talk", "Delta talk"}, {"Charlie talk", "Bravo talk"}, {"Alpha talk"}},
+		},
+		{
+			name:   "by duration with ties",
+			sortBy: "-duration,name",
+			size:   "2",
+			pages:  [][]string{{"Alpha talk", "Charlie talk"}, {"Bravo talk", "Echo talk"}, {"Delta talk"}},
+		},
+		{
+			name:   "pages filled exactly",
+			sortBy: "duration,-name",
+			size:   "1",
+			pages: [][]string{
+				{"Delta talk"}, {"Echo talk"}, {"Bravo talk"}, {"Charlie talk"}, {"Alpha talk"},
+			},
+		},
+		{
+			name:   "single short page",
+			sortBy: "name",
+			size:   "10",
+			pages:  [][]string{{"Alpha talk", "Bravo talk", "Charlie talk", "Delta talk", "Echo talk"}},
+		},
+	}
+
+	handler, queriesStore := newTestServer(t)
+	seedPresentations(t, queriesStore, durations)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{"sort_by": {tt.sortBy}, "page_size": {tt.size}}
+
+			var forward []listPage
+			page := getPage(t, handler, "/presentations", query, http.StatusOK)
+			for {
+				forward = append(forward, page)
+				if page.PageInfo.NextCursor == nil {
+					break
+				}
+				if len(forward) > len(tt.pages) {
+					t.Fatalf("more than %d pages", len(tt.pages))
+				}
+
+				page = getPage(t, handler, "/presentations", withCursor(query, page.PageInfo.NextCursor), http.StatusOK)
+			}
+
+			if len(forward) != len(tt.pages) {
+				t.Fatalf("got %d pages forward, want %d", len(forward), len(tt.pages))
+			}
+			for i, page := range forward {
+				if !slices.Equal(page.names(), tt.pages[i]) {
+					t.Errorf("page %d forward is %q, want %q", i, page.names(), tt.pages[i])
+				}
+				if hasPrev := page.PageInfo.PrevCursor != nil; hasPrev != (i > 0) {
+					t.Errorf("page %d forward has prev cursor %v, want %v", i, hasPrev, i > 0)
+				}
+			}
+
+			// walking back from the last page goes through the same pages
+			for i := len(forward) - 2; i >= 0; i-- {
+				page = getPage(t, handler, "/presentations", withCursor(query, page.PageInfo.PrevCursor), http.StatusOK)
+
+				if !slices.Equal(page.names(), tt.pages[i]) {
+					t.Errorf("page %d backward is %q, want %q", i, page.names(), tt.pages[i])
+				}
+				if page.PageInfo.NextCursor == nil {
+					t.Errorf("page %d backward has no next cursor", i)
+				}
+				if hasPrev := page.PageInfo.PrevCursor != nil; hasPrev != (i > 0) {
+					t.Errorf("page %d backward has prev cursor %v, want %v", i, hasPrev, i > 0)
+				}
+			}
+		})
+	}
+}
+
+func TestPresentationsCursorPaginationEmpty(t *testing.T) {
+	handler, _ := newTestServer(t)
+
+	page := getPage(t, handler, "/presentations", url.Values{"sort_by": {"name"}}, http.StatusOK)
+	if len(page.Data) != 0 {
+		t.Errorf("got %q, want no presentations", page.names())
+	}
+	if page.PageInfo.NextCursor != nil || page.PageInfo.PrevCursor != nil {
+		t.Errorf("empty page has cursors %+v", page.PageInfo)
+	}
+
+	// a cursor past the last row gives an empty page
+	cursor := filters.Cursor{CursorValues: filters.CursorValues{ID: 1, Name: "Zulu"}, SortBy: "name"}.Encode()
+	page = getPage(t, handler, "/presentations", url.Values{"sort_by": {"name"}, "cursor": {cursor}}, http.StatusOK)
+	if len(page.Data) != 0 || page.PageInfo.NextCursor != nil || page.PageInfo.PrevCursor != nil {
+		t.Errorf("page after the last row is %q with %+v, want it empty", page.names(), page.PageInfo)
+	}
+}
+
+func TestPresentationsCursorValidation(t *testing.T) {
+	handler, queriesStore := newTestServer(t)
+	seedPresentations(t, queriesStore, map[string]time.Duration{
+		"Alpha talk": time.Minute,
+		"Bravo talk": time.Minute,
+	})
+
+	first := getPage(t, handler, "/presentations", url.Values{"sort_by": {"name"}, "page_size": {"1"}}, http.StatusOK)
+	if first.PageInfo.NextCursor == nil {
+		t.Fatal("first page has no next cursor")
+	}
+	cursor := *first.PageInfo.NextCursor
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"different sort_by", url.Values{"sort_by": {"-name"}, "cursor": {cursor}}},
+		{"missing sort_by", url.Values{"cursor": {cursor}}},
+		{"with page", url.Values{"sort_by": {"name"}, "cursor": {cursor}, "page": {"2"}}},
+		{"not base64", url.Values{"sort_by": {"name"}, "cursor": {"%%%"}}},
+		{"not json", url.Values{"sort_by": {"name"}, "cursor": {"bm90IGpzb24"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getPage(t, handler, "/presentations", tt.query, http.StatusUnprocessableEntity)
+		})
+	}
+}
+
+func TestSectionsCursorPagination(t *testing.T) {
+	handler, queriesStore := newTestServer(t)
+
+	ctx := context.Background()
+	presentation, err := queriesStore.CreatePresentation(ctx, queries.CreatePresentationParams{
+		Workspace: store.DefaultWorkspace,
+		Name:      "Sectioned talk",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, name := range []string{"Opening", "Agenda", "Demo time", "Questions"} {
+		if _, err := queriesStore.CreateSection(ctx, queries.CreateSectionParams{
+			Presentation: presentation.ID,
+			Name:         name,
+			Duration:     time.Minute,
+			Position:     int16(i + 1),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := "/presentations/" + strconv.FormatInt(presentation.ID, 10) + "/sections"
+	query := url.Values{"sort_by": {"position"}, "page_size": {"3"}}
+
+	first := getPage(t, handler, path, query, http.StatusOK)
+	if want := []string{"Opening", "Agenda", "Demo time"}; !slices.Equal(first.names(), want) {
+		t.Errorf("first page is %q, want %q", first.names(), want)
+	}
+	if first.PageInfo.NextCursor == nil || first.PageInfo.PrevCursor != nil {
+		t.Fatalf("first page has cursors %+v, want only next", first.PageInfo)
+	}
+
+	last := getPage(t, handler, path, withCursor(query, first.PageInfo.NextCursor), http.StatusOK)
+	if want := []string{"Questions"}; !slices.Equal(last.names(), want) {
+		t.Errorf("last page is %q, want %q", last.names(), want)
+	}
+	if last.PageInfo.NextCursor != nil || last.PageInfo.PrevCursor == nil {
+		t.Fatalf("last page has cursors %+v, want only prev", last.PageInfo)
+	}
+
+	back := getPage(t, handler, path, withCursor(query, last.PageInfo.PrevCursor), http.StatusOK)
+	if !slices.Equal(back.names(), first.names()) {
+		t.Errorf("page before the last is %q, want %q", back.names(), first.names())
+	}
+}
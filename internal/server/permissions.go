@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/PabloVarg/presentation-timer/internal/auth"
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+)
+
+// Roles that can be granted on a presentation to accounts other than its
+// owner.
+const (
+	RoleViewer   = "viewer"
+	RoleEditor   = "editor"
+	RoleOperator = "operator"
+)
+
+var Roles = []string{RoleViewer, RoleEditor, RoleOperator}
+
+// Roles an account can have in a workspace. Admins have every permission on
+// the presentations of the workspace and manage its members, members only
+// see the presentations they own or that are shared with them.
+const (
+	WorkspaceRoleAdmin  = "admin"
+	WorkspaceRoleMember = "member"
+)
+
+var WorkspaceRoles = []string{WorkspaceRoleAdmin, WorkspaceRoleMember}
+
+// Permission is something an account can do with a presentation.
+type Permission int
+
+const (
+	// PermissionView allows reading the presentation, its sections and
+	// following its runs.
+	PermissionView Permission = iota
+	// PermissionEdit allows changing the presentation and its sections.
+	PermissionEdit
+	// PermissionOperate allows controlling the runs of the presentation.
+	PermissionOperate
+	// PermissionManage allows deleting the presentation and sharing it, only
+	// the owner and the workspace admins have it.
+	PermissionManage
+)
+
+var rolePermissions = map[string][]Permission{
+	RoleViewer:   {PermissionView},
+	RoleEditor:   {PermissionView, PermissionEdit},
+	RoleOperator: {PermissionView, PermissionOperate},
+}
+
+var (
+	errForbidden   = errors.New("not allowed to do this with the presentation")
+	errNoWorkspace = errors.New("not a member of any workspace")
+)
+
+// authorize checks that the account of r has permission on the presentation.
+// It returns sql.ErrNoRows when the presentation does not exist, is in the
+// trash, belongs to another workspace or is not shared with the account, so
+// its existence isn't leaked, and errForbidden when the account's role doesn't
+// include permission. Share links can only view their presentation. Requests
+// without an account or share link are only routed when authentication is
+// disabled and are allowed within their workspace.
+func authorize(
+	ctx context.Context,
+	queriesStore store.Store,
+	r *http.Request,
+	presentationID int64,
+	permission Permission,
+) error {
+	return authorizePresentation(ctx, queriesStore, r, presentationID, permission, false)
+}
+
+// authorizeDeleted is authorize for presentations in the trash, it returns
+// sql.ErrNoRows for presentations that are not deleted.
+func authorizeDeleted(
+	ctx context.Context,
+	queriesStore store.Store,
+	r *http.Request,
+	presentationID int64,
+	permission Permission,
+) error {
+	return authorizePresentation(ctx, queriesStore, r, presentationID, permission, true)
+}
+
+func authorizePresentation(
+	ctx context.Context,
+	queriesStore store.Store,
+	r *http.Request,
+	presentationID int64,
+	permission Permission,
+	deleted bool,
+) error {
+	if link, ok := auth.ShareLinkFromContext(r.Context()); ok {
+		if link.PresentationID != presentationID {
+			return sql.ErrNoRows
+		}
+		if permission != PermissionView {
+			return errForbidden
+		}
+
+		return nil
+	}
+
+	workspace, ok := auth.WorkspaceFromContext(r.Context())
+	if !ok {
+		return errNoWorkspace
+	}
+
+	account, _ := auth.AccountFromContext(r.Context())
+	access, err := queriesStore.GetPresentationAccess(ctx, queries.GetPresentationAccessParams{
+		Account:        account.ID,
+		PresentationID: presentationID,
+		Workspace:      workspace.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if (access.DeletedAt != nil) != deleted {
+		return sql.ErrNoRows
+	}
+
+	if account.ID == 0 || workspace.Role == WorkspaceRoleAdmin {
+		return nil
+	}
+	if access.Owner != nil && *access.Owner == account.ID {
+		return nil
+	}
+	if access.Role == nil {
+		return sql.ErrNoRows
+	}
+	if !slices.Contains(rolePermissions[*access.Role], permission) {
+		return errForbidden
+	}
+
+	return nil
+}
+
+// authorizeSection checks permission on the presentation of a section, see
+// authorize.
+func authorizeSection(
+	ctx context.Context,
+	queriesStore store.Store,
+	r *http.Request,
+	sectionID int64,
+	permission Permission,
+) error {
+	section, err := queriesStore.GetSection(ctx, sectionID)
+	if err != nil {
+		return err
+	}
+
+	return authorize(ctx, queriesStore, r, section.Presentation, permission)
+}
+
+// accountID returns the ID of the account of r, nil when authentication is
+// disabled.
+func accountID(r *http.Request) *int64 {
+	account, ok := auth.AccountFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+
+	return &account.ID
+}
+
+// accountUsername returns the username of the account of r, nil when
+// authentication is disabled.
+func accountUsername(r *http.Request) *string {
+	account, ok := auth.AccountFromContext(r.Context())
+	if !ok {
+		return nil
+	}
+
+	return &account.Username
+}
+
+// workspaceID returns the ID of the workspace r acts on, errNoWorkspace when
+// its account is not a member of any workspace.
+func workspaceID(r *http.Request) (int64, error) {
+	workspace, ok := auth.WorkspaceFromContext(r.Context())
+	if !ok {
+		return 0, errNoWorkspace
+	}
+
+	return workspace.ID, nil
+}
+
+// listAccount returns the account presentation lists are filtered by, nil when
+// authentication is disabled or the account administers the workspace and can
+// see all of its presentations.
+func listAccount(r *http.Request, sharedWithMe bool) *int64 {
+	workspace, _ := auth.WorkspaceFromContext(r.Context())
+	if workspace.Role == WorkspaceRoleAdmin && !sharedWithMe {
+		return nil
+	}
+
+	return accountID(r)
+}
+
+func writeAuthorizeError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		http.NotFound(w, r)
+	case errors.Is(err, errForbidden), errors.Is(err, errNoWorkspace), errors.Is(err, errNotWorkspaceAdmin):
+		helpers.Forbidden(w, err.Error())
+	default:
+		helpers.InternalError(w, logger, err)
+	}
+}
@@ -11,57 +11,141 @@ import (
 	"github.com/PabloVarg/presentation-timer/internal/filters"
 	"github.com/PabloVarg/presentation-timer/internal/helpers"
 	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
 	"github.com/PabloVarg/presentation-timer/internal/validation"
 )
 
 const PresentationsPageSize = 20
 
-var PresentationsSortFields = []string{"name"}
+var PresentationsSortFields = []string{"name", "duration", "created_at"}
 
-func ListPresentationsHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+var errPreconditionFailed = errors.New("the resource has changed since it was read")
+
+func ListPresentationsHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	type output struct {
-		Data     []queries.GetPresentationsRow `json:"data"`
-		PageInfo filters.PageInfo              `json:"page_info"`
+		Data     any              `json:"data"`
+		PageInfo filters.PageInfo `json:"page_info"`
 	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		f, v := filters.FromRequest(r, PresentationsPageSize, PresentationsSortFields...)
+		f, v := filters.FromRequestWithCursor(r, conf.PresentationsPageSize, PresentationsSortFields...)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		fields, v := filters.FieldFiltersFromRequest(r)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		fieldset, v := filters.FieldsetFromRequest(r, PresentationFields, PresentationIncludes)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		sharedWithMe, v := helpers.QueryBool(r, "shared_with_me", false)
 		if !v.Valid() {
 			helpers.UnprocessableContent(w, v.Errors())
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		workspace, err := workspaceID(r)
+		if err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+		account := listAccount(r, sharedWithMe)
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
+		cursorID, cursor := f.QueryCursor()
 		presentations, err := queriesStore.GetPresentations(ctx, queries.GetPresentationsParams{
-			Direction:   f.QuerySortDirection(),
-			SortBy:      f.QuerySortBy(),
-			QueryOffset: f.QueryOffset(),
-			QueryLimit:  f.QueryLimit(),
+			Workspace:       workspace,
+			Account:         account,
+			SharedWithMe:    sharedWithMe,
+			NameContains:    fields.NameContains,
+			CreatedAfter:    fields.CreatedAfter,
+			Search:          fields.Search,
+			MinDuration:     fields.MinDuration,
+			MaxDuration:     fields.MaxDuration,
+			Direction1:      f.QuerySortDirection(0),
+			SortBy1:         f.QuerySortBy(0),
+			Direction2:      f.QuerySortDirection(1),
+			SortBy2:         f.QuerySortBy(1),
+			Direction3:      f.QuerySortDirection(2),
+			SortBy3:         f.QuerySortBy(2),
+			IDDirection:     f.QueryIDDirection(),
+			CursorID:        cursorID,
+			CursorName:      cursor.Name,
+			CursorDuration:  cursor.Duration,
+			CursorCreatedAt: cursor.CreatedAt,
+			QueryOffset:     f.QueryOffset(),
+			QueryLimit:      f.QueryCursorLimit(),
 		})
 		if err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 
-		totalRows, err := queriesStore.GetPresentationsMetadata(ctx)
+		totalRows, err := queriesStore.GetPresentationsMetadata(ctx, queries.GetPresentationsMetadataParams{
+			Workspace:    workspace,
+			Account:      account,
+			SharedWithMe: sharedWithMe,
+			NameContains: fields.NameContains,
+			CreatedAfter: fields.CreatedAfter,
+			Search:       fields.Search,
+			MinDuration:  fields.MinDuration,
+			MaxDuration:  fields.MaxDuration,
+		})
 		if err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 
-		if err := helpers.WriteJSON(w, http.StatusOK, output{
-			Data:     presentations,
-			PageInfo: f.PageInfo(totalRows),
-		}); err != nil {
+		presentations, pageInfo := filters.CursorPage(
+			f,
+			presentations,
+			f.PageInfo(totalRows),
+			func(presentation queries.GetPresentationsRow) filters.CursorValues {
+				return filters.CursorValues{
+					ID:        presentation.ID,
+					Name:      presentation.Name,
+					Duration:  presentation.Duration,
+					CreatedAt: presentation.CreatedAt,
+				}
+			},
+		)
+
+		var data any = presentations
+		if !fieldset.Default() {
+			data, err = expandPresentations(
+				ctx,
+				queriesStore,
+				fieldset,
+				presentations,
+				func(presentation queries.GetPresentationsRow) int64 { return presentation.ID },
+			)
+			if err != nil {
+				helpers.InternalError(w, logger, err)
+				return
+			}
+		}
+
+		if err := helpers.WriteJSONETag(w, r, http.StatusOK, output{
+			Data:     data,
+			PageInfo: pageInfo,
+		}, ""); err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 	})
 }
 
-func GetPresentationHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func GetPresentationHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ID, v := helpers.ParseID(r, "id")
 		if !v.Valid() {
@@ -69,9 +153,20 @@ func GetPresentationHandler(logger *slog.Logger, queriesStore *queries.Queries)
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		fieldset, v := filters.FieldsetFromRequest(r, PresentationFields, PresentationIncludes)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
+		if err := authorize(ctx, queriesStore, r, ID, PermissionView); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
 		presentation, err := queriesStore.GetPresentation(ctx, ID)
 		if err != nil {
 			switch {
@@ -83,14 +178,36 @@ func GetPresentationHandler(logger *slog.Logger, queriesStore *queries.Queries)
 			return
 		}
 
-		if err := helpers.WriteJSON(w, http.StatusOK, presentation); err != nil {
+		if !fieldset.Default() {
+			// the version of the presentation does not cover its sections, the
+			// ETag is derived from the body instead
+			expanded, err := expandPresentations(
+				ctx,
+				queriesStore,
+				fieldset,
+				[]queries.Presentation{presentation},
+				func(presentation queries.Presentation) int64 { return presentation.ID },
+			)
+			if err != nil {
+				helpers.InternalError(w, logger, err)
+				return
+			}
+
+			if err := helpers.WriteJSONETag(w, r, http.StatusOK, expanded[0], ""); err != nil {
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		etag := helpers.ETag(presentation.Version)
+		if err := helpers.WriteJSONETag(w, r, http.StatusOK, presentation, etag); err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 	})
 }
 
-func CreatePresentationHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func CreatePresentationHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	type Input struct {
 		Name *string `json:"name"`
 	}
@@ -110,15 +227,45 @@ func CreatePresentationHandler(logger *slog.Logger, queriesStore *queries.Querie
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		workspace, err := workspaceID(r)
+		if err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		presentation, err := queriesStore.CreatePresentation(ctx, *input.Name)
+		var presentation queries.Presentation
+		err = queriesStore.InTx(ctx, func(tx store.Store) error {
+			var err error
+
+			presentation, err = tx.CreatePresentation(ctx, queries.CreatePresentationParams{
+				Workspace: workspace,
+				Name:      *input.Name,
+				Owner:     accountID(r),
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := recordAudit(ctx, tx, r, auditChange{
+				Presentation: presentation.ID,
+				Action:       AuditActionCreate,
+				After:        presentation,
+			}); err != nil {
+				return err
+			}
+
+			_, err = recordVersion(ctx, tx, r, presentation.ID)
+			return err
+		})
 		if err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 
+		w.Header().Set("ETag", helpers.ETag(presentation.Version))
 		if err := helpers.WriteJSON(w, http.StatusCreated, presentation); err != nil {
 			helpers.InternalError(w, logger, err)
 			return
@@ -126,7 +273,7 @@ func CreatePresentationHandler(logger *slog.Logger, queriesStore *queries.Querie
 	})
 }
 
-func PutPresentationHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func PutPresentationHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	type Input struct {
 		Name *string `json:"name"`
 	}
@@ -152,27 +299,38 @@ func PutPresentationHandler(logger *slog.Logger, queriesStore *queries.Queries)
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		rows, err := queriesStore.UpdatePresentation(ctx, queries.UpdatePresentationParams{
-			ID:   ID,
-			Name: *input.Name,
-		})
-		if err != nil {
-			helpers.InternalError(w, logger, err)
+		if err := authorize(ctx, queriesStore, r, ID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
 			return
 		}
-		if rows == 0 {
-			http.NotFound(w, r)
+
+		presentation, err := changePresentation(ctx, queriesStore, r, ID, AuditActionUpdate, func(tx store.Store) (int64, error) {
+			return tx.UpdatePresentation(ctx, queries.UpdatePresentationParams{
+				ID:   ID,
+				Name: *input.Name,
+			})
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			case errors.Is(err, errPreconditionFailed):
+				helpers.PreconditionFailed(w, err.Error())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
 			return
 		}
 
+		w.Header().Set("ETag", helpers.ETag(presentation.Version))
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
 
-func PatchPresentationHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func PatchPresentationHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	type input struct {
 		Name *string `json:"name"`
 	}
@@ -196,27 +354,38 @@ func PatchPresentationHandler(logger *slog.Logger, queriesStore *queries.Queries
 			ValidatePresentationName(v, input.Name)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		rows, err := queriesStore.PatchPresentation(ctx, queries.PatchPresentationParams{
-			ID:   ID,
-			Name: input.Name,
-		})
-		if err != nil {
-			helpers.InternalError(w, logger, err)
+		if err := authorize(ctx, queriesStore, r, ID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
 			return
 		}
-		if rows == 0 {
-			http.NotFound(w, r)
+
+		presentation, err := changePresentation(ctx, queriesStore, r, ID, AuditActionPatch, func(tx store.Store) (int64, error) {
+			return tx.PatchPresentation(ctx, queries.PatchPresentationParams{
+				ID:   ID,
+				Name: input.Name,
+			})
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			case errors.Is(err, errPreconditionFailed):
+				helpers.PreconditionFailed(w, err.Error())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
 			return
 		}
 
+		w.Header().Set("ETag", helpers.ETag(presentation.Version))
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
 
-func DeletePresentationHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func DeletePresentationHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ID, v := helpers.ParseID(r, "id")
 		if !v.Valid() {
@@ -224,19 +393,96 @@ func DeletePresentationHandler(logger *slog.Logger, queriesStore *queries.Querie
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		rows, err := queriesStore.DeletePresentation(ctx, ID)
-		if err != nil {
-			helpers.InternalError(w, logger, err)
+		if err := authorize(ctx, queriesStore, r, ID, PermissionManage); err != nil {
+			writeAuthorizeError(w, r, logger, err)
 			return
 		}
-		if rows == 0 {
-			http.NotFound(w, r)
+
+		_, err := changePresentation(ctx, queriesStore, r, ID, AuditActionDelete, func(tx store.Store) (int64, error) {
+			return tx.DeletePresentation(ctx, queries.DeletePresentationParams{ID: ID, Now: time.Now()})
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			case errors.Is(err, errPreconditionFailed):
+				helpers.PreconditionFailed(w, err.Error())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 	})
 }
+
+// changePresentation applies change to the presentation in a transaction,
+// recording it in the audit log and, unless it was deleted, as a new version.
+// change returns the affected rows, sql.ErrNoRows is returned when there were
+// none and errPreconditionFailed when r has an If-Match header that doesn't
+// match the presentation. The presentation is returned as it is after the
+// change.
+func changePresentation(
+	ctx context.Context,
+	queriesStore store.Store,
+	r *http.Request,
+	ID int64,
+	action string,
+	change func(tx store.Store) (int64, error),
+) (queries.Presentation, error) {
+	var after queries.Presentation
+	err := queriesStore.InTx(ctx, func(tx store.Store) error {
+		before, err := tx.LockPresentation(ctx, ID)
+		if err != nil {
+			return err
+		}
+		if err := checkIfMatch(r, before.Version); err != nil {
+			return err
+		}
+
+		rows, err := change(tx)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+
+		entry := auditChange{
+			Presentation: ID,
+			Action:       action,
+			Before:       before,
+		}
+		if action == AuditActionDelete {
+			return recordAudit(ctx, tx, r, entry)
+		}
+
+		if after, err = tx.GetPresentation(ctx, ID); err != nil {
+			return err
+		}
+		entry.After = after
+		if err := recordAudit(ctx, tx, r, entry); err != nil {
+			return err
+		}
+
+		_, err = recordVersion(ctx, tx, r, ID)
+		return err
+	})
+
+	return after, err
+}
+
+// checkIfMatch returns errPreconditionFailed when r has an If-Match header
+// that doesn't match the ETag of a resource at version.
+func checkIfMatch(r *http.Request, version int32) error {
+	header := r.Header.Get("If-Match")
+	if header == "" || helpers.ETagMatch(header, helpers.ETag(version), false) {
+		return nil
+	}
+
+	return errPreconditionFailed
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/filters"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+)
+
+const IncludeSections = "sections"
+
+var PresentationFields = []string{
+	"id",
+	"name",
+	"owner",
+	"workspace",
+	"deleted_at",
+	"version",
+	"created_at",
+	"duration",
+}
+
+// PresentationIncludes are the resources that can be embedded in presentations,
+// sections is the only one.
+var PresentationIncludes = []string{IncludeSections}
+
+// PresentationTotals summarizes the sections embedded in a presentation.
+type PresentationTotals struct {
+	Sections int           `json:"sections"`
+	Duration time.Duration `json:"duration"`
+}
+
+// expandPresentations shapes presentations as asked by fieldset, embedding
+// their sections in order along with their totals when they are included. The
+// sections of every presentation are queried at once. ID returns the ID of a
+// presentation.
+func expandPresentations[T any](
+	ctx context.Context,
+	queriesStore store.Store,
+	fieldset filters.Fieldset,
+	presentations []T,
+	ID func(T) int64,
+) ([]map[string]any, error) {
+	var sections map[int64][]queries.Section
+	if fieldset.Includes(IncludeSections) {
+		IDs := make([]int64, 0, len(presentations))
+		for _, presentation := range presentations {
+			IDs = append(IDs, ID(presentation))
+		}
+
+		all, err := queriesStore.GetSectionsOfPresentations(ctx, IDs)
+		if err != nil {
+			return nil, err
+		}
+
+		sections = make(map[int64][]queries.Section, len(presentations))
+		for _, section := range all {
+			sections[section.Presentation] = append(sections[section.Presentation], section)
+		}
+	}
+
+	expanded := make([]map[string]any, 0, len(presentations))
+	for _, presentation := range presentations {
+		embedded := make(map[string]any)
+
+		if sections != nil {
+			of := sections[ID(presentation)]
+			if of == nil {
+				of = []queries.Section{}
+			}
+
+			totals := PresentationTotals{Sections: len(of)}
+			for _, section := range of {
+				totals.Duration += section.Duration
+			}
+
+			embedded["sections"] = of
+			embedded["totals"] = totals
+		}
+
+		item, err := fieldset.Select(presentation, embedded)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, item)
+	}
+
+	return expanded, nil
+}
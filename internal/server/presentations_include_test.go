@@ -0,0 +1,47 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPresentationsIncludeSections(t *testing.T) {
+	handler, queriesStore := newTestServer(t)
+	seedPresentations(t, queriesStore, map[string]time.Duration{
+		"Alpha talk": 2 * time.Minute,
+		"Bravo talk": 3 * time.Minute,
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/presentations?include=sections&fields=name", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("answered %d: %s", w.Code, w.Body)
+	}
+
+	var page struct {
+		Data []struct {
+			Name     string                  `json:"name"`
+			Sections []struct{ Name string } `json:"sections"`
+			Totals   PresentationTotals      `json:"totals"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]time.Duration{"Alpha talk": 2 * time.Minute, "Bravo talk": 3 * time.Minute}
+	if len(page.Data) != len(want) {
+		t.Fatalf("got %d presentations, want %d", len(page.Data), len(want))
+	}
+	for _, presentation := range page.Data {
+		if len(presentation.Sections) != 1 || presentation.Sections[0].Name != "Section of "+presentation.Name {
+			t.Errorf("%s has sections %+v, want only its own", presentation.Name, presentation.Sections)
+		}
+		if presentation.Totals != (PresentationTotals{Sections: 1, Duration: want[presentation.Name]}) {
+			t.Errorf("%s has totals %+v", presentation.Name, presentation.Totals)
+		}
+	}
+}
@@ -4,36 +4,116 @@ import (
 	"log/slog"
 	"net/http"
 
-	"github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
 )
 
-func routes(logger *slog.Logger, queries *queries.Queries) *http.ServeMux {
+func routes(logger *slog.Logger, queries store.Store, conf Config) http.Handler {
 	mux := http.NewServeMux()
+	shareLinkConns := NewShareLinkConns()
 
-	mux.Handle("GET /presentations", ListPresentationsHandler(logger, queries))
-	mux.Handle("GET /presentations/{id}", GetPresentationHandler(logger, queries))
-	mux.Handle("POST /presentations", CreatePresentationHandler(logger, queries))
-	mux.Handle("PUT /presentations/{id}", PutPresentationHandler(logger, queries))
-	mux.Handle("PATCH /presentations/{id}", PatchPresentationHandler(logger, queries))
-	mux.Handle("DELETE /presentations/{id}", DeletePresentationHandler(logger, queries))
+	mux.Handle("GET /presentations", ListPresentationsHandler(logger, queries, conf))
+	mux.Handle("GET /presentations/{id}", GetPresentationHandler(logger, queries, conf))
+	mux.Handle("POST /presentations", CreatePresentationHandler(logger, queries, conf))
+	mux.Handle("PUT /presentations/{id}", PutPresentationHandler(logger, queries, conf))
+	mux.Handle("PATCH /presentations/{id}", PatchPresentationHandler(logger, queries, conf))
+	mux.Handle("DELETE /presentations/{id}", DeletePresentationHandler(logger, queries, conf))
+	mux.Handle("GET /presentations/{id}/audit", ListAuditEntriesHandler(logger, queries, conf))
+	mux.Handle("POST /presentations/{id}/restore", RestorePresentationHandler(logger, queries, conf))
+
+	mux.Handle("GET /presentations/{id}/versions", ListVersionsHandler(logger, queries, conf))
+	mux.Handle("GET /presentations/{id}/versions/diff", DiffVersionsHandler(logger, queries, conf))
+	mux.Handle("GET /presentations/{id}/versions/{version}", GetVersionHandler(logger, queries, conf))
+	mux.Handle(
+		"POST /presentations/{id}/versions/{version}/restore",
+		RestoreVersionHandler(logger, queries, conf),
+	)
 
 	mux.Handle(
 		"GET /presentations/{presentation_id}/sections",
-		ListSectionsHandler(logger, queries),
+		ListSectionsHandler(logger, queries, conf),
 	)
 	mux.Handle(
 		"POST /presentations/{presentation_id}/sections",
-		CreateSectionHandler(logger, queries),
+		CreateSectionHandler(logger, queries, conf),
+	)
+	mux.Handle(
+		"POST /presentations/{presentation_id}/sections:batch",
+		BatchSectionsHandler(logger, queries, conf),
+	)
+	mux.Handle(
+		"PUT /presentations/{presentation_id}/sections/order",
+		ReorderSectionsHandler(logger, queries, conf),
+	)
+
+	mux.Handle(
+		"GET /presentations/{presentation_id}/grants",
+		ListGrantsHandler(logger, queries, conf),
+	)
+	mux.Handle(
+		"PUT /presentations/{presentation_id}/grants/{username}",
+		PutGrantHandler(logger, queries, conf),
+	)
+	mux.Handle(
+		"DELETE /presentations/{presentation_id}/grants/{username}",
+		DeleteGrantHandler(logger, queries, conf),
+	)
+
+	mux.Handle(
+		"GET /presentations/{presentation_id}/share-links",
+		ListShareLinksHandler(logger, queries, conf),
 	)
+	mux.Handle(
+		"POST /presentations/{presentation_id}/share-links",
+		CreateShareLinkHandler(logger, queries, conf),
+	)
+	mux.Handle(
+		"DELETE /presentations/{presentation_id}/share-links/{id}",
+		DeleteShareLinkHandler(logger, queries, conf, shareLinkConns),
+	)
+
+	mux.Handle("GET /sections/{id}", GetSectionHandler(logger, queries, conf))
+	mux.Handle("DELETE /sections/{id}", DeleteSectionHandler(logger, queries, conf))
+	mux.Handle("PUT /sections/{id}", UpdateSectionHandler(logger, queries, conf))
+	mux.Handle("PATCH /sections/{id}", PatchSectionHandler(logger, queries, conf))
+
+	mux.Handle("POST /sections/{id}/move", MoveSectionHandler(logger, queries, conf))
+	mux.Handle("POST /sections/{id}/restore", RestoreSectionHandler(logger, queries, conf))
+
+	mux.Handle("GET /trash", ListTrashHandler(logger, queries, conf))
+
+	mux.Handle("/run/{id}", RunPresentation(logger, queries, conf, shareLinkConns))
+
+	mux.Handle("GET /stats/pool", PoolStatsHandler(logger, queries))
 
-	mux.Handle("GET /sections/{id}", GetSectionHandler(logger, queries))
-	mux.Handle("DELETE /sections/{id}", DeleteSectionHandler(logger, queries))
-	mux.Handle("PUT /sections/{id}", UpdateSectionHandler(logger, queries))
-	mux.Handle("PATCH /sections/{id}", PatchSectionHandler(logger, queries))
+	mux.Handle("GET /openapi.json", OpenAPIHandler())
+	mux.Handle("GET /asyncapi.json", AsyncAPIHandler())
+	mux.Handle("GET /docs", DocsHandler())
+	mux.Handle("GET /ui/", WebHandler())
 
-	mux.Handle("POST /sections/{id}/move", MoveSectionHandler(logger, queries))
+	mux.Handle("POST /accounts", CreateAccountHandler(logger, queries, conf))
+	mux.Handle("GET /accounts/me", GetCurrentAccountHandler(logger, queries, conf))
 
-	mux.Handle("/run/{id}", RunPresentation(logger, queries))
+	mux.Handle("POST /sessions", CreateSessionHandler(logger, queries, conf))
+	mux.Handle("DELETE /sessions/current", DeleteCurrentSessionHandler(logger, queries, conf))
+
+	mux.Handle("GET /api-keys", ListAPIKeysHandler(logger, queries, conf))
+	mux.Handle("POST /api-keys", CreateAPIKeyHandler(logger, queries, conf))
+	mux.Handle("DELETE /api-keys/{id}", DeleteAPIKeyHandler(logger, queries, conf))
+
+	mux.Handle("GET /workspaces", ListWorkspacesHandler(logger, queries, conf))
+	mux.Handle("POST /workspaces", CreateWorkspaceHandler(logger, queries, conf))
+	mux.Handle(
+		"GET /workspaces/{workspace_id}/members",
+		ListWorkspaceMembersHandler(logger, queries, conf),
+	)
+	mux.Handle(
+		"PUT /workspaces/{workspace_id}/members/{username}",
+		PutWorkspaceMemberHandler(logger, queries, conf),
+	)
+	mux.Handle(
+		"DELETE /workspaces/{workspace_id}/members/{username}",
+		DeleteWorkspaceMemberHandler(logger, queries, conf),
+	)
 
-	return mux
+	return Instrument(mux, Authenticate(logger, queries, conf, mux))
 }
@@ -1,38 +1,40 @@
 package server
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/PabloVarg/presentation-timer/internal/auth"
 	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	"github.com/PabloVarg/presentation-timer/internal/metrics"
 	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 	"golang.org/x/sync/errgroup"
 )
 
-type RunStatusResponse struct {
+// RunState is where a run is at.
+type RunState struct {
 	State  string          `json:"state"`
 	Step   queries.Section `json:"step"`
 	MsLeft int64           `json:"ms_left"`
-	// errors
-	Err string `json:"error,omitempty"`
 }
 
-func RunPresentation(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
-	type input struct {
-		Action string `json:"action"`
-		Step   *int32 `json:"step"`
-	}
-
+func RunPresentation(
+	logger *slog.Logger,
+	queriesStore store.Store,
+	conf Config,
+	shareLinkConns *ShareLinkConns,
+) http.Handler {
 	upgrader := websocket.Upgrader{
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
+		Subprotocols:    RunSubprotocols,
 	}
 	runs := make(map[int64]RunTask)
 
@@ -49,21 +51,33 @@ func RunPresentation(logger *slog.Logger, queriesStore *queries.Queries) http.Ha
 			return
 		}
 
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		if err := authorize(ctx, queriesStore, r, ID, PermissionView); err != nil {
+			cancel()
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+		canOperate := authorize(ctx, queriesStore, r, ID, PermissionOperate) == nil
+		cancel()
+
 		if _, ok := runs[ID]; !ok {
-			runs[ID], err = NewRun(ID, logger, queriesStore)
+			runs[ID], err = NewRun(ID, logger, queriesStore, conf.DBTimeout)
 			if err != nil {
 				helpers.InternalError(w, logger, err)
 				return
 			}
 		}
 
-		conn, err := upgrader.Upgrade(w, r, nil)
+		ws, err := upgrader.Upgrade(w, r, nil)
 		if err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
+		conn := newRunConn(ws)
+		metrics.RunConnections.Inc()
 		defer func() {
-			conn.Close()
+			metrics.RunConnections.Dec()
+			ws.Close()
 			runs[ID].RemoveConnection(u.String())
 
 			if runs[ID].Terminated() {
@@ -72,43 +86,62 @@ func RunPresentation(logger *slog.Logger, queriesStore *queries.Queries) http.Ha
 		}()
 
 		runs[ID].AddConnection(u.String(), conn)
+		if link, ok := auth.ShareLinkFromContext(r.Context()); ok {
+			defer shareLinkConns.Add(link, ws)()
+		}
 
 		for {
-			_, p, err := conn.ReadMessage()
+			_, p, err := ws.ReadMessage()
 			if err != nil {
 				return
 			}
 
-			var message input
-			if err := json.NewDecoder(bytes.NewReader(p)).Decode(&message); err != nil {
-				conn.WriteJSON(helpers.ErrorResponse{
-					Error: err.Error(),
-				})
+			// unknown actions are reported the same whatever the role of
+			// the caller
+			request, err := conn.decode(p)
+			if err != nil {
+				conn.sendError(request.RequestID, err.Error())
+				continue
+			}
+
+			if request.Action != RunMessageStatus && !canOperate {
+				conn.sendError(request.RequestID, "not allowed to control this run")
 				continue
 			}
 
-			switch message.Action {
-			case "status":
-				runs[ID].SendMsg(Status, WithConn(conn))
-			case "start":
-				runs[ID].SendMsg(StartPresentation, WithConn(conn))
-			case "pause":
-				runs[ID].SendMsg(PausePresentation, WithConn(conn))
-			case "resume":
-				runs[ID].SendMsg(ResumePresentation, WithConn(conn))
-			case "step":
-				if message.Step == nil {
-					conn.WriteJSON(helpers.ErrorResponse{
-						Error: "a step must be given for the step action",
-					})
+			opts := []func(*TaskMsg){WithConn(conn), WithRequestID(request.RequestID)}
+			switch request.Action {
+			case RunMessageStatus:
+				runs[ID].SendMsg(Status, opts...)
+			case RunMessageStart:
+				runs[ID].SendMsg(StartPresentation, opts...)
+			case RunMessagePause:
+				runs[ID].SendMsg(PausePresentation, opts...)
+			case RunMessageResume:
+				runs[ID].SendMsg(ResumePresentation, opts...)
+			case RunMessageStep:
+				if request.Step == nil {
+					conn.sendError(request.RequestID, "a step must be given for the step action")
 					break
 				}
 
-				runs[ID].SendMsg(StepInto, WithStep(*message.Step), WithConn(conn))
+				runs[ID].SendMsg(StepInto, append(opts, WithStep(*request.Step))...)
+			case RunMessageExtend:
+				if request.Ms == nil || *request.Ms <= 0 {
+					conn.sendError(request.RequestID, "a positive ms must be given for the extend action")
+					break
+				}
+				if *request.Ms > MaxRunExtension.Milliseconds() {
+					conn.sendError(
+						request.RequestID,
+						fmt.Sprintf("ms can be at most %d for the extend action", MaxRunExtension.Milliseconds()),
+					)
+					break
+				}
+
+				runs[ID].SendMsg(ExtendStep, append(opts, WithExtension(time.Duration(*request.Ms)*time.Millisecond))...)
 			default:
-				conn.WriteJSON(map[string]any{
-					"error": "command not recognized",
-				})
+				conn.sendError(request.RequestID, errUnknownAction.Error())
 			}
 
 		}
@@ -118,7 +151,7 @@ func RunPresentation(logger *slog.Logger, queriesStore *queries.Queries) http.Ha
 type RunTask struct {
 	presentationID int64
 	logger         *slog.Logger
-	conns          map[string]*websocket.Conn
+	conns          map[string]runConn
 	// sections
 	sections []queries.Section
 	// runs state
@@ -133,9 +166,11 @@ type RunTask struct {
 }
 
 type TaskMsg struct {
-	conn       *websocket.Conn
+	conn       *runConn
+	requestID  string
 	action     int
 	targetStep int32
+	extendBy   time.Duration
 }
 
 const (
@@ -144,16 +179,18 @@ const (
 	ResumePresentation
 	StepInto
 	Status
+	ExtendStep
 )
 
 func NewRun(
 	presentationID int64,
 	logger *slog.Logger,
-	queriesStore *queries.Queries,
+	queriesStore store.Store,
+	dbTimeout time.Duration,
 ) (RunTask, error) {
 	stoppedTimer := time.NewTimer(0)
 
-	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	dbCtx, cancel := context.WithTimeout(context.Background(), dbTimeout)
 	defer cancel()
 	sections, err := queriesStore.GetSectionsByPosition(dbCtx, presentationID)
 	if err != nil {
@@ -164,7 +201,7 @@ func NewRun(
 	task := RunTask{
 		presentationID: presentationID,
 		logger:         logger,
-		conns:          make(map[string]*websocket.Conn),
+		conns:          make(map[string]runConn),
 		sections:       sections,
 		// runs state
 		isRunning: false,
@@ -179,7 +216,7 @@ func NewRun(
 	return task, nil
 }
 
-func (t RunTask) AddConnection(ID string, conn *websocket.Conn) {
+func (t RunTask) AddConnection(ID string, conn runConn) {
 	t.conns[ID] = conn
 }
 
@@ -202,6 +239,8 @@ func (t RunTask) Terminated() bool {
 
 func (t *RunTask) Run() {
 	t.logger.Info("pr run", "event", "coroutine started")
+	metrics.RunTasks.Inc()
+	defer metrics.RunTasks.Dec()
 
 	for {
 		select {
@@ -232,10 +271,12 @@ func (t *RunTask) Run() {
 			t.Broadcast(t.GetRunState())
 		case msg := <-t.msg:
 			if err := t.HandleMsg(msg); err != nil {
-				state := t.GetRunState()
-				state.Err = err.Error()
+				t.RespondToMsg(msg, RunMessageError, helpers.ErrorResponse{Error: err.Error()})
+				continue
+			}
 
-				t.RespondToMsg(msg, state)
+			if msg.action != Status {
+				t.RespondToMsg(msg, RunMessageAck, nil)
 			}
 		}
 	}
@@ -246,7 +287,13 @@ func (t *RunTask) HandleMsg(msg TaskMsg) error {
 
 	switch msg.action {
 	case Status:
-		t.Broadcast(t.GetRunState())
+		// the legacy protocol has no replies, the state is sent to everyone
+		if msg.conn == nil || msg.conn.version == 0 {
+			t.Broadcast(t.GetRunState())
+			break
+		}
+
+		t.RespondToMsg(msg, RunMessageState, t.GetRunState())
 	case StartPresentation:
 		t.logger.Info("handle message", "case", "start presentation")
 		t.step = -1
@@ -281,12 +328,26 @@ func (t *RunTask) HandleMsg(msg TaskMsg) error {
 
 		t.step = msg.targetStep - 1
 		t.timer = time.NewTimer(0)
+	case ExtendStep:
+		t.logger.Info("handle message", "case", "extend step")
+		switch {
+		case t.isRunning && !t.timerEnd.IsZero():
+			t.timer.Stop()
+			t.timerEnd = t.timerEnd.Add(msg.extendBy)
+			t.timer = time.NewTimer(time.Until(t.timerEnd))
+		case t.timeRemaining != 0:
+			t.timeRemaining += msg.extendBy
+		default:
+			return errors.New("there is no step to extend, the run has not started or is over")
+		}
+
+		t.Broadcast(t.GetRunState())
 	}
 
 	return nil
 }
 
-func (t RunTask) GetRunState() RunStatusResponse {
+func (t RunTask) GetRunState() RunState {
 	step := t.step
 	if step < 0 {
 		step = 0
@@ -295,11 +356,13 @@ func (t RunTask) GetRunState() RunStatusResponse {
 		step = int32(len(t.sections)) - 1
 	}
 
-	state := RunStatusResponse{
+	state := RunState{
 		State:  "running",
-		Step:   t.sections[t.step],
 		MsLeft: t.timerEnd.Sub(time.Now()).Milliseconds(),
 	}
+	if step >= 0 {
+		state.Step = t.sections[step]
+	}
 
 	if !t.isRunning {
 		state.State = "stopped"
@@ -331,27 +394,66 @@ func WithStep(step int32) func(*TaskMsg) {
 	}
 }
 
-func WithConn(conn *websocket.Conn) func(*TaskMsg) {
+// WithExtension sets how much time is added to the current step.
+func WithExtension(d time.Duration) func(*TaskMsg) {
 	return func(tm *TaskMsg) {
-		tm.conn = conn
+		tm.extendBy = d
 	}
 }
 
-func (t RunTask) Broadcast(msg any) {
-	b, err := json.Marshal(msg)
-	if err != nil {
-		t.logger.Error("ws broadcast", "err", err)
+func WithConn(conn runConn) func(*TaskMsg) {
+	return func(tm *TaskMsg) {
+		tm.conn = &conn
 	}
+}
 
-	pm, err := websocket.NewPreparedMessage(websocket.BinaryMessage, b)
-	if err != nil {
-		t.logger.Error("ws broadcast", "err", err)
+// WithRequestID sets the ID of the request the message answers, so the reply
+// can be correlated to it.
+func WithRequestID(ID string) func(*TaskMsg) {
+	return func(tm *TaskMsg) {
+		tm.requestID = ID
+	}
+}
+
+// Broadcast sends state to every connection of the run in the protocol each
+// of them speaks.
+func (t RunTask) Broadcast(state RunState) {
+	prepared := make(map[int]*websocket.PreparedMessage)
+	for _, conn := range t.conns {
+		if _, ok := prepared[conn.version]; ok {
+			continue
+		}
+
+		messageType := websocket.TextMessage
+		if conn.version == 0 {
+			messageType = websocket.BinaryMessage
+		}
+
+		b, err := conn.encode(RunMessageState, "", state)
+		if err != nil {
+			t.logger.Error("ws broadcast", "err", err)
+			metrics.RunBroadcastFailures.Add(float64(len(t.conns)))
+			return
+		}
+
+		pm, err := websocket.NewPreparedMessage(messageType, b)
+		if err != nil {
+			t.logger.Error("ws broadcast", "err", err)
+			metrics.RunBroadcastFailures.Add(float64(len(t.conns)))
+			return
+		}
+		prepared[conn.version] = pm
 	}
 
 	g := new(errgroup.Group)
 	for _, conn := range t.conns {
 		g.Go(func() error {
-			return conn.WritePreparedMessage(pm)
+			err := conn.writePrepared(prepared[conn.version])
+			if err != nil {
+				metrics.RunBroadcastFailures.Inc()
+			}
+
+			return err
 		})
 	}
 	if err := g.Wait(); err != nil {
@@ -359,15 +461,16 @@ func (t RunTask) Broadcast(msg any) {
 	}
 }
 
-func (t RunTask) RespondToMsg(msg TaskMsg, message any) {
+// RespondToMsg replies to the connection msg came from, if any.
+func (t RunTask) RespondToMsg(msg TaskMsg, messageType string, payload any) {
 	if msg.conn == nil {
 		return
 	}
 
-	if err := msg.conn.WriteJSON(message); err != nil {
+	if err := msg.conn.send(messageType, msg.requestID, payload); err != nil {
 		switch {
 		case errors.Is(err, websocket.ErrCloseSent):
-			t.logger.Info("send response to closed conn", "message", msg, "response", message)
+			t.logger.Info("send response to closed conn", "message", msg, "response", payload)
 			return
 		default:
 			t.logger.Error("failed to send ws response", "err", err)
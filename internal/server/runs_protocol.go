@@ -0,0 +1,214 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	"github.com/gorilla/websocket"
+)
+
+// RunProtocolV1 is the websocket subprotocol of the first version of the run
+// messages, every message is wrapped in a RunEnvelope. Connections that don't
+// ask for a subprotocol speak the legacy protocol, whose messages are the bare
+// payloads and whose version is 0.
+const RunProtocolV1 = "presentation-timer.v1"
+
+// RunSubprotocols are the subprotocols offered by the run websocket, from the
+// most to the least preferred.
+var RunSubprotocols = []string{RunProtocolV1}
+
+// Types of the messages sent by clients.
+const (
+	RunMessageStatus = "status"
+	RunMessageStart  = "start"
+	RunMessagePause  = "pause"
+	RunMessageResume = "resume"
+	RunMessageStep   = "step"
+	RunMessageExtend = "extend"
+)
+
+// Types of the messages sent by the server.
+const (
+	RunMessageState = "state"
+	RunMessageAck   = "ack"
+	RunMessageError = "error"
+)
+
+var RunActions = []string{
+	RunMessageStatus,
+	RunMessageStart,
+	RunMessagePause,
+	RunMessageResume,
+	RunMessageStep,
+	RunMessageExtend,
+}
+
+// RunEnvelope wraps every message of a versioned run protocol. Replies to a
+// request carry its RequestID, messages broadcast to every connection of the
+// run have none.
+type RunEnvelope struct {
+	Type      string          `json:"type"`
+	Version   int             `json:"version"`
+	RequestID string          `json:"request_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// RunStepPayload is the payload of step requests.
+type RunStepPayload struct {
+	Step *int32 `json:"step"`
+}
+
+// MaxRunExtension is the most a single extend request adds to a step.
+const MaxRunExtension = 24 * time.Hour
+
+// RunExtendPayload is the payload of extend requests, Ms is how many
+// milliseconds are added to the current step.
+type RunExtendPayload struct {
+	Ms *int64 `json:"ms"`
+}
+
+// runRequest is a message received from a run connection, whatever the
+// protocol it was sent with.
+type runRequest struct {
+	Action    string
+	Step      *int32
+	Ms        *int64
+	RequestID string
+}
+
+var errUnknownAction = errors.New("command not recognized")
+
+// runConn is a connection to a run along with the version of the protocol it
+// speaks. Writes are serialized as the connection is written to both by its
+// handler and by the run.
+type runConn struct {
+	conn    *websocket.Conn
+	version int
+	mu      *sync.Mutex
+}
+
+func newRunConn(conn *websocket.Conn) runConn {
+	version := 0
+	if conn.Subprotocol() == RunProtocolV1 {
+		version = 1
+	}
+
+	return runConn{
+		conn:    conn,
+		version: version,
+		mu:      &sync.Mutex{},
+	}
+}
+
+// decode reads a request sent in the protocol of the connection, failing with
+// errUnknownAction for actions that don't exist. The request ID is returned
+// even when the request is invalid so the error can be correlated to it.
+func (c runConn) decode(p []byte) (runRequest, error) {
+	if c.version == 0 {
+		var input struct {
+			Action string `json:"action"`
+			Step   *int32 `json:"step"`
+			Ms     *int64 `json:"ms"`
+		}
+		if err := json.Unmarshal(p, &input); err != nil {
+			return runRequest{}, err
+		}
+
+		request := runRequest{Action: input.Action, Step: input.Step, Ms: input.Ms}
+		if !slices.Contains(RunActions, request.Action) {
+			return request, errUnknownAction
+		}
+
+		return request, nil
+	}
+
+	var envelope RunEnvelope
+	if err := json.Unmarshal(p, &envelope); err != nil {
+		return runRequest{}, err
+	}
+
+	request := runRequest{Action: envelope.Type, RequestID: envelope.RequestID}
+	if envelope.Version != c.version {
+		return request, fmt.Errorf("version %d is not supported by %s", envelope.Version, RunProtocolV1)
+	}
+	if !slices.Contains(RunActions, envelope.Type) {
+		return request, errUnknownAction
+	}
+
+	if envelope.Type == RunMessageStep && len(envelope.Payload) != 0 {
+		var payload RunStepPayload
+		if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+			return request, err
+		}
+
+		request.Step = payload.Step
+	}
+
+	if envelope.Type == RunMessageExtend && len(envelope.Payload) != 0 {
+		var payload RunExtendPayload
+		if err := json.Unmarshal(envelope.Payload, &payload); err != nil {
+			return request, err
+		}
+
+		request.Ms = payload.Ms
+	}
+
+	return request, nil
+}
+
+// encode converts a message to the protocol of the connection. Legacy
+// connections get the payload alone and no acknowledgements, nil is returned
+// for messages that must not be sent.
+func (c runConn) encode(messageType, requestID string, payload any) ([]byte, error) {
+	if c.version == 0 {
+		if messageType == RunMessageAck {
+			return nil, nil
+		}
+
+		return json.Marshal(payload)
+	}
+
+	envelope := RunEnvelope{
+		Type:      messageType,
+		Version:   c.version,
+		RequestID: requestID,
+	}
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+
+		envelope.Payload = encoded
+	}
+
+	return json.Marshal(envelope)
+}
+
+func (c runConn) send(messageType, requestID string, payload any) error {
+	message, err := c.encode(messageType, requestID, payload)
+	if err != nil || message == nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.conn.WriteMessage(websocket.TextMessage, message)
+}
+
+func (c runConn) sendError(requestID string, message string) error {
+	return c.send(RunMessageError, requestID, helpers.ErrorResponse{Error: message})
+}
+
+func (c runConn) writePrepared(message *websocket.PreparedMessage) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.conn.WritePreparedMessage(message)
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRunConnDecodeAction(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int
+		message string
+		action  string
+		err     error
+	}{
+		{"legacy action", 0, `{"action": "start"}`, RunMessageStart, nil},
+		{"legacy unknown action", 0, `{"action": "explode"}`, "explode", errUnknownAction},
+		{"legacy missing action", 0, `{}`, "", errUnknownAction},
+		{"v1 action", 1, `{"type": "pause", "version": 1}`, RunMessagePause, nil},
+		{"v1 unknown action", 1, `{"type": "explode", "version": 1}`, "explode", errUnknownAction},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request, err := runConn{version: tt.version}.decode([]byte(tt.message))
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("decode returned %v, want %v", err, tt.err)
+			}
+			if request.Action != tt.action {
+				t.Errorf("action is %q, want %q", request.Action, tt.action)
+			}
+		})
+	}
+}
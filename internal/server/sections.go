@@ -11,15 +11,15 @@ import (
 	"github.com/PabloVarg/presentation-timer/internal/filters"
 	"github.com/PabloVarg/presentation-timer/internal/helpers"
 	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
 	"github.com/PabloVarg/presentation-timer/internal/validation"
-	"github.com/jackc/pgx/v5/pgconn"
 )
 
 const SectionsPageSize = 20
 
-var SectionsSortFields = []string{"name", "duration", "position"}
+var SectionsSortFields = []string{"name", "duration", "position", "created_at"}
 
-func ListSectionsHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func ListSectionsHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	type output struct {
 		Data     []queries.Section `json:"data"`
 		PageInfo filters.PageInfo  `json:"page_info"`
@@ -32,44 +32,93 @@ func ListSectionsHandler(logger *slog.Logger, queriesStore *queries.Queries) htt
 			return
 		}
 
-		f, v := filters.FromRequest(r, SectionsPageSize, SectionsSortFields...)
+		f, v := filters.FromRequestWithCursor(r, conf.SectionsPageSize, SectionsSortFields...)
 		if !v.Valid() {
 			helpers.UnprocessableContent(w, v.Errors())
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		fields, v := filters.FieldFiltersFromRequest(r)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionView); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		cursorID, cursor := f.QueryCursor()
 		sections, err := queriesStore.GetSections(ctx, queries.GetSectionsParams{
-			PresentationID: presentationID,
-			Direction:      f.QuerySortDirection(),
-			SortBy:         f.QuerySortBy(),
-			QueryLimit:     f.QueryLimit(),
-			QueryOffset:    f.QueryOffset(),
+			PresentationID:  presentationID,
+			NameContains:    fields.NameContains,
+			MinDuration:     fields.MinDuration,
+			MaxDuration:     fields.MaxDuration,
+			CreatedAfter:    fields.CreatedAfter,
+			Search:          fields.Search,
+			Direction1:      f.QuerySortDirection(0),
+			SortBy1:         f.QuerySortBy(0),
+			Direction2:      f.QuerySortDirection(1),
+			SortBy2:         f.QuerySortBy(1),
+			Direction3:      f.QuerySortDirection(2),
+			SortBy3:         f.QuerySortBy(2),
+			IDDirection:     f.QueryIDDirection(),
+			CursorID:        cursorID,
+			CursorName:      cursor.Name,
+			CursorDuration:  cursor.Duration,
+			CursorPosition:  cursor.Position,
+			CursorCreatedAt: cursor.CreatedAt,
+			QueryLimit:      f.QueryCursorLimit(),
+			QueryOffset:     f.QueryOffset(),
 		})
 		if err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 
-		totalRows, err := queriesStore.GetSectionsMetadata(ctx, presentationID)
+		totalRows, err := queriesStore.GetSectionsMetadata(ctx, queries.GetSectionsMetadataParams{
+			PresentationID: presentationID,
+			NameContains:   fields.NameContains,
+			MinDuration:    fields.MinDuration,
+			MaxDuration:    fields.MaxDuration,
+			CreatedAfter:   fields.CreatedAfter,
+			Search:         fields.Search,
+		})
 		if err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 
-		if err := helpers.WriteJSON(w, http.StatusOK, output{
+		sections, pageInfo := filters.CursorPage(
+			f,
+			sections,
+			f.PageInfo(totalRows),
+			func(section queries.Section) filters.CursorValues {
+				return filters.CursorValues{
+					ID:        section.ID,
+					Name:      section.Name,
+					Duration:  section.Duration,
+					Position:  section.Position,
+					CreatedAt: section.CreatedAt,
+				}
+			},
+		)
+
+		if err := helpers.WriteJSONETag(w, r, http.StatusOK, output{
 			Data:     sections,
-			PageInfo: f.PageInfo(totalRows),
-		}); err != nil {
+			PageInfo: pageInfo,
+		}, ""); err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 	})
 }
 
-func GetSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func GetSectionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ID, v := helpers.ParseID(r, "id")
 		if !v.Valid() {
@@ -77,7 +126,7 @@ func GetSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) http.
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
 		section, err := queriesStore.GetSection(ctx, ID)
@@ -91,14 +140,20 @@ func GetSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) http.
 			return
 		}
 
-		if err := helpers.WriteJSON(w, http.StatusOK, section); err != nil {
+		if err := authorize(ctx, queriesStore, r, section.Presentation, PermissionView); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		etag := helpers.ETag(section.Version)
+		if err := helpers.WriteJSONETag(w, r, http.StatusOK, section, etag); err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 	})
 }
 
-func CreateSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func CreateSectionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	type input struct {
 		Name     *string        `json:"name"`
 		Duration *time.Duration `json:"duration"`
@@ -128,30 +183,57 @@ func CreateSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) ht
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		if input.Position == nil {
-			position, err := queriesStore.MaxPosition(ctx, presentationID)
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		var section queries.Section
+		err := queriesStore.InTx(ctx, func(tx store.Store) error {
+			// serializes position changes of the same presentation
+			if _, err := tx.LockPresentation(ctx, presentationID); err != nil {
+				return err
+			}
+
+			if input.Position == nil {
+				position, err := tx.MaxPosition(ctx, presentationID)
+				if err != nil {
+					return err
+				}
+
+				input.Position = &position
+				*input.Position += 1
+			}
+
+			var err error
+			section, err = tx.CreateSection(ctx, queries.CreateSectionParams{
+				Presentation: presentationID,
+				Name:         *input.Name,
+				Duration:     *input.Duration,
+				Position:     *input.Position,
+			})
 			if err != nil {
-				helpers.InternalError(w, logger, err)
-				return
+				return err
 			}
 
-			input.Position = &position
-			*input.Position += 1
-		}
+			if err := recordAudit(ctx, tx, r, auditChange{
+				Presentation: presentationID,
+				Section:      &section.ID,
+				Action:       AuditActionCreate,
+				After:        section,
+			}); err != nil {
+				return err
+			}
 
-		presentation, err := queriesStore.CreateSection(ctx, queries.CreateSectionParams{
-			Presentation: presentationID,
-			Name:         *input.Name,
-			Duration:     *input.Duration,
-			Position:     *input.Position,
+			_, err = recordVersion(ctx, tx, r, presentationID)
+			return err
 		})
 		if err != nil {
-			var pgErr *pgconn.PgError
-			switch errors.As(err, &pgErr) {
-			case pgErr.ConstraintName != "":
+			switch {
+			case errors.Is(err, store.ErrInvalidReference), errors.Is(err, sql.ErrNoRows):
 				v := validation.New()
 				v.AddErrors("presentation", "presentation does not exist")
 				helpers.UnprocessableContent(w, v.Errors())
@@ -161,14 +243,15 @@ func CreateSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) ht
 			return
 		}
 
-		if err := helpers.WriteJSON(w, http.StatusCreated, presentation); err != nil {
+		w.Header().Set("ETag", helpers.ETag(section.Version))
+		if err := helpers.WriteJSON(w, http.StatusCreated, section); err != nil {
 			helpers.InternalError(w, logger, err)
 			return
 		}
 	})
 }
 
-func UpdateSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func UpdateSectionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	type input struct {
 		Name     *string        `json:"name"`
 		Duration *time.Duration `json:"duration"`
@@ -198,29 +281,40 @@ func UpdateSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) ht
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		rows, err := queriesStore.UpdateSection(ctx, queries.UpdateSectionParams{
-			ID:       ID,
-			Name:     *input.Name,
-			Duration: *input.Duration,
-			Position: *input.Position,
-		})
-		if err != nil {
-			helpers.InternalError(w, logger, err)
+		if err := authorizeSection(ctx, queriesStore, r, ID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
 			return
 		}
-		if rows == 0 {
-			http.NotFound(w, r)
+
+		section, err := changeSection(ctx, queriesStore, r, ID, AuditActionUpdate, func(tx store.Store) (int64, error) {
+			return tx.UpdateSection(ctx, queries.UpdateSectionParams{
+				ID:       ID,
+				Name:     *input.Name,
+				Duration: *input.Duration,
+				Position: *input.Position,
+			})
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			case errors.Is(err, errPreconditionFailed):
+				helpers.PreconditionFailed(w, err.Error())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
 			return
 		}
 
+		w.Header().Set("ETag", helpers.ETag(section.Version))
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
 
-func PatchSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func PatchSectionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	type input struct {
 		Name     *string        `json:"name"`
 		Duration *time.Duration `json:"duration"`
@@ -252,29 +346,40 @@ func PatchSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) htt
 			ValidatePosition(v, input.Position)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		rows, err := queriesStore.PatchSection(ctx, queries.PatchSectionParams{
-			ID:       ID,
-			Name:     input.Name,
-			Duration: input.Duration,
-			Position: input.Position,
-		})
-		if err != nil {
-			helpers.InternalError(w, logger, err)
+		if err := authorizeSection(ctx, queriesStore, r, ID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
 			return
 		}
-		if rows == 0 {
-			http.NotFound(w, r)
+
+		section, err := changeSection(ctx, queriesStore, r, ID, AuditActionPatch, func(tx store.Store) (int64, error) {
+			return tx.PatchSection(ctx, queries.PatchSectionParams{
+				ID:       ID,
+				Name:     input.Name,
+				Duration: input.Duration,
+				Position: input.Position,
+			})
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			case errors.Is(err, errPreconditionFailed):
+				helpers.PreconditionFailed(w, err.Error())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
 			return
 		}
 
+		w.Header().Set("ETag", helpers.ETag(section.Version))
 		w.WriteHeader(http.StatusNoContent)
 	})
 }
 
-func DeleteSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) http.Handler {
+func DeleteSectionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ID, v := helpers.ParseID(r, "id")
 		if !v.Valid() {
@@ -282,16 +387,26 @@ func DeleteSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) ht
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		rows, err := queriesStore.DeleteSection(ctx, ID)
-		if err != nil {
-			helpers.InternalError(w, logger, err)
+		if err := authorizeSection(ctx, queriesStore, r, ID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
 			return
 		}
-		if rows == 0 {
-			http.NotFound(w, r)
+
+		_, err := changeSection(ctx, queriesStore, r, ID, AuditActionDelete, func(tx store.Store) (int64, error) {
+			return tx.DeleteSection(ctx, queries.DeleteSectionParams{ID: ID, Now: time.Now()})
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			case errors.Is(err, errPreconditionFailed):
+				helpers.PreconditionFailed(w, err.Error())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
 			return
 		}
 
@@ -301,11 +416,9 @@ func DeleteSectionHandler(logger *slog.Logger, queriesStore *queries.Queries) ht
 
 func MoveSectionHandler(
 	logger *slog.Logger,
-	queriesStore *queries.Queries,
+	queriesStore store.Store,
+	conf Config,
 ) http.Handler {
-	type input struct {
-		Move *int32 `json:"move"`
-	}
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ID, v := helpers.ParseID(r, "id")
@@ -314,7 +427,7 @@ func MoveSectionHandler(
 			return
 		}
 
-		var input input
+		var input SectionMovement
 		err := helpers.ReadJSON(r.Body, &input)
 		if err != nil {
 			helpers.BadRequest(w, err.Error())
@@ -322,28 +435,110 @@ func MoveSectionHandler(
 		}
 
 		v = validation.New()
-		ValidateMovement(v, input.Move)
+		ValidateMovement(v, input)
 		if !v.Valid() {
 			helpers.UnprocessableContent(w, v.Errors())
 			return
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
 		defer cancel()
 
-		err = queriesStore.CleanPositionsBySectionGroup(ctx, ID)
-		if err != nil {
-			helpers.InternalError(w, logger, err)
+		if err := authorizeSection(ctx, queriesStore, r, ID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
 			return
 		}
 
-		err = queriesStore.MoveSection(ctx, queries.MoveSectionParams{
-			ID:      ID,
-			Column2: *input.Move,
+		section, err := changeSection(ctx, queriesStore, r, ID, AuditActionMove, func(tx store.Store) (int64, error) {
+			section, err := tx.GetSection(ctx, ID)
+			if err != nil {
+				return 0, err
+			}
+
+			return 1, moveSection(ctx, tx, section, input)
 		})
 		if err != nil {
-			helpers.InternalError(w, logger, err)
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			case errors.Is(err, errSectionNotInPresentation):
+				v := validation.New()
+				v.AddErrors(input.key(), err.Error())
+				helpers.UnprocessableContent(w, v.Errors())
+			case errors.Is(err, errPreconditionFailed):
+				helpers.PreconditionFailed(w, err.Error())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
 			return
 		}
+
+		w.Header().Set("ETag", helpers.ETag(section.Version))
+	})
+}
+
+// changeSection applies change to the section in a transaction, recording it
+// in the audit log of its presentation and as a new version of it. change
+// returns the affected rows, sql.ErrNoRows is returned when there were none
+// and errPreconditionFailed when r has an If-Match header that doesn't match
+// the section. The section is returned as it is after the change.
+//
+// The presentation is locked before the section, in the order the endpoints
+// changing several sections take them, so moves renumbering its sections run
+// one at a time.
+func changeSection(
+	ctx context.Context,
+	queriesStore store.Store,
+	r *http.Request,
+	ID int64,
+	action string,
+	change func(tx store.Store) (int64, error),
+) (queries.Section, error) {
+	var after queries.Section
+	err := queriesStore.InTx(ctx, func(tx store.Store) error {
+		section, err := tx.GetSection(ctx, ID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.LockPresentation(ctx, section.Presentation); err != nil {
+			return err
+		}
+
+		before, err := tx.LockSection(ctx, ID)
+		if err != nil {
+			return err
+		}
+		if err := checkIfMatch(r, before.Version); err != nil {
+			return err
+		}
+
+		rows, err := change(tx)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return sql.ErrNoRows
+		}
+
+		entry := auditChange{
+			Presentation: before.Presentation,
+			Section:      &ID,
+			Action:       action,
+			Before:       before,
+		}
+		if action != AuditActionDelete {
+			if after, err = tx.GetSection(ctx, ID); err != nil {
+				return err
+			}
+			entry.After = after
+		}
+		if err := recordAudit(ctx, tx, r, entry); err != nil {
+			return err
+		}
+
+		_, err = recordVersion(ctx, tx, r, before.Presentation)
+		return err
 	})
+
+	return after, err
 }
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+const BatchMaxOperations = 100
+
+const (
+	BatchOperationCreate = "create"
+	BatchOperationUpdate = "update"
+	BatchOperationDelete = "delete"
+	BatchOperationMove   = "move"
+)
+
+var BatchOperations = []string{
+	BatchOperationCreate,
+	BatchOperationUpdate,
+	BatchOperationDelete,
+	BatchOperationMove,
+}
+
+// BatchOperation is one of the changes sent to the sections batch endpoint,
+// which fields are used depends on Op.
+type BatchOperation struct {
+	Op       *string        `json:"op"`
+	ID       *int64         `json:"id"`
+	Name     *string        `json:"name"`
+	Duration *time.Duration `json:"duration"`
+	Position *int16         `json:"position"`
+	SectionMovement
+}
+
+type BatchResult struct {
+	Op      string           `json:"op"`
+	ID      int64            `json:"id"`
+	Section *queries.Section `json:"section,omitempty"`
+}
+
+var errBatchOperation = errors.New("batch operation failed")
+
+// BatchSectionsHandler applies a list of operations to the sections of a
+// presentation in a single transaction, either all of them are applied or
+// none is.
+func BatchSectionsHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		Operations []BatchOperation `json:"operations"`
+	}
+
+	type output struct {
+		Data []BatchResult `json:"data"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		presentationID, v := helpers.ParseID(r, "presentation_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		if err := helpers.ReadJSON(r.Body, &input); err != nil {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v = validation.New()
+		ValidateBatchOperations(v, input.Operations)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		results := make([]BatchResult, 0, len(input.Operations))
+		err := queriesStore.InTx(ctx, func(tx store.Store) error {
+			// serializes position changes of the same presentation
+			if _, err := tx.LockPresentation(ctx, presentationID); err != nil {
+				return err
+			}
+
+			for i, operation := range input.Operations {
+				result, err := applyBatchOperation(ctx, tx, r, presentationID, operation)
+				if err != nil {
+					if errors.Is(err, sql.ErrNoRows) {
+						v.AddErrors(
+							fmt.Sprintf("operations[%d].id", i),
+							"section does not exist in the presentation",
+						)
+						return errBatchOperation
+					}
+					if errors.Is(err, errSectionNotInPresentation) {
+						v.AddErrors(
+							fmt.Sprintf("operations[%d].%s", i, operation.key()),
+							err.Error(),
+						)
+						return errBatchOperation
+					}
+
+					return err
+				}
+
+				results = append(results, result)
+			}
+
+			_, err := recordVersion(ctx, tx, r, presentationID)
+			return err
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, errBatchOperation):
+				helpers.UnprocessableContent(w, v.Errors())
+			case errors.Is(err, store.ErrInvalidReference), errors.Is(err, sql.ErrNoRows):
+				v := validation.New()
+				v.AddErrors("presentation", "presentation does not exist")
+				helpers.UnprocessableContent(w, v.Errors())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{Data: results}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+// applyBatchOperation applies a single validated operation inside the batch
+// transaction and records it in the audit log. sql.ErrNoRows is returned when
+// the section of the operation is not part of presentationID. The caller holds
+// the lock of the presentation.
+func applyBatchOperation(
+	ctx context.Context,
+	tx store.Store,
+	r *http.Request,
+	presentationID int64,
+	operation BatchOperation,
+) (BatchResult, error) {
+	result := BatchResult{Op: *operation.Op}
+
+	if *operation.Op == BatchOperationCreate {
+		position := operation.Position
+		if position == nil {
+			maxPosition, err := tx.MaxPosition(ctx, presentationID)
+			if err != nil {
+				return result, err
+			}
+
+			maxPosition += 1
+			position = &maxPosition
+		}
+
+		section, err := tx.CreateSection(ctx, queries.CreateSectionParams{
+			Presentation: presentationID,
+			Name:         *operation.Name,
+			Duration:     *operation.Duration,
+			Position:     *position,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		result.ID = section.ID
+		result.Section = &section
+
+		return result, recordAudit(ctx, tx, r, auditChange{
+			Presentation: presentationID,
+			Section:      &section.ID,
+			Action:       AuditActionCreate,
+			After:        section,
+		})
+	}
+
+	ID := *operation.ID
+	result.ID = ID
+
+	before, err := tx.LockSection(ctx, ID)
+	if err != nil {
+		return result, err
+	}
+	if before.Presentation != presentationID {
+		return result, sql.ErrNoRows
+	}
+
+	var (
+		rows   int64
+		action string
+	)
+	switch *operation.Op {
+	case BatchOperationUpdate:
+		action = AuditActionUpdate
+		rows, err = tx.PatchSection(ctx, queries.PatchSectionParams{
+			ID:       ID,
+			Name:     operation.Name,
+			Duration: operation.Duration,
+			Position: operation.Position,
+		})
+	case BatchOperationDelete:
+		action = AuditActionDelete
+		rows, err = tx.DeleteSection(ctx, queries.DeleteSectionParams{ID: ID, Now: time.Now()})
+	case BatchOperationMove:
+		action = AuditActionMove
+		rows, err = 1, moveSection(ctx, tx, before, operation.SectionMovement)
+	}
+	if err != nil {
+		return result, err
+	}
+	if rows == 0 {
+		return result, sql.ErrNoRows
+	}
+
+	entry := auditChange{
+		Presentation: presentationID,
+		Section:      &ID,
+		Action:       action,
+		Before:       before,
+	}
+	if action != AuditActionDelete {
+		after, err := tx.GetSection(ctx, ID)
+		if err != nil {
+			return result, err
+		}
+
+		entry.After = after
+		result.Section = &after
+	}
+
+	return result, recordAudit(ctx, tx, r, entry)
+}
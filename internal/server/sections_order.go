@@ -0,0 +1,248 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+// SectionMovement is where a section is moved to, either Move positions away
+// from where it is or right before or after another section of its
+// presentation. Only one of them is given.
+type SectionMovement struct {
+	Move       *int32 `json:"move"`
+	MoveBefore *int64 `json:"move_before"`
+	MoveAfter  *int64 `json:"move_after"`
+}
+
+// key is the input field that holds the movement.
+func (m SectionMovement) key() string {
+	switch {
+	case m.MoveBefore != nil:
+		return "move_before"
+	case m.MoveAfter != nil:
+		return "move_after"
+	default:
+		return "move"
+	}
+}
+
+var (
+	errSectionNotInPresentation = errors.New("section does not exist in the presentation")
+	errInvalidOrder             = errors.New("sections must list every section of the presentation exactly once")
+)
+
+// ReorderSectionsHandler sets the order of all the sections of a presentation
+// at once, the given IDs must be exactly the sections of the presentation.
+func ReorderSectionsHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		Sections []int64 `json:"sections"`
+	}
+
+	type output struct {
+		Data []queries.Section `json:"data"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		presentationID, v := helpers.ParseID(r, "presentation_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		if err := helpers.ReadJSON(r.Body, &input); err != nil {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v = validation.New()
+		ValidateSectionOrder(v, input.Sections)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		var sections []queries.Section
+		err := queriesStore.InTx(ctx, func(tx store.Store) error {
+			// serializes reorders of the same presentation
+			if _, err := tx.LockPresentation(ctx, presentationID); err != nil {
+				return err
+			}
+
+			current, err := tx.GetSectionsByPosition(ctx, presentationID)
+			if err != nil {
+				return err
+			}
+			if !isPermutation(current, input.Sections) {
+				return errInvalidOrder
+			}
+
+			changed, err := reorderSections(ctx, tx, current, input.Sections)
+			if err != nil {
+				return err
+			}
+
+			if sections, err = tx.GetSectionsByPosition(ctx, presentationID); err != nil {
+				return err
+			}
+			for _, after := range sections {
+				before, ok := changed[after.ID]
+				if !ok {
+					continue
+				}
+
+				if err := recordAudit(ctx, tx, r, auditChange{
+					Presentation: presentationID,
+					Section:      &after.ID,
+					Action:       AuditActionMove,
+					Before:       before,
+					After:        after,
+				}); err != nil {
+					return err
+				}
+			}
+			if len(changed) == 0 {
+				return nil
+			}
+
+			_, err = recordVersion(ctx, tx, r, presentationID)
+			return err
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, errInvalidOrder):
+				v := validation.New()
+				v.AddErrors("sections", err.Error())
+				helpers.UnprocessableContent(w, v.Errors())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{Data: sections}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+// moveSection moves section as described by movement, renumbering the
+// sections of its presentation. errSectionNotInPresentation is returned when
+// the section to move before or after is not part of the presentation. The
+// caller holds the lock of the presentation.
+func moveSection(
+	ctx context.Context,
+	tx store.Store,
+	section queries.Section,
+	movement SectionMovement,
+) error {
+	sections, err := tx.GetSectionsByPosition(ctx, section.Presentation)
+	if err != nil {
+		return err
+	}
+
+	from := -1
+	order := make([]int64, 0, len(sections))
+	for i, current := range sections {
+		if current.ID == section.ID {
+			from = i
+			continue
+		}
+
+		order = append(order, current.ID)
+	}
+	if from == -1 {
+		return errSectionNotInPresentation
+	}
+
+	var to int
+	switch {
+	case movement.Move != nil:
+		to = min(max(from+int(*movement.Move), 0), len(order))
+	case movement.MoveBefore != nil:
+		if to = slices.Index(order, *movement.MoveBefore); to == -1 {
+			return errSectionNotInPresentation
+		}
+	case movement.MoveAfter != nil:
+		if to = slices.Index(order, *movement.MoveAfter); to == -1 {
+			return errSectionNotInPresentation
+		}
+		to++
+	}
+
+	_, err = reorderSections(ctx, tx, sections, slices.Insert(order, to, section.ID))
+	return err
+}
+
+// reorderSections gives the sections positions following order, which must
+// be a permutation of sections. Only the sections whose position changes are
+// updated, they are returned by ID as they were before.
+func reorderSections(
+	ctx context.Context,
+	tx store.Store,
+	sections []queries.Section,
+	order []int64,
+) (map[int64]queries.Section, error) {
+	byID := make(map[int64]queries.Section, len(sections))
+	for _, section := range sections {
+		byID[section.ID] = section
+	}
+
+	changed := make(map[int64]queries.Section)
+	for i, ID := range order {
+		section := byID[ID]
+		position := int16(i + 1)
+		if section.Position == position {
+			continue
+		}
+
+		if err := tx.SetSectionPosition(ctx, queries.SetSectionPositionParams{
+			ID:       ID,
+			Position: position,
+		}); err != nil {
+			return nil, err
+		}
+		changed[ID] = section
+	}
+
+	return changed, nil
+}
+
+// isPermutation reports whether order holds the IDs of sections exactly once
+// each.
+func isPermutation(sections []queries.Section, order []int64) bool {
+	if len(sections) != len(order) {
+		return false
+	}
+
+	pending := make(map[int64]bool, len(sections))
+	for _, section := range sections {
+		pending[section.ID] = true
+	}
+	for _, ID := range order {
+		if !pending[ID] {
+			return false
+		}
+		delete(pending, ID)
+	}
+
+	return true
+}
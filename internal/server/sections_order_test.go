@@ -0,0 +1,35 @@
+package server
+
+import (
+	"testing"
+
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+)
+
+func TestIsPermutation(t *testing.T) {
+	sections := []queries.Section{{ID: 4}, {ID: 7}, {ID: 9}}
+
+	tests := []struct {
+		name     string
+		sections []queries.Section
+		order    []int64
+		want     bool
+	}{
+		{"same order", sections, []int64{4, 7, 9}, true},
+		{"reordered", sections, []int64{9, 4, 7}, true},
+		{"no sections", nil, []int64{}, true},
+		{"missing one", sections, []int64{4, 9}, false},
+		{"extra one", sections, []int64{4, 7, 9, 11}, false},
+		{"unknown one", sections, []int64{4, 7, 11}, false},
+		{"repeated one", sections, []int64{4, 4, 9}, false},
+		{"order of no sections", nil, []int64{4}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPermutation(tt.sections, tt.order); got != tt.want {
+				t.Errorf("isPermutation(%v) = %v, want %v", tt.order, got, tt.want)
+			}
+		})
+	}
+}
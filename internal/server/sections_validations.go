@@ -1,6 +1,7 @@
 package server
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/PabloVarg/presentation-timer/internal/validation"
@@ -38,10 +39,109 @@ func ValidatePosition(v validation.Validator, position *int16) {
 	)
 }
 
-func ValidateMovement(v validation.Validator, move *int32) {
+func ValidateMovement(v validation.Validator, movement SectionMovement) {
+	given := 0
+	for _, ok := range []bool{
+		movement.Move != nil,
+		movement.MoveBefore != nil,
+		movement.MoveAfter != nil,
+	} {
+		if ok {
+			given++
+		}
+	}
+
+	switch {
+	case given == 0:
+		v.AddErrors("move", "move, move_before or move_after must be given")
+	case given > 1:
+		v.AddErrors("move", "only one of move, move_before or move_after can be given")
+	case movement.MoveBefore != nil:
+		v.Check("move_before", movement.MoveBefore, validation.IntCheckPositive("move_before must be positive"))
+	case movement.MoveAfter != nil:
+		v.Check("move_after", movement.MoveAfter, validation.IntCheckPositive("move_after must be positive"))
+	}
+}
+
+func ValidateSectionOrder(v validation.Validator, sections []int64) {
+	if sections == nil {
+		v.AddErrors("sections", "sections must be given")
+		return
+	}
+
+	seen := make(map[int64]bool, len(sections))
+	for i, ID := range sections {
+		key := fmt.Sprintf("sections[%d]", i)
+
+		v.Check(key, ID, validation.IntCheckPositive("id must be positive"))
+		if seen[ID] {
+			v.AddErrors(key, "id is repeated")
+		}
+		seen[ID] = true
+	}
+}
+
+func ValidateBatchOperations(v validation.Validator, operations []BatchOperation) {
+	if len(operations) == 0 {
+		v.AddErrors("operations", "operations must be given")
+		return
+	}
+	if len(operations) > BatchMaxOperations {
+		v.AddErrors(
+			"operations",
+			fmt.Sprintf("at most %d operations can be given", BatchMaxOperations),
+		)
+		return
+	}
+
+	for i, operation := range operations {
+		operationV := validation.New()
+		ValidateBatchOperation(operationV, operation)
+
+		for key, messages := range operationV.Errors() {
+			v.AddErrors(fmt.Sprintf("operations[%d].%s", i, key), messages...)
+		}
+	}
+}
+
+func ValidateBatchOperation(v validation.Validator, operation BatchOperation) {
+	v.Check(
+		"op",
+		operation.Op,
+		validation.CheckPointerNotNil("op must be given"),
+		validation.StringCheckIn(BatchOperations, "op must be one of create, update, delete or move"),
+	)
+	if !v.Valid() {
+		return
+	}
+
+	switch *operation.Op {
+	case BatchOperationCreate:
+		ValidateSectionName(v, operation.Name)
+		ValidateDuration(v, operation.Duration)
+		ValidatePosition(v, operation.Position)
+	case BatchOperationUpdate:
+		validateBatchID(v, operation.ID)
+		if operation.Name != nil {
+			ValidateSectionName(v, operation.Name)
+		}
+		if operation.Duration != nil {
+			ValidateDuration(v, operation.Duration)
+		}
+		ValidatePosition(v, operation.Position)
+	case BatchOperationDelete:
+		validateBatchID(v, operation.ID)
+	case BatchOperationMove:
+		validateBatchID(v, operation.ID)
+		ValidateMovement(v, operation.SectionMovement)
+	}
+}
+
+func validateBatchID(v validation.Validator, ID *int64) {
 	v.Check(
-		"move",
-		move,
-		validation.CheckPointerNotNil("move must be given"),
+		"id",
+		ID,
+		validation.CheckPointerNotNil("id must be given"),
+		validation.IntCheckPositive("id must be positive"),
 	)
 }
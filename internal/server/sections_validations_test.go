@@ -0,0 +1,96 @@
+package server
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+func TestValidateBatchOperations(t *testing.T) {
+	op := func(op string) *string { return &op }
+	id := func(id int64) *int64 { return &id }
+	name := func(name string) *string { return &name }
+	duration := func(d time.Duration) *time.Duration { return &d }
+	move := func(by int32) *int32 { return &by }
+
+	tests := []struct {
+		name       string
+		operations []BatchOperation
+		// errors are the keys that must fail, none when empty
+		errors []string
+	}{
+		{
+			name: "valid operations",
+			operations: []BatchOperation{
+				{Op: op(BatchOperationCreate), Name: name("Opening"), Duration: duration(time.Minute)},
+				{Op: op(BatchOperationUpdate), ID: id(3), Name: name("Closing")},
+				{Op: op(BatchOperationDelete), ID: id(4)},
+				{Op: op(BatchOperationMove), ID: id(5), SectionMovement: SectionMovement{Move: move(-1)}},
+				{Op: op(BatchOperationMove), ID: id(5), SectionMovement: SectionMovement{MoveAfter: id(3)}},
+			},
+		},
+		{
+			name:   "no operations",
+			errors: []string{"operations"},
+		},
+		{
+			name:       "too many operations",
+			operations: make([]BatchOperation, BatchMaxOperations+1),
+			errors:     []string{"operations"},
+		},
+		{
+			name: "missing and unknown op",
+			operations: []BatchOperation{
+				{ID: id(1)},
+				{Op: op("rename"), ID: id(1)},
+			},
+			errors: []string{"operations[0].op", "operations[1].op"},
+		},
+		{
+			name: "create without name or duration",
+			operations: []BatchOperation{
+				{Op: op(BatchOperationCreate)},
+			},
+			errors: []string{"operations[0].name", "operations[0].duration"},
+		},
+		{
+			name: "operations on sections without id",
+			operations: []BatchOperation{
+				{Op: op(BatchOperationDelete), ID: id(2)},
+				{Op: op(BatchOperationUpdate), Name: name("Closing")},
+				{Op: op(BatchOperationDelete), ID: id(-1)},
+			},
+			errors: []string{"operations[1].id", "operations[2].id"},
+		},
+		{
+			name: "moves without or with several movements",
+			operations: []BatchOperation{
+				{Op: op(BatchOperationMove), ID: id(1)},
+				{Op: op(BatchOperationMove), ID: id(1), SectionMovement: SectionMovement{Move: move(1), MoveBefore: id(2)}},
+				{Op: op(BatchOperationMove), ID: id(1), SectionMovement: SectionMovement{MoveBefore: id(0)}},
+			},
+			errors: []string{"operations[0].move", "operations[1].move", "operations[2].move_before"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := validation.New()
+			ValidateBatchOperations(v, tt.operations)
+
+			var got []string
+			for key := range v.Errors() {
+				got = append(got, key)
+			}
+			slices.Sort(got)
+			want := slices.Sorted(slices.Values(tt.errors))
+
+			if !slices.Equal(got, want) {
+				t.Errorf("errors on %s, want %s", strings.Join(got, ", "), strings.Join(want, ", "))
+			}
+		})
+	}
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/auth"
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+	"github.com/gorilla/websocket"
+)
+
+type ShareLinkResponse struct {
+	ID           int64      `json:"id"`
+	Presentation int64      `json:"presentation"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	// Token is only sent when the link is created
+	Token string `json:"token,omitempty"`
+}
+
+func ListShareLinksHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type output struct {
+		Data []ShareLinkResponse `json:"data"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "presentation_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionManage); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		links, err := queriesStore.GetShareLinks(ctx, presentationID)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		data := make([]ShareLinkResponse, 0, len(links))
+		for _, link := range links {
+			data = append(data, ShareLinkResponse{
+				ID:           link.ID,
+				Presentation: link.Presentation,
+				CreatedAt:    link.CreatedAt,
+				ExpiresAt:    link.ExpiresAt,
+			})
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{Data: data}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func CreateShareLinkHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		presentationID, v := helpers.ParseID(r, "presentation_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		// the body is optional, links without it never expire
+		if err := helpers.ReadJSON(r.Body, &input); err != nil && !errors.Is(err, io.EOF) {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v = validation.New()
+		ValidateExpiresAt(v, input.ExpiresAt)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		token, tokenHash, err := auth.NewToken(auth.ShareLinkPrefix)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionManage); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		link, err := queriesStore.CreateShareLink(ctx, queries.CreateShareLinkParams{
+			Presentation: presentationID,
+			TokenHash:    tokenHash,
+			ExpiresAt:    input.ExpiresAt,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, store.ErrInvalidReference):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusCreated, ShareLinkResponse{
+			ID:           link.ID,
+			Presentation: link.Presentation,
+			CreatedAt:    link.CreatedAt,
+			ExpiresAt:    link.ExpiresAt,
+			Token:        token,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func DeleteShareLinkHandler(
+	logger *slog.Logger,
+	queriesStore store.Store,
+	conf Config,
+	conns *ShareLinkConns,
+) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "presentation_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionManage); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		rows, err := queriesStore.DeleteShareLink(ctx, queries.DeleteShareLinkParams{
+			ID:           ID,
+			Presentation: presentationID,
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+		if rows == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		conns.Revoke(ID)
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// ShareLinkConns keeps the run connections opened with each share link, links
+// are only checked when a connection opens so revoking or expiring them has to
+// close the connections already open.
+type ShareLinkConns struct {
+	mu    sync.Mutex
+	conns map[int64]map[*websocket.Conn]struct{}
+}
+
+func NewShareLinkConns() *ShareLinkConns {
+	return &ShareLinkConns{
+		conns: make(map[int64]map[*websocket.Conn]struct{}),
+	}
+}
+
+// Add keeps conn until the returned function is called, closing it when link
+// is revoked or expires first.
+func (c *ShareLinkConns) Add(link auth.ShareLink, conn *websocket.Conn) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conns[link.ID] == nil {
+		c.conns[link.ID] = make(map[*websocket.Conn]struct{})
+	}
+	c.conns[link.ID][conn] = struct{}{}
+
+	stop := func() bool { return false }
+	if link.ExpiresAt != nil {
+		timer := time.AfterFunc(time.Until(*link.ExpiresAt), func() {
+			closeShareLinkConn(conn, "share link expired")
+		})
+		stop = timer.Stop
+	}
+
+	return func() {
+		stop()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		delete(c.conns[link.ID], conn)
+		if len(c.conns[link.ID]) == 0 {
+			delete(c.conns, link.ID)
+		}
+	}
+}
+
+// Revoke closes the connections opened with the share link ID.
+func (c *ShareLinkConns) Revoke(ID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for conn := range c.conns[ID] {
+		closeShareLinkConn(conn, "share link revoked")
+	}
+}
+
+// closeShareLinkConn tells the client why the connection ends before closing
+// it, the run handler cleans up once its read fails.
+func closeShareLinkConn(conn *websocket.Conn, reason string) {
+	conn.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason),
+		time.Now().Add(time.Second),
+	)
+	conn.Close()
+}
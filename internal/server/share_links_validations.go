@@ -0,0 +1,17 @@
+package server
+
+import (
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+func ValidateExpiresAt(v validation.Validator, expiresAt *time.Time) {
+	if expiresAt == nil {
+		return
+	}
+
+	if !expiresAt.After(time.Now()) {
+		v.AddErrors("expires_at", "expires_at must be in the future")
+	}
+}
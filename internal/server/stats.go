@@ -0,0 +1,24 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+)
+
+func PoolStatsHandler(logger *slog.Logger, queriesStore store.Store) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statsStore, ok := queriesStore.(store.StatsStore)
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, statsStore.PoolStats()); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
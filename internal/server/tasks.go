@@ -6,13 +6,18 @@ import (
 	"sync"
 	"time"
 
-	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/metrics"
+	"github.com/PabloVarg/presentation-timer/internal/store"
 )
 
 type TasksState struct {
-	logger               *slog.Logger
-	queriesStore         *queries.Queries
-	cleanSectionInterval time.Duration
+	logger                *slog.Logger
+	queriesStore          store.Store
+	cleanSectionInterval  time.Duration
+	cleanSessionsInterval time.Duration
+	purgeTrashInterval    time.Duration
+	trashRetention        time.Duration
+	dbTimeout             time.Duration
 }
 
 func WithSectionOrderCleanInterval(d time.Duration) func(*TasksState) {
@@ -21,15 +26,43 @@ func WithSectionOrderCleanInterval(d time.Duration) func(*TasksState) {
 	}
 }
 
+func WithSessionCleanInterval(d time.Duration) func(*TasksState) {
+	return func(tc *TasksState) {
+		tc.cleanSessionsInterval = d.Abs()
+	}
+}
+
+func WithTrashPurgeInterval(d time.Duration) func(*TasksState) {
+	return func(tc *TasksState) {
+		tc.purgeTrashInterval = d.Abs()
+	}
+}
+
+// WithTrashRetention sets how long deleted presentations and sections are
+// kept before they are purged.
+func WithTrashRetention(d time.Duration) func(*TasksState) {
+	return func(tc *TasksState) {
+		tc.trashRetention = d.Abs()
+	}
+}
+
+func WithDBTimeout(d time.Duration) func(*TasksState) {
+	return func(tc *TasksState) {
+		tc.dbTimeout = d.Abs()
+	}
+}
+
 func RunTasks(
 	ctx context.Context,
 	logger *slog.Logger,
-	queriesStore *queries.Queries,
+	queriesStore store.Store,
 	opts ...func(*TasksState),
 ) {
 	conf := TasksState{
-		logger:       logger,
-		queriesStore: queriesStore,
+		logger:         logger,
+		queriesStore:   queriesStore,
+		dbTimeout:      5 * time.Second,
+		trashRetention: 30 * 24 * time.Hour,
 	}
 
 	for _, opt := range opts {
@@ -44,6 +77,18 @@ func RunTasks(
 		conf.RunCleanSectionOrder(ctx)
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conf.RunCleanSessions(ctx)
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		conf.RunPurgeTrash(ctx)
+	}()
+
 	wg.Wait()
 }
 
@@ -65,15 +110,103 @@ func (c TasksState) RunCleanSectionOrder(ctx context.Context) {
 }
 
 func (c TasksState) cleanSectionOrder() {
-	dbCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	dbCtx, cancel := context.WithTimeout(context.Background(), c.dbTimeout)
 	defer cancel()
 
 	c.logger.Info("start clean sections order")
 
+	start := time.Now()
 	err := c.queriesStore.CleanPositions(dbCtx)
+	observeTask("clean_section_order", start, err)
 	if err != nil {
 		c.logger.Error("clean sections order", "err", err)
 	}
 
 	c.logger.Info("finish clean sections order")
 }
+
+func (c TasksState) RunCleanSessions(ctx context.Context) {
+	duration := c.cleanSessionsInterval
+	if duration.Microseconds() == 0 {
+		duration = 1 * time.Hour
+	}
+
+	t := time.NewTicker(duration)
+	for {
+		select {
+		case <-t.C:
+			c.cleanSessions()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c TasksState) cleanSessions() {
+	dbCtx, cancel := context.WithTimeout(context.Background(), c.dbTimeout)
+	defer cancel()
+
+	start := time.Now()
+	rows, err := c.queriesStore.DeleteExpiredSessions(dbCtx, start)
+	observeTask("clean_sessions", start, err)
+	if err != nil {
+		c.logger.Error("clean expired sessions", "err", err)
+		return
+	}
+
+	c.logger.Info("clean expired sessions", "deleted", rows)
+}
+
+func (c TasksState) RunPurgeTrash(ctx context.Context) {
+	duration := c.purgeTrashInterval
+	if duration.Microseconds() == 0 {
+		duration = 1 * time.Hour
+	}
+
+	t := time.NewTicker(duration)
+	for {
+		select {
+		case <-t.C:
+			c.purgeTrash()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// purgeTrash permanently deletes the presentations and sections that have
+// been in the trash for longer than the retention.
+func (c TasksState) purgeTrash() {
+	dbCtx, cancel := context.WithTimeout(context.Background(), c.dbTimeout)
+	defer cancel()
+
+	before := time.Now().Add(-c.trashRetention)
+
+	var presentations, sections int64
+	start := time.Now()
+	err := c.queriesStore.InTx(dbCtx, func(tx store.Store) error {
+		var err error
+
+		if presentations, err = tx.PurgePresentations(dbCtx, before); err != nil {
+			return err
+		}
+
+		sections, err = tx.PurgeSections(dbCtx, before)
+		return err
+	})
+	observeTask("purge_trash", start, err)
+	if err != nil {
+		c.logger.Error("purge trash", "err", err)
+		return
+	}
+
+	c.logger.Info("purge trash", "presentations", presentations, "sections", sections)
+}
+
+// observeTask records in the metrics a run of task that started at start.
+func observeTask(task string, start time.Time, err error) {
+	metrics.TaskDuration.WithLabelValues(task).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.TaskErrors.WithLabelValues(task).Inc()
+	}
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+)
+
+func ListTrashHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type output struct {
+		Presentations []queries.Presentation `json:"presentations"`
+		Sections      []queries.Section      `json:"sections"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspace, err := workspaceID(r)
+		if err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+		account := listAccount(r, false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		presentations, err := queriesStore.GetDeletedPresentations(ctx, queries.GetDeletedPresentationsParams{
+			Workspace: workspace,
+			Account:   account,
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		sections, err := queriesStore.GetDeletedSections(ctx, queries.GetDeletedSectionsParams{
+			Workspace: workspace,
+			Account:   account,
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		// an empty trash is listed as empty arrays, not null
+		if presentations == nil {
+			presentations = []queries.Presentation{}
+		}
+		if sections == nil {
+			sections = []queries.Section{}
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{
+			Presentations: presentations,
+			Sections:      sections,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func RestorePresentationHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorizeDeleted(ctx, queriesStore, r, ID, PermissionManage); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		var presentation queries.Presentation
+		err := queriesStore.InTx(ctx, func(tx store.Store) error {
+			// the update locks the presentation until the version is recorded
+			rows, err := tx.RestorePresentation(ctx, ID)
+			if err != nil {
+				return err
+			}
+			if rows == 0 {
+				return sql.ErrNoRows
+			}
+
+			if presentation, err = tx.GetPresentation(ctx, ID); err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, tx, r, auditChange{
+				Presentation: ID,
+				Action:       AuditActionRestore,
+				After:        presentation,
+			}); err != nil {
+				return err
+			}
+
+			_, err = recordVersion(ctx, tx, r, ID)
+			return err
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		w.Header().Set("ETag", helpers.ETag(presentation.Version))
+		if err := helpers.WriteJSON(w, http.StatusOK, presentation); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+// RestoreSectionHandler takes a section out of the trash, it is placed after
+// the other sections of its presentation.
+func RestoreSectionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		deleted, err := queriesStore.GetDeletedSection(ctx, ID)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := authorize(ctx, queriesStore, r, deleted.Presentation, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		var section queries.Section
+		err = queriesStore.InTx(ctx, func(tx store.Store) error {
+			// serializes position changes and versions of the presentation
+			if _, err := tx.LockPresentation(ctx, deleted.Presentation); err != nil {
+				return err
+			}
+
+			position, err := tx.MaxPosition(ctx, deleted.Presentation)
+			if err != nil {
+				return err
+			}
+
+			rows, err := tx.RestoreSection(ctx, queries.RestoreSectionParams{
+				ID:       ID,
+				Position: position + 1,
+			})
+			if err != nil {
+				return err
+			}
+			if rows == 0 {
+				return sql.ErrNoRows
+			}
+
+			if section, err = tx.GetSection(ctx, ID); err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, tx, r, auditChange{
+				Presentation: section.Presentation,
+				Section:      &ID,
+				Action:       AuditActionRestore,
+				After:        section,
+			}); err != nil {
+				return err
+			}
+
+			_, err = recordVersion(ctx, tx, r, section.Presentation)
+			return err
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		w.Header().Set("ETag", helpers.ETag(section.Version))
+		if err := helpers.WriteJSON(w, http.StatusOK, section); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
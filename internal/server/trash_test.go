@@ -0,0 +1,21 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestListTrashEmpty(t *testing.T) {
+	handler, _ := newTestServer(t)
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/trash", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /trash answered %d: %s", w.Code, w.Body)
+	}
+
+	if want := `{"presentations":[],"sections":[]}`; w.Body.String() != want {
+		t.Errorf("empty trash is %s, want %s", w.Body, want)
+	}
+}
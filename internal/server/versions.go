@@ -0,0 +1,529 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/filters"
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+const VersionsPageSize = 20
+
+// Kinds of change of a section between two versions.
+const (
+	SectionAdded   = "added"
+	SectionRemoved = "removed"
+	SectionChanged = "changed"
+)
+
+type VersionResponse struct {
+	Version int32  `json:"version"`
+	Name    string `json:"name"`
+	// Actor is the username of the account that made the change, nil when
+	// authentication was disabled
+	Actor     *string   `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type VersionDetailResponse struct {
+	VersionResponse
+	Sections []VersionSection `json:"sections"`
+}
+
+// VersionSection is a section as it was when a version was taken.
+type VersionSection struct {
+	ID       int64         `json:"id"`
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Position int16         `json:"position"`
+}
+
+type SectionDiff struct {
+	ID int64 `json:"id"`
+	// Change is one of added, removed or changed
+	Change string          `json:"change"`
+	Before *VersionSection `json:"before"`
+	After  *VersionSection `json:"after"`
+}
+
+type VersionDiffResponse struct {
+	From int32 `json:"from"`
+	To   int32 `json:"to"`
+	// Name is nil when the name of the presentation didn't change
+	Name     *AuditFieldChange `json:"name"`
+	Sections []SectionDiff     `json:"sections"`
+}
+
+// presentationSnapshot is the layout of a presentation at some point in time.
+type presentationSnapshot struct {
+	Name     string           `json:"name"`
+	Sections []VersionSection `json:"sections"`
+}
+
+func ListVersionsHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type output struct {
+		Data     []VersionResponse `json:"data"`
+		PageInfo filters.PageInfo  `json:"page_info"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		f, v := filters.FromRequest(r, conf.VersionsPageSize)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionView); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		versions, err := queriesStore.GetPresentationVersions(ctx, queries.GetPresentationVersionsParams{
+			PresentationID: presentationID,
+			QueryOffset:    f.QueryOffset(),
+			QueryLimit:     f.QueryLimit(),
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		totalRows, err := queriesStore.GetPresentationVersionsMetadata(ctx, presentationID)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		data := make([]VersionResponse, 0, len(versions))
+		for _, version := range versions {
+			data = append(data, VersionResponse{
+				Version:   version.Version,
+				Name:      version.Name,
+				Actor:     version.ActorUsername,
+				CreatedAt: version.CreatedAt,
+			})
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{
+			Data:     data,
+			PageInfo: f.PageInfo(totalRows),
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func GetVersionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		version, v := parseVersion(r)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionView); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		detail, err := getVersion(ctx, queriesStore, presentationID, version)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, detail); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func DiffVersionsHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		v = validation.New()
+		from, err := helpers.QueryInt32(r, "from", 0)
+		if err != nil {
+			v.AddErrors("from", "not a valid number")
+		}
+		to, err := helpers.QueryInt32(r, "to", 0)
+		if err != nil {
+			v.AddErrors("to", "not a valid number")
+		}
+		v.Check("from", from, validation.IntCheckPositive("from must be a version"))
+		v.Check("to", to, validation.IntCheckPositive("to must be a version"))
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionView); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		before, err := getVersion(ctx, queriesStore, presentationID, from)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		after, err := getVersion(ctx, queriesStore, presentationID, to)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, diffVersions(before, after)); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func RestoreVersionHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presentationID, v := helpers.ParseID(r, "id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		version, v := parseVersion(r)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorize(ctx, queriesStore, r, presentationID, PermissionEdit); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		var restored queries.PresentationVersion
+		err := queriesStore.InTx(ctx, func(tx store.Store) error {
+			if _, err := tx.LockPresentation(ctx, presentationID); err != nil {
+				return err
+			}
+
+			target, err := getVersion(ctx, tx, presentationID, version)
+			if err != nil {
+				return err
+			}
+
+			before, err := takeSnapshot(ctx, tx, presentationID)
+			if err != nil {
+				return err
+			}
+
+			if err := restoreSnapshot(ctx, tx, presentationID, before, presentationSnapshot{
+				Name:     target.Name,
+				Sections: target.Sections,
+			}); err != nil {
+				return err
+			}
+
+			after, err := takeSnapshot(ctx, tx, presentationID)
+			if err != nil {
+				return err
+			}
+
+			if err := recordAudit(ctx, tx, r, auditChange{
+				Presentation: presentationID,
+				Action:       AuditActionRestore,
+				Before:       before,
+				After:        after,
+			}); err != nil {
+				return err
+			}
+
+			restored, err = recordVersion(ctx, tx, r, presentationID)
+			return err
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, VersionResponse{
+			Version:   restored.Version,
+			Name:      restored.Name,
+			Actor:     accountUsername(r),
+			CreatedAt: restored.CreatedAt,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func parseVersion(r *http.Request) (int32, validation.Validator) {
+	version, v := helpers.ParseID(r, "version")
+	if !v.Valid() {
+		return 0, v
+	}
+
+	v.Check("version", version, validation.IntCheckMax(math.MaxInt32, "version does not exist"))
+	return int32(version), v
+}
+
+func getVersion(
+	ctx context.Context,
+	queriesStore store.Store,
+	presentationID int64,
+	version int32,
+) (VersionDetailResponse, error) {
+	row, err := queriesStore.GetPresentationVersion(ctx, queries.GetPresentationVersionParams{
+		PresentationID: presentationID,
+		Version:        version,
+	})
+	if err != nil {
+		return VersionDetailResponse{}, err
+	}
+
+	detail := VersionDetailResponse{
+		VersionResponse: VersionResponse{
+			Version:   row.Version,
+			Name:      row.Name,
+			Actor:     row.ActorUsername,
+			CreatedAt: row.CreatedAt,
+		},
+	}
+	if err := json.Unmarshal(row.Sections, &detail.Sections); err != nil {
+		return VersionDetailResponse{}, err
+	}
+
+	return detail, nil
+}
+
+// takeSnapshot returns the current layout of the presentation.
+func takeSnapshot(ctx context.Context, tx store.Store, presentationID int64) (presentationSnapshot, error) {
+	presentation, err := tx.GetPresentation(ctx, presentationID)
+	if err != nil {
+		return presentationSnapshot{}, err
+	}
+
+	sections, err := tx.GetSectionsByPosition(ctx, presentationID)
+	if err != nil {
+		return presentationSnapshot{}, err
+	}
+
+	snapshot := presentationSnapshot{
+		Name:     presentation.Name,
+		Sections: make([]VersionSection, 0, len(sections)),
+	}
+	for _, section := range sections {
+		snapshot.Sections = append(snapshot.Sections, VersionSection{
+			ID:       section.ID,
+			Name:     section.Name,
+			Duration: section.Duration,
+			Position: section.Position,
+		})
+	}
+
+	return snapshot, nil
+}
+
+// recordVersion stores the current layout of the presentation as its next
+// version, it must be called in the same transaction as the change. Version
+// numbers follow the last one stored, so the transaction must hold the lock of
+// the presentation for concurrent changes not to take the same number.
+func recordVersion(
+	ctx context.Context,
+	tx store.Store,
+	r *http.Request,
+	presentationID int64,
+) (queries.PresentationVersion, error) {
+	snapshot, err := takeSnapshot(ctx, tx, presentationID)
+	if err != nil {
+		return queries.PresentationVersion{}, err
+	}
+
+	sections, err := json.Marshal(snapshot.Sections)
+	if err != nil {
+		return queries.PresentationVersion{}, err
+	}
+
+	return tx.CreatePresentationVersion(ctx, queries.CreatePresentationVersionParams{
+		Presentation: presentationID,
+		Name:         snapshot.Name,
+		Sections:     sections,
+		Actor:        accountID(r),
+	})
+}
+
+// restoreSnapshot changes the presentation from current to target. Sections
+// deleted since target was taken are restored from the trash, or created again
+// with new IDs when they were purged.
+func restoreSnapshot(
+	ctx context.Context,
+	tx store.Store,
+	presentationID int64,
+	current, target presentationSnapshot,
+) error {
+	if _, err := tx.UpdatePresentation(ctx, queries.UpdatePresentationParams{
+		ID:   presentationID,
+		Name: target.Name,
+	}); err != nil {
+		return err
+	}
+
+	existing := make(map[int64]bool, len(current.Sections))
+	for _, section := range current.Sections {
+		existing[section.ID] = true
+	}
+
+	for _, section := range target.Sections {
+		if existing[section.ID] {
+			delete(existing, section.ID)
+		} else {
+			rows, err := tx.RestoreSection(ctx, queries.RestoreSectionParams{
+				ID:       section.ID,
+				Position: section.Position,
+			})
+			if err != nil {
+				return err
+			}
+
+			if rows == 0 {
+				if _, err := tx.CreateSection(ctx, queries.CreateSectionParams{
+					Presentation: presentationID,
+					Name:         section.Name,
+					Duration:     section.Duration,
+					Position:     section.Position,
+				}); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if _, err := tx.UpdateSection(ctx, queries.UpdateSectionParams{
+			ID:       section.ID,
+			Name:     section.Name,
+			Duration: section.Duration,
+			Position: section.Position,
+		}); err != nil {
+			return err
+		}
+	}
+
+	for ID := range existing {
+		if _, err := tx.DeleteSection(ctx, queries.DeleteSectionParams{ID: ID, Now: time.Now()}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// diffVersions compares two versions section by section, sections are
+// matched by their ID.
+func diffVersions(from, to VersionDetailResponse) VersionDiffResponse {
+	diff := VersionDiffResponse{
+		From:     from.Version,
+		To:       to.Version,
+		Sections: make([]SectionDiff, 0),
+	}
+	if from.Name != to.Name {
+		diff.Name = &AuditFieldChange{Before: from.Name, After: to.Name}
+	}
+
+	fromSections := make(map[int64]VersionSection, len(from.Sections))
+	for _, section := range from.Sections {
+		fromSections[section.ID] = section
+	}
+
+	for _, section := range to.Sections {
+		before, ok := fromSections[section.ID]
+		switch {
+		case !ok:
+			diff.Sections = append(diff.Sections, SectionDiff{
+				ID:     section.ID,
+				Change: SectionAdded,
+				After:  &section,
+			})
+		case before != section:
+			diff.Sections = append(diff.Sections, SectionDiff{
+				ID:     section.ID,
+				Change: SectionChanged,
+				Before: &before,
+				After:  &section,
+			})
+		}
+		delete(fromSections, section.ID)
+	}
+
+	for _, section := range from.Sections {
+		if _, ok := fromSections[section.ID]; ok {
+			diff.Sections = append(diff.Sections, SectionDiff{
+				ID:     section.ID,
+				Change: SectionRemoved,
+				Before: &section,
+			})
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,13 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/PabloVarg/presentation-timer/web"
+)
+
+// WebHandler serves the browser interface under /ui/, its pages talk to the
+// API like any other client.
+func WebHandler() http.Handler {
+	return http.StripPrefix("/ui", http.FileServerFS(web.Files()))
+}
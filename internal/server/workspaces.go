@@ -0,0 +1,271 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/PabloVarg/presentation-timer/internal/auth"
+	"github.com/PabloVarg/presentation-timer/internal/helpers"
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/PabloVarg/presentation-timer/internal/store"
+	"github.com/PabloVarg/presentation-timer/internal/validation"
+)
+
+var errNotWorkspaceAdmin = errors.New("not an admin of the workspace")
+
+func ListWorkspacesHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type output struct {
+		Data []queries.GetWorkspacesRow `json:"data"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		account, ok := auth.AccountFromContext(r.Context())
+		if !ok {
+			helpers.Unauthorized(w, "authentication required")
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		workspaces, err := queriesStore.GetWorkspaces(ctx, account.ID)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{Data: workspaces}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func CreateWorkspaceHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		Name *string `json:"name"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		account, ok := auth.AccountFromContext(r.Context())
+		if !ok {
+			helpers.Unauthorized(w, "authentication required")
+			return
+		}
+
+		if err := helpers.ReadJSON(r.Body, &input); err != nil {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v := validation.New()
+		ValidateWorkspaceName(v, input.Name)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		var workspace queries.Workspace
+		err := queriesStore.InTx(ctx, func(tx store.Store) error {
+			var err error
+
+			workspace, err = tx.CreateWorkspace(ctx, *input.Name)
+			if err != nil {
+				return err
+			}
+
+			return tx.UpsertWorkspaceMember(ctx, queries.UpsertWorkspaceMemberParams{
+				Workspace: workspace.ID,
+				Account:   account.ID,
+				Role:      WorkspaceRoleAdmin,
+			})
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusCreated, workspace); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func ListWorkspaceMembersHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type output struct {
+		Data []queries.GetWorkspaceMembersRow `json:"data"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspaceID, v := helpers.ParseID(r, "workspace_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorizeWorkspace(ctx, queriesStore, r, workspaceID, false); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		members, err := queriesStore.GetWorkspaceMembers(ctx, workspaceID)
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		if err := helpers.WriteJSON(w, http.StatusOK, output{Data: members}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+	})
+}
+
+func PutWorkspaceMemberHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	type input struct {
+		Role *string `json:"role"`
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var input input
+
+		workspaceID, v := helpers.ParseID(r, "workspace_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		if err := helpers.ReadJSON(r.Body, &input); err != nil {
+			helpers.BadRequest(w, err.Error())
+			return
+		}
+
+		v = validation.New()
+		ValidateWorkspaceRole(v, input.Role)
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorizeWorkspace(ctx, queriesStore, r, workspaceID, true); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		account, err := queriesStore.GetAccountByUsername(ctx, r.PathValue("username"))
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				v := validation.New()
+				v.AddErrors("username", "account does not exist")
+				helpers.UnprocessableContent(w, v.Errors())
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		if err := queriesStore.UpsertWorkspaceMember(ctx, queries.UpsertWorkspaceMemberParams{
+			Workspace: workspaceID,
+			Account:   account.ID,
+			Role:      *input.Role,
+		}); err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+func DeleteWorkspaceMemberHandler(logger *slog.Logger, queriesStore store.Store, conf Config) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		workspaceID, v := helpers.ParseID(r, "workspace_id")
+		if !v.Valid() {
+			helpers.UnprocessableContent(w, v.Errors())
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), conf.DBTimeout)
+		defer cancel()
+
+		if err := authorizeWorkspace(ctx, queriesStore, r, workspaceID, true); err != nil {
+			writeAuthorizeError(w, r, logger, err)
+			return
+		}
+
+		account, err := queriesStore.GetAccountByUsername(ctx, r.PathValue("username"))
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				http.NotFound(w, r)
+			default:
+				helpers.InternalError(w, logger, err)
+			}
+			return
+		}
+
+		rows, err := queriesStore.DeleteWorkspaceMember(ctx, queries.DeleteWorkspaceMemberParams{
+			Workspace: workspaceID,
+			Account:   account.ID,
+		})
+		if err != nil {
+			helpers.InternalError(w, logger, err)
+			return
+		}
+		if rows == 0 {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// authorizeWorkspace checks that the account of r is a member of the
+// workspace, and an admin when admin is set. It returns sql.ErrNoRows when the
+// workspace does not exist or the account is not a member.
+func authorizeWorkspace(
+	ctx context.Context,
+	queriesStore store.Store,
+	r *http.Request,
+	workspaceID int64,
+	admin bool,
+) error {
+	account, ok := auth.AccountFromContext(r.Context())
+	if !ok {
+		if _, err := queriesStore.GetWorkspace(ctx, workspaceID); err != nil {
+			return err
+		}
+
+		return nil
+	}
+
+	role, err := queriesStore.GetWorkspaceMember(ctx, queries.GetWorkspaceMemberParams{
+		Workspace: workspaceID,
+		Account:   account.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if admin && role != WorkspaceRoleAdmin {
+		return errNotWorkspaceAdmin
+	}
+
+	return nil
+}
@@ -0,0 +1,22 @@
+package server
+
+import "github.com/PabloVarg/presentation-timer/internal/validation"
+
+func ValidateWorkspaceName(v validation.Validator, name *string) {
+	v.Check(
+		"name",
+		name,
+		validation.CheckPointerNotNil("name must be given"),
+		validation.StringCheckNotEmpty("name can't be empty"),
+		validation.StringCheckMaxLen(50, "name must be at most 50 characters"),
+	)
+}
+
+func ValidateWorkspaceRole(v validation.Validator, role *string) {
+	v.Check(
+		"role",
+		role,
+		validation.CheckPointerNotNil("role must be given"),
+		validation.StringCheckIn(WorkspaceRoles, "role must be one of admin or member"),
+	)
+}
@@ -0,0 +1,1407 @@
+package store
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"database/sql"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+)
+
+// Memory is a Store that keeps everything in memory, it is meant for tests and
+// demos, data is lost when the process exits.
+type Memory struct {
+	*memoryData
+	// inTx is set on the Memory given to InTx callbacks, which already hold
+	// the lock.
+	inTx bool
+}
+
+type memoryData struct {
+	mu sync.RWMutex
+	memoryState
+}
+
+type memoryState struct {
+	presentationID int64
+	sectionID      int64
+	accountID      int64
+	apiKeyID       int64
+	shareLinkID    int64
+	workspaceID    int64
+	auditEntryID   int64
+	presentations  map[int64]queries.Presentation
+	sections       map[int64]queries.Section
+	accounts       map[int64]queries.Account
+	// sessions are keyed by their token hash
+	sessions   map[string]queries.Session
+	apiKeys    map[int64]queries.ApiKey
+	grants     map[grantKey]string
+	shareLinks map[int64]queries.ShareLink
+	workspaces map[int64]queries.Workspace
+	members    map[memberKey]string
+	// auditEntries are kept in insertion order
+	auditEntries []queries.AuditEntry
+	// versions are keyed by presentation, in version order
+	versions map[int64][]queries.PresentationVersion
+}
+
+type grantKey struct {
+	presentation int64
+	account      int64
+}
+
+type memberKey struct {
+	workspace int64
+	account   int64
+}
+
+// NewMemory returns an empty Memory with the default workspace the migrations
+// create.
+func NewMemory() *Memory {
+	return &Memory{
+		memoryData: &memoryData{
+			memoryState: memoryState{
+				workspaceID: DefaultWorkspace,
+				workspaces: map[int64]queries.Workspace{
+					DefaultWorkspace: {ID: DefaultWorkspace, Name: "Default", CreatedAt: time.Now()},
+				},
+				members:       make(map[memberKey]string),
+				versions:      make(map[int64][]queries.PresentationVersion),
+				presentations: make(map[int64]queries.Presentation),
+				sections:      make(map[int64]queries.Section),
+				accounts:      make(map[int64]queries.Account),
+				sessions:      make(map[string]queries.Session),
+				apiKeys:       make(map[int64]queries.ApiKey),
+				grants:        make(map[grantKey]string),
+				shareLinks:    make(map[int64]queries.ShareLink),
+			},
+		},
+	}
+}
+
+// clone returns a copy of the state that is not affected by later writes.
+func (s memoryState) clone() memoryState {
+	s.presentations = maps.Clone(s.presentations)
+	s.sections = maps.Clone(s.sections)
+	s.accounts = maps.Clone(s.accounts)
+	s.sessions = maps.Clone(s.sessions)
+	s.apiKeys = maps.Clone(s.apiKeys)
+	s.grants = maps.Clone(s.grants)
+	s.shareLinks = maps.Clone(s.shareLinks)
+	s.workspaces = maps.Clone(s.workspaces)
+	s.members = maps.Clone(s.members)
+	s.auditEntries = slices.Clone(s.auditEntries)
+	s.versions = maps.Clone(s.versions)
+
+	return s
+}
+
+// InTx holds the lock while fn runs, restoring the previous state if it
+// fails.
+func (m *Memory) InTx(_ context.Context, fn func(Store) error) error {
+	if m.inTx {
+		return fn(m)
+	}
+
+	defer m.lock()()
+
+	snapshot := m.memoryState.clone()
+	if err := fn(&Memory{memoryData: m.memoryData, inTx: true}); err != nil {
+		m.memoryState = snapshot
+		return err
+	}
+
+	return nil
+}
+
+func (m *Memory) GetPresentations(
+	_ context.Context,
+	arg queries.GetPresentationsParams,
+) ([]queries.GetPresentationsRow, error) {
+	defer m.rlock()()
+
+	rows := m.filterPresentations(queries.GetPresentationsMetadataParams{
+		Workspace:    arg.Workspace,
+		Account:      arg.Account,
+		SharedWithMe: arg.SharedWithMe,
+		NameContains: arg.NameContains,
+		CreatedAfter: arg.CreatedAfter,
+		Search:       arg.Search,
+		MinDuration:  arg.MinDuration,
+		MaxDuration:  arg.MaxDuration,
+	})
+
+	compare := compareRows([]sortField{
+		{arg.SortBy1, arg.Direction1},
+		{arg.SortBy2, arg.Direction2},
+		{arg.SortBy3, arg.Direction3},
+	}, map[string]func(a, b queries.GetPresentationsRow) int{
+		"name":       func(a, b queries.GetPresentationsRow) int { return cmp.Compare(a.Name, b.Name) },
+		"duration":   func(a, b queries.GetPresentationsRow) int { return cmp.Compare(a.Duration, b.Duration) },
+		"created_at": func(a, b queries.GetPresentationsRow) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	}, func(a, b queries.GetPresentationsRow) int {
+		return direction(arg.IDDirection, cmp.Compare(a.ID, b.ID))
+	})
+	slices.SortFunc(rows, compare)
+
+	if arg.CursorID != nil {
+		cursor := queries.GetPresentationsRow{
+			ID:        *arg.CursorID,
+			Name:      arg.CursorName,
+			Duration:  arg.CursorDuration,
+			CreatedAt: arg.CursorCreatedAt,
+		}
+		rows = slices.DeleteFunc(rows, func(row queries.GetPresentationsRow) bool {
+			return compare(row, cursor) <= 0
+		})
+	}
+
+	return paginate(rows, arg.QueryOffset, arg.QueryLimit), nil
+}
+
+func (m *Memory) GetPresentationsMetadata(
+	_ context.Context,
+	arg queries.GetPresentationsMetadataParams,
+) (int64, error) {
+	defer m.rlock()()
+
+	return int64(len(m.filterPresentations(arg))), nil
+}
+
+// filterPresentations returns the presentations listed with the filters of
+// arg, with the total duration of their sections. Callers must hold the lock.
+func (m *Memory) filterPresentations(arg queries.GetPresentationsMetadataParams) []queries.GetPresentationsRow {
+	rows := make([]queries.GetPresentationsRow, 0, len(m.presentations))
+	for _, presentation := range m.presentations {
+		if presentation.DeletedAt != nil || presentation.Workspace != arg.Workspace ||
+			!m.visible(presentation, arg.Account, arg.SharedWithMe) {
+			continue
+		}
+
+		sections := m.sectionsOf(presentation.ID)
+		var duration time.Duration
+		for _, section := range sections {
+			duration += section.Duration
+		}
+
+		if !matchesFilters(
+			presentation.Name, duration, presentation.CreatedAt,
+			arg.NameContains, arg.MinDuration, arg.MaxDuration, arg.CreatedAfter,
+		) {
+			continue
+		}
+		if arg.Search != nil && !matchesSearch(presentation.Name, *arg.Search) &&
+			!slices.ContainsFunc(sections, func(section queries.Section) bool {
+				return matchesSearch(section.Name, *arg.Search)
+			}) {
+			continue
+		}
+
+		rows = append(rows, queries.GetPresentationsRow{
+			ID:        presentation.ID,
+			Name:      presentation.Name,
+			Owner:     presentation.Owner,
+			Workspace: presentation.Workspace,
+			DeletedAt: presentation.DeletedAt,
+			Version:   presentation.Version,
+			CreatedAt: presentation.CreatedAt,
+			Duration:  duration,
+		})
+	}
+
+	return rows
+}
+
+func (m *Memory) GetPresentation(_ context.Context, id int64) (queries.Presentation, error) {
+	defer m.rlock()()
+
+	presentation, ok := m.presentations[id]
+	if !ok || presentation.DeletedAt != nil {
+		return queries.Presentation{}, sql.ErrNoRows
+	}
+
+	return presentation, nil
+}
+
+func (m *Memory) LockPresentation(ctx context.Context, id int64) (queries.Presentation, error) {
+	return m.GetPresentation(ctx, id)
+}
+
+func (m *Memory) CreatePresentation(
+	_ context.Context,
+	arg queries.CreatePresentationParams,
+) (queries.Presentation, error) {
+	defer m.lock()()
+
+	if _, ok := m.workspaces[arg.Workspace]; !ok {
+		return queries.Presentation{}, ErrInvalidReference
+	}
+	if arg.Owner != nil {
+		if _, ok := m.accounts[*arg.Owner]; !ok {
+			return queries.Presentation{}, ErrInvalidReference
+		}
+	}
+
+	m.presentationID++
+	presentation := queries.Presentation{
+		ID:        m.presentationID,
+		Name:      arg.Name,
+		Owner:     arg.Owner,
+		Workspace: arg.Workspace,
+		Version:   1,
+		CreatedAt: time.Now(),
+	}
+	m.presentations[presentation.ID] = presentation
+
+	return presentation, nil
+}
+
+func (m *Memory) UpdatePresentation(_ context.Context, arg queries.UpdatePresentationParams) (int64, error) {
+	defer m.lock()()
+
+	presentation, ok := m.presentations[arg.ID]
+	if !ok || presentation.DeletedAt != nil {
+		return 0, nil
+	}
+
+	presentation.Name = arg.Name
+	presentation.Version++
+	m.presentations[arg.ID] = presentation
+
+	return 1, nil
+}
+
+func (m *Memory) PatchPresentation(_ context.Context, arg queries.PatchPresentationParams) (int64, error) {
+	defer m.lock()()
+
+	presentation, ok := m.presentations[arg.ID]
+	if !ok || presentation.DeletedAt != nil {
+		return 0, nil
+	}
+
+	if arg.Name != nil {
+		presentation.Name = *arg.Name
+	}
+	presentation.Version++
+	m.presentations[arg.ID] = presentation
+
+	return 1, nil
+}
+
+func (m *Memory) DeletePresentation(_ context.Context, arg queries.DeletePresentationParams) (int64, error) {
+	defer m.lock()()
+
+	presentation, ok := m.presentations[arg.ID]
+	if !ok || presentation.DeletedAt != nil {
+		return 0, nil
+	}
+
+	presentation.DeletedAt = &arg.Now
+	presentation.Version++
+	m.presentations[arg.ID] = presentation
+
+	return 1, nil
+}
+
+func (m *Memory) GetPresentationAccess(
+	_ context.Context,
+	arg queries.GetPresentationAccessParams,
+) (queries.GetPresentationAccessRow, error) {
+	defer m.rlock()()
+
+	presentation, ok := m.presentations[arg.PresentationID]
+	if !ok || presentation.Workspace != arg.Workspace {
+		return queries.GetPresentationAccessRow{}, sql.ErrNoRows
+	}
+
+	access := queries.GetPresentationAccessRow{Owner: presentation.Owner, DeletedAt: presentation.DeletedAt}
+	if role, ok := m.grants[grantKey{arg.PresentationID, arg.Account}]; ok {
+		access.Role = &role
+	}
+
+	return access, nil
+}
+
+func (m *Memory) GetGrants(_ context.Context, presentationID int64) ([]queries.GetGrantsRow, error) {
+	defer m.rlock()()
+
+	var grants []queries.GetGrantsRow
+	for key, role := range m.grants {
+		if key.presentation == presentationID {
+			grants = append(grants, queries.GetGrantsRow{
+				Account:  key.account,
+				Username: m.accounts[key.account].Username,
+				Role:     role,
+			})
+		}
+	}
+	slices.SortFunc(grants, func(a, b queries.GetGrantsRow) int {
+		return cmp.Compare(a.Username, b.Username)
+	})
+
+	return grants, nil
+}
+
+func (m *Memory) UpsertGrant(_ context.Context, arg queries.UpsertGrantParams) error {
+	defer m.lock()()
+
+	if _, ok := m.presentations[arg.Presentation]; !ok {
+		return ErrInvalidReference
+	}
+	if _, ok := m.accounts[arg.Account]; !ok {
+		return ErrInvalidReference
+	}
+	m.grants[grantKey{arg.Presentation, arg.Account}] = arg.Role
+
+	return nil
+}
+
+func (m *Memory) DeleteGrant(_ context.Context, arg queries.DeleteGrantParams) (int64, error) {
+	defer m.lock()()
+
+	key := grantKey{arg.Presentation, arg.Account}
+	if _, ok := m.grants[key]; !ok {
+		return 0, nil
+	}
+	delete(m.grants, key)
+
+	return 1, nil
+}
+
+func (m *Memory) GetSections(_ context.Context, arg queries.GetSectionsParams) ([]queries.Section, error) {
+	defer m.rlock()()
+
+	sections := m.filterSections(queries.GetSectionsMetadataParams{
+		PresentationID: arg.PresentationID,
+		NameContains:   arg.NameContains,
+		MinDuration:    arg.MinDuration,
+		MaxDuration:    arg.MaxDuration,
+		CreatedAfter:   arg.CreatedAfter,
+		Search:         arg.Search,
+	})
+
+	compare := compareRows([]sortField{
+		{arg.SortBy1, arg.Direction1},
+		{arg.SortBy2, arg.Direction2},
+		{arg.SortBy3, arg.Direction3},
+	}, map[string]func(a, b queries.Section) int{
+		"name":       func(a, b queries.Section) int { return cmp.Compare(a.Name, b.Name) },
+		"duration":   func(a, b queries.Section) int { return cmp.Compare(a.Duration, b.Duration) },
+		"position":   func(a, b queries.Section) int { return cmp.Compare(a.Position, b.Position) },
+		"created_at": func(a, b queries.Section) int { return a.CreatedAt.Compare(b.CreatedAt) },
+	}, func(a, b queries.Section) int {
+		return direction(arg.IDDirection, cmp.Compare(a.ID, b.ID))
+	})
+	slices.SortFunc(sections, compare)
+
+	if arg.CursorID != nil {
+		cursor := queries.Section{
+			ID:        *arg.CursorID,
+			Name:      arg.CursorName,
+			Duration:  arg.CursorDuration,
+			Position:  arg.CursorPosition,
+			CreatedAt: arg.CursorCreatedAt,
+		}
+		sections = slices.DeleteFunc(sections, func(section queries.Section) bool {
+			return compare(section, cursor) <= 0
+		})
+	}
+
+	return paginate(sections, arg.QueryOffset, arg.QueryLimit), nil
+}
+
+func (m *Memory) GetSectionsMetadata(_ context.Context, arg queries.GetSectionsMetadataParams) (int64, error) {
+	defer m.rlock()()
+
+	return int64(len(m.filterSections(arg))), nil
+}
+
+// filterSections returns the sections of a presentation listed with the
+// filters of arg. Callers must hold the lock.
+func (m *Memory) filterSections(arg queries.GetSectionsMetadataParams) []queries.Section {
+	var sections []queries.Section
+	for _, section := range m.sectionsOf(arg.PresentationID) {
+		if !matchesFilters(
+			section.Name, section.Duration, section.CreatedAt,
+			arg.NameContains, arg.MinDuration, arg.MaxDuration, arg.CreatedAfter,
+		) {
+			continue
+		}
+		if arg.Search != nil && !matchesSearch(section.Name, *arg.Search) {
+			continue
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+func (m *Memory) GetSection(_ context.Context, id int64) (queries.Section, error) {
+	defer m.rlock()()
+
+	section, ok := m.sections[id]
+	if !ok || section.DeletedAt != nil {
+		return queries.Section{}, sql.ErrNoRows
+	}
+
+	return section, nil
+}
+
+func (m *Memory) LockSection(ctx context.Context, id int64) (queries.Section, error) {
+	return m.GetSection(ctx, id)
+}
+
+func (m *Memory) CreateSection(_ context.Context, arg queries.CreateSectionParams) (queries.Section, error) {
+	defer m.lock()()
+
+	if _, ok := m.presentations[arg.Presentation]; !ok {
+		return queries.Section{}, ErrInvalidReference
+	}
+
+	m.sectionID++
+	section := queries.Section{
+		ID:           m.sectionID,
+		Presentation: arg.Presentation,
+		Name:         arg.Name,
+		Duration:     arg.Duration,
+		Position:     arg.Position,
+		Version:      1,
+		CreatedAt:    time.Now(),
+	}
+	m.sections[section.ID] = section
+
+	return section, nil
+}
+
+func (m *Memory) UpdateSection(_ context.Context, arg queries.UpdateSectionParams) (int64, error) {
+	defer m.lock()()
+
+	section, ok := m.sections[arg.ID]
+	if !ok || section.DeletedAt != nil {
+		return 0, nil
+	}
+
+	section.Name = arg.Name
+	section.Duration = arg.Duration
+	section.Position = arg.Position
+	section.Version++
+	m.sections[arg.ID] = section
+
+	return 1, nil
+}
+
+func (m *Memory) PatchSection(_ context.Context, arg queries.PatchSectionParams) (int64, error) {
+	defer m.lock()()
+
+	section, ok := m.sections[arg.ID]
+	if !ok || section.DeletedAt != nil {
+		return 0, nil
+	}
+
+	if arg.Name != nil {
+		section.Name = *arg.Name
+	}
+	if arg.Duration != nil {
+		section.Duration = *arg.Duration
+	}
+	if arg.Position != nil {
+		section.Position = *arg.Position
+	}
+	section.Version++
+	m.sections[arg.ID] = section
+
+	return 1, nil
+}
+
+func (m *Memory) DeleteSection(_ context.Context, arg queries.DeleteSectionParams) (int64, error) {
+	defer m.lock()()
+
+	section, ok := m.sections[arg.ID]
+	if !ok || section.DeletedAt != nil {
+		return 0, nil
+	}
+
+	section.DeletedAt = &arg.Now
+	section.Version++
+	m.sections[arg.ID] = section
+
+	return 1, nil
+}
+
+func (m *Memory) MaxPosition(_ context.Context, presentationID int64) (int16, error) {
+	defer m.rlock()()
+
+	var position int16
+	for _, section := range m.sectionsOf(presentationID) {
+		position = max(position, section.Position)
+	}
+
+	return position, nil
+}
+
+func (m *Memory) CleanPositions(_ context.Context) error {
+	defer m.lock()()
+
+	for id, presentation := range m.presentations {
+		if presentation.DeletedAt == nil {
+			m.cleanPositions(id)
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) CleanPositionsBySectionGroup(_ context.Context, id int64) error {
+	defer m.lock()()
+
+	section, ok := m.sections[id]
+	if !ok {
+		return nil
+	}
+	m.cleanPositions(section.Presentation)
+
+	return nil
+}
+
+func (m *Memory) SetSectionPosition(_ context.Context, arg queries.SetSectionPositionParams) error {
+	defer m.lock()()
+
+	section, ok := m.sections[arg.ID]
+	if !ok || section.DeletedAt != nil || section.Position == arg.Position {
+		return nil
+	}
+
+	section.Position = arg.Position
+	section.Version++
+	m.sections[arg.ID] = section
+
+	return nil
+}
+
+func (m *Memory) GetSectionsByPosition(_ context.Context, presentationID int64) ([]queries.Section, error) {
+	defer m.rlock()()
+
+	sections := m.sectionsOf(presentationID)
+	sortByPosition(sections)
+
+	return sections, nil
+}
+
+func (m *Memory) GetSectionsOfPresentations(_ context.Context, presentationIDs []int64) ([]queries.Section, error) {
+	defer m.rlock()()
+
+	var sections []queries.Section
+	for _, ID := range slices.Compact(slices.Sorted(slices.Values(presentationIDs))) {
+		of := m.sectionsOf(ID)
+		sortByPosition(of)
+		sections = append(sections, of...)
+	}
+
+	return sections, nil
+}
+
+func (m *Memory) RestorePresentation(_ context.Context, id int64) (int64, error) {
+	defer m.lock()()
+
+	presentation, ok := m.presentations[id]
+	if !ok || presentation.DeletedAt == nil {
+		return 0, nil
+	}
+
+	presentation.DeletedAt = nil
+	presentation.Version++
+	m.presentations[id] = presentation
+
+	return 1, nil
+}
+
+func (m *Memory) GetDeletedPresentations(
+	_ context.Context,
+	arg queries.GetDeletedPresentationsParams,
+) ([]queries.Presentation, error) {
+	defer m.rlock()()
+
+	var presentations []queries.Presentation
+	for _, presentation := range m.presentations {
+		if presentation.DeletedAt == nil || presentation.Workspace != arg.Workspace {
+			continue
+		}
+		if arg.Account != nil && (presentation.Owner == nil || *presentation.Owner != *arg.Account) {
+			continue
+		}
+
+		presentations = append(presentations, presentation)
+	}
+	slices.SortFunc(presentations, func(a, b queries.Presentation) int {
+		return cmp.Or(b.DeletedAt.Compare(*a.DeletedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return presentations, nil
+}
+
+func (m *Memory) PurgePresentations(_ context.Context, before time.Time) (int64, error) {
+	defer m.lock()()
+
+	var count int64
+	for id, presentation := range m.presentations {
+		if presentation.DeletedAt == nil || !presentation.DeletedAt.Before(before) {
+			continue
+		}
+
+		delete(m.presentations, id)
+		maps.DeleteFunc(m.sections, func(_ int64, section queries.Section) bool {
+			return section.Presentation == id
+		})
+		maps.DeleteFunc(m.grants, func(key grantKey, _ string) bool {
+			return key.presentation == id
+		})
+		maps.DeleteFunc(m.shareLinks, func(_ int64, link queries.ShareLink) bool {
+			return link.Presentation == id
+		})
+		delete(m.versions, id)
+		count++
+	}
+
+	return count, nil
+}
+
+func (m *Memory) GetDeletedSection(_ context.Context, id int64) (queries.Section, error) {
+	defer m.rlock()()
+
+	section, ok := m.sections[id]
+	if !ok || section.DeletedAt == nil {
+		return queries.Section{}, sql.ErrNoRows
+	}
+
+	return section, nil
+}
+
+func (m *Memory) RestoreSection(_ context.Context, arg queries.RestoreSectionParams) (int64, error) {
+	defer m.lock()()
+
+	section, ok := m.sections[arg.ID]
+	if !ok || section.DeletedAt == nil {
+		return 0, nil
+	}
+
+	section.DeletedAt = nil
+	section.Position = arg.Position
+	section.Version++
+	m.sections[arg.ID] = section
+
+	return 1, nil
+}
+
+func (m *Memory) GetDeletedSections(
+	_ context.Context,
+	arg queries.GetDeletedSectionsParams,
+) ([]queries.Section, error) {
+	defer m.rlock()()
+
+	var sections []queries.Section
+	for _, section := range m.sections {
+		presentation := m.presentations[section.Presentation]
+		if section.DeletedAt == nil || presentation.DeletedAt != nil || presentation.Workspace != arg.Workspace {
+			continue
+		}
+		if !m.visible(presentation, arg.Account, false) {
+			continue
+		}
+
+		sections = append(sections, section)
+	}
+	slices.SortFunc(sections, func(a, b queries.Section) int {
+		return cmp.Or(b.DeletedAt.Compare(*a.DeletedAt), cmp.Compare(b.ID, a.ID))
+	})
+
+	return sections, nil
+}
+
+func (m *Memory) PurgeSections(_ context.Context, before time.Time) (int64, error) {
+	defer m.lock()()
+
+	count := len(m.sections)
+	maps.DeleteFunc(m.sections, func(_ int64, section queries.Section) bool {
+		return section.DeletedAt != nil && section.DeletedAt.Before(before)
+	})
+
+	return int64(count - len(m.sections)), nil
+}
+
+func (m *Memory) CreateAccount(_ context.Context, arg queries.CreateAccountParams) (queries.Account, error) {
+	defer m.lock()()
+
+	for _, account := range m.accounts {
+		if account.Username == arg.Username {
+			return queries.Account{}, ErrConflict
+		}
+	}
+
+	m.accountID++
+	account := queries.Account{
+		ID:           m.accountID,
+		Username:     arg.Username,
+		PasswordHash: arg.PasswordHash,
+		CreatedAt:    time.Now(),
+	}
+	m.accounts[account.ID] = account
+
+	return account, nil
+}
+
+func (m *Memory) GetAccount(_ context.Context, id int64) (queries.Account, error) {
+	defer m.rlock()()
+
+	account, ok := m.accounts[id]
+	if !ok {
+		return queries.Account{}, sql.ErrNoRows
+	}
+
+	return account, nil
+}
+
+func (m *Memory) GetAccountByUsername(_ context.Context, username string) (queries.Account, error) {
+	defer m.rlock()()
+
+	for _, account := range m.accounts {
+		if account.Username == username {
+			return account, nil
+		}
+	}
+
+	return queries.Account{}, sql.ErrNoRows
+}
+
+func (m *Memory) CountAccounts(_ context.Context) (int64, error) {
+	defer m.rlock()()
+
+	return int64(len(m.accounts)), nil
+}
+
+// LockAccounts has nothing to do, transactions hold the lock of the store.
+func (m *Memory) LockAccounts(_ context.Context) error {
+	return nil
+}
+
+func (m *Memory) CreateSession(_ context.Context, arg queries.CreateSessionParams) error {
+	defer m.lock()()
+
+	if _, ok := m.accounts[arg.Account]; !ok {
+		return ErrInvalidReference
+	}
+
+	m.sessions[string(arg.TokenHash)] = queries.Session{
+		TokenHash: arg.TokenHash,
+		Account:   arg.Account,
+		CreatedAt: time.Now(),
+		ExpiresAt: arg.ExpiresAt,
+	}
+
+	return nil
+}
+
+func (m *Memory) GetSessionAccount(
+	_ context.Context,
+	arg queries.GetSessionAccountParams,
+) (queries.Account, error) {
+	defer m.rlock()()
+
+	session, ok := m.sessions[string(arg.TokenHash)]
+	if !ok || !session.ExpiresAt.After(arg.Now) {
+		return queries.Account{}, sql.ErrNoRows
+	}
+
+	return m.accounts[session.Account], nil
+}
+
+func (m *Memory) DeleteSession(_ context.Context, tokenHash []byte) (int64, error) {
+	defer m.lock()()
+
+	if _, ok := m.sessions[string(tokenHash)]; !ok {
+		return 0, nil
+	}
+	delete(m.sessions, string(tokenHash))
+
+	return 1, nil
+}
+
+func (m *Memory) DeleteExpiredSessions(_ context.Context, now time.Time) (int64, error) {
+	defer m.lock()()
+
+	before := len(m.sessions)
+	maps.DeleteFunc(m.sessions, func(_ string, session queries.Session) bool {
+		return !session.ExpiresAt.After(now)
+	})
+
+	return int64(before - len(m.sessions)), nil
+}
+
+func (m *Memory) CreateAPIKey(_ context.Context, arg queries.CreateAPIKeyParams) (queries.ApiKey, error) {
+	defer m.lock()()
+
+	if _, ok := m.accounts[arg.Account]; !ok {
+		return queries.ApiKey{}, ErrInvalidReference
+	}
+	if _, ok := m.workspaces[arg.Workspace]; !ok {
+		return queries.ApiKey{}, ErrInvalidReference
+	}
+
+	m.apiKeyID++
+	key := queries.ApiKey{
+		ID:        m.apiKeyID,
+		Account:   arg.Account,
+		Name:      arg.Name,
+		KeyHash:   arg.KeyHash,
+		CreatedAt: time.Now(),
+		Workspace: arg.Workspace,
+	}
+	m.apiKeys[key.ID] = key
+
+	return key, nil
+}
+
+func (m *Memory) GetAPIKeys(_ context.Context, arg queries.GetAPIKeysParams) ([]queries.ApiKey, error) {
+	defer m.rlock()()
+
+	var keys []queries.ApiKey
+	for _, key := range m.apiKeys {
+		if key.Account == arg.Account && key.Workspace == arg.Workspace {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b queries.ApiKey) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return keys, nil
+}
+
+func (m *Memory) DeleteAPIKey(_ context.Context, arg queries.DeleteAPIKeyParams) (int64, error) {
+	defer m.lock()()
+
+	key, ok := m.apiKeys[arg.ID]
+	if !ok || key.Account != arg.Account {
+		return 0, nil
+	}
+	delete(m.apiKeys, arg.ID)
+
+	return 1, nil
+}
+
+func (m *Memory) UseAPIKey(_ context.Context, arg queries.UseAPIKeyParams) (queries.UseAPIKeyRow, error) {
+	defer m.lock()()
+
+	for id, key := range m.apiKeys {
+		if bytes.Equal(key.KeyHash, arg.KeyHash) {
+			key.LastUsedAt = &arg.Now
+			m.apiKeys[id] = key
+
+			return queries.UseAPIKeyRow{
+				Account:   key.Account,
+				Workspace: key.Workspace,
+			}, nil
+		}
+	}
+
+	return queries.UseAPIKeyRow{}, sql.ErrNoRows
+}
+
+func (m *Memory) CreateShareLink(_ context.Context, arg queries.CreateShareLinkParams) (queries.ShareLink, error) {
+	defer m.lock()()
+
+	if _, ok := m.presentations[arg.Presentation]; !ok {
+		return queries.ShareLink{}, ErrInvalidReference
+	}
+
+	m.shareLinkID++
+	link := queries.ShareLink{
+		ID:           m.shareLinkID,
+		Presentation: arg.Presentation,
+		TokenHash:    arg.TokenHash,
+		CreatedAt:    time.Now(),
+		ExpiresAt:    arg.ExpiresAt,
+	}
+	m.shareLinks[link.ID] = link
+
+	return link, nil
+}
+
+func (m *Memory) GetShareLinks(_ context.Context, presentationID int64) ([]queries.ShareLink, error) {
+	defer m.rlock()()
+
+	var links []queries.ShareLink
+	for _, link := range m.shareLinks {
+		if link.Presentation == presentationID {
+			links = append(links, link)
+		}
+	}
+	slices.SortFunc(links, func(a, b queries.ShareLink) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return links, nil
+}
+
+func (m *Memory) DeleteShareLink(_ context.Context, arg queries.DeleteShareLinkParams) (int64, error) {
+	defer m.lock()()
+
+	link, ok := m.shareLinks[arg.ID]
+	if !ok || link.Presentation != arg.Presentation {
+		return 0, nil
+	}
+	delete(m.shareLinks, arg.ID)
+
+	return 1, nil
+}
+
+func (m *Memory) GetValidShareLink(
+	_ context.Context,
+	arg queries.GetValidShareLinkParams,
+) (queries.ShareLink, error) {
+	defer m.rlock()()
+
+	for _, link := range m.shareLinks {
+		if !bytes.Equal(link.TokenHash, arg.TokenHash) {
+			continue
+		}
+		if link.ExpiresAt != nil && !link.ExpiresAt.After(arg.Now) {
+			break
+		}
+		if m.presentations[link.Presentation].DeletedAt != nil {
+			break
+		}
+
+		return link, nil
+	}
+
+	return queries.ShareLink{}, sql.ErrNoRows
+}
+
+func (m *Memory) CreateWorkspace(_ context.Context, name string) (queries.Workspace, error) {
+	defer m.lock()()
+
+	m.workspaceID++
+	workspace := queries.Workspace{
+		ID:        m.workspaceID,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	m.workspaces[workspace.ID] = workspace
+
+	return workspace, nil
+}
+
+func (m *Memory) GetWorkspace(_ context.Context, id int64) (queries.Workspace, error) {
+	defer m.rlock()()
+
+	workspace, ok := m.workspaces[id]
+	if !ok {
+		return queries.Workspace{}, sql.ErrNoRows
+	}
+
+	return workspace, nil
+}
+
+func (m *Memory) GetWorkspaces(_ context.Context, account int64) ([]queries.GetWorkspacesRow, error) {
+	defer m.rlock()()
+
+	var workspaces []queries.GetWorkspacesRow
+	for key, role := range m.members {
+		if key.account == account {
+			workspace := m.workspaces[key.workspace]
+			workspaces = append(workspaces, queries.GetWorkspacesRow{
+				ID:        workspace.ID,
+				Name:      workspace.Name,
+				CreatedAt: workspace.CreatedAt,
+				Role:      role,
+			})
+		}
+	}
+	slices.SortFunc(workspaces, func(a, b queries.GetWorkspacesRow) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return workspaces, nil
+}
+
+func (m *Memory) GetWorkspaceMember(_ context.Context, arg queries.GetWorkspaceMemberParams) (string, error) {
+	defer m.rlock()()
+
+	role, ok := m.members[memberKey{arg.Workspace, arg.Account}]
+	if !ok {
+		return "", sql.ErrNoRows
+	}
+
+	return role, nil
+}
+
+func (m *Memory) GetDefaultWorkspace(_ context.Context, account int64) (queries.GetDefaultWorkspaceRow, error) {
+	defer m.rlock()()
+
+	var (
+		workspace queries.GetDefaultWorkspaceRow
+		found     bool
+	)
+	for key, role := range m.members {
+		if key.account == account && (!found || key.workspace < workspace.Workspace) {
+			workspace = queries.GetDefaultWorkspaceRow{Workspace: key.workspace, Role: role}
+			found = true
+		}
+	}
+	if !found {
+		return queries.GetDefaultWorkspaceRow{}, sql.ErrNoRows
+	}
+
+	return workspace, nil
+}
+
+func (m *Memory) GetWorkspaceMembers(_ context.Context, workspace int64) ([]queries.GetWorkspaceMembersRow, error) {
+	defer m.rlock()()
+
+	var members []queries.GetWorkspaceMembersRow
+	for key, role := range m.members {
+		if key.workspace == workspace {
+			members = append(members, queries.GetWorkspaceMembersRow{
+				Account:  key.account,
+				Username: m.accounts[key.account].Username,
+				Role:     role,
+			})
+		}
+	}
+	slices.SortFunc(members, func(a, b queries.GetWorkspaceMembersRow) int {
+		return cmp.Compare(a.Username, b.Username)
+	})
+
+	return members, nil
+}
+
+func (m *Memory) UpsertWorkspaceMember(_ context.Context, arg queries.UpsertWorkspaceMemberParams) error {
+	defer m.lock()()
+
+	if _, ok := m.workspaces[arg.Workspace]; !ok {
+		return ErrInvalidReference
+	}
+	if _, ok := m.accounts[arg.Account]; !ok {
+		return ErrInvalidReference
+	}
+	m.members[memberKey{arg.Workspace, arg.Account}] = arg.Role
+
+	return nil
+}
+
+func (m *Memory) DeleteWorkspaceMember(_ context.Context, arg queries.DeleteWorkspaceMemberParams) (int64, error) {
+	defer m.lock()()
+
+	key := memberKey{arg.Workspace, arg.Account}
+	if _, ok := m.members[key]; !ok {
+		return 0, nil
+	}
+	delete(m.members, key)
+
+	return 1, nil
+}
+
+func (m *Memory) CreateAuditEntry(_ context.Context, arg queries.CreateAuditEntryParams) error {
+	defer m.lock()()
+
+	if arg.Actor != nil {
+		if _, ok := m.accounts[*arg.Actor]; !ok {
+			return ErrInvalidReference
+		}
+	}
+
+	m.auditEntryID++
+	m.auditEntries = append(m.auditEntries, queries.AuditEntry{
+		ID:           m.auditEntryID,
+		Presentation: arg.Presentation,
+		Section:      arg.Section,
+		Actor:        arg.Actor,
+		Action:       arg.Action,
+		Diff:         arg.Diff,
+		CreatedAt:    time.Now(),
+	})
+
+	return nil
+}
+
+func (m *Memory) GetAuditEntries(
+	_ context.Context,
+	arg queries.GetAuditEntriesParams,
+) ([]queries.GetAuditEntriesRow, error) {
+	defer m.rlock()()
+
+	var entries []queries.GetAuditEntriesRow
+	for _, entry := range slices.Backward(m.auditEntries) {
+		if entry.Presentation != arg.PresentationID {
+			continue
+		}
+
+		row := queries.GetAuditEntriesRow{
+			ID:           entry.ID,
+			Presentation: entry.Presentation,
+			Section:      entry.Section,
+			Actor:        entry.Actor,
+			Action:       entry.Action,
+			Diff:         entry.Diff,
+			CreatedAt:    entry.CreatedAt,
+		}
+		row.ActorUsername = m.username(entry.Actor)
+		entries = append(entries, row)
+	}
+
+	return paginate(entries, arg.QueryOffset, arg.QueryLimit), nil
+}
+
+func (m *Memory) GetAuditEntriesMetadata(_ context.Context, presentationID int64) (int64, error) {
+	defer m.rlock()()
+
+	var count int64
+	for _, entry := range m.auditEntries {
+		if entry.Presentation == presentationID {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (m *Memory) CreatePresentationVersion(
+	_ context.Context,
+	arg queries.CreatePresentationVersionParams,
+) (queries.PresentationVersion, error) {
+	defer m.lock()()
+
+	if _, ok := m.presentations[arg.Presentation]; !ok {
+		return queries.PresentationVersion{}, ErrInvalidReference
+	}
+
+	versions := m.versions[arg.Presentation]
+	version := queries.PresentationVersion{
+		Presentation: arg.Presentation,
+		Version:      int32(len(versions) + 1),
+		Name:         arg.Name,
+		Sections:     arg.Sections,
+		Actor:        arg.Actor,
+		CreatedAt:    time.Now(),
+	}
+	// clipped so appending never writes to an array shared with the state
+	// saved by InTx
+	m.versions[arg.Presentation] = append(slices.Clip(versions), version)
+
+	return version, nil
+}
+
+func (m *Memory) GetPresentationVersions(
+	_ context.Context,
+	arg queries.GetPresentationVersionsParams,
+) ([]queries.GetPresentationVersionsRow, error) {
+	defer m.rlock()()
+
+	var versions []queries.GetPresentationVersionsRow
+	for _, version := range slices.Backward(m.versions[arg.PresentationID]) {
+		versions = append(versions, queries.GetPresentationVersionsRow{
+			Presentation:  version.Presentation,
+			Version:       version.Version,
+			Name:          version.Name,
+			CreatedAt:     version.CreatedAt,
+			ActorUsername: m.username(version.Actor),
+		})
+	}
+
+	return paginate(versions, arg.QueryOffset, arg.QueryLimit), nil
+}
+
+func (m *Memory) GetPresentationVersionsMetadata(_ context.Context, presentationID int64) (int64, error) {
+	defer m.rlock()()
+
+	return int64(len(m.versions[presentationID])), nil
+}
+
+func (m *Memory) GetPresentationVersion(
+	_ context.Context,
+	arg queries.GetPresentationVersionParams,
+) (queries.GetPresentationVersionRow, error) {
+	defer m.rlock()()
+
+	versions := m.versions[arg.PresentationID]
+	if arg.Version < 1 || int(arg.Version) > len(versions) {
+		return queries.GetPresentationVersionRow{}, sql.ErrNoRows
+	}
+
+	version := versions[arg.Version-1]
+	return queries.GetPresentationVersionRow{
+		Presentation:  version.Presentation,
+		Version:       version.Version,
+		Name:          version.Name,
+		Sections:      version.Sections,
+		Actor:         version.Actor,
+		CreatedAt:     version.CreatedAt,
+		ActorUsername: m.username(version.Actor),
+	}, nil
+}
+
+// cleanPositions renumbers the sections of a presentation so their positions
+// go from 1 to n, keeping their relative order. Callers must hold the lock.
+func (m *Memory) cleanPositions(presentationID int64) {
+	sections := m.sectionsOf(presentationID)
+	sortByPosition(sections)
+
+	for i, section := range sections {
+		if section.Position == int16(i+1) {
+			continue
+		}
+
+		section.Position = int16(i + 1)
+		section.Version++
+		m.sections[section.ID] = section
+	}
+}
+
+func (m *Memory) lock() func() {
+	if m.inTx {
+		return func() {}
+	}
+
+	m.mu.Lock()
+	return m.mu.Unlock
+}
+
+func (m *Memory) rlock() func() {
+	if m.inTx {
+		return func() {}
+	}
+
+	m.mu.RLock()
+	return m.mu.RUnlock
+}
+
+// visible reports whether account can list presentation, a nil account sees
+// every presentation. Presentations without an owner are only listed to nil
+// accounts, that is workspace admins. Callers must hold the lock.
+func (m *Memory) visible(presentation queries.Presentation, account *int64, sharedWithMe bool) bool {
+	if account == nil {
+		return true
+	}
+
+	if _, ok := m.grants[grantKey{presentation.ID, *account}]; ok {
+		return true
+	}
+	if sharedWithMe {
+		return false
+	}
+
+	return presentation.Owner != nil && *presentation.Owner == *account
+}
+
+// username returns the username of the account, nil when there is none.
+// Callers must hold the lock.
+func (m *Memory) username(accountID *int64) *string {
+	if accountID == nil {
+		return nil
+	}
+
+	account, ok := m.accounts[*accountID]
+	if !ok {
+		return nil
+	}
+
+	return &account.Username
+}
+
+// sectionsOf returns the sections of a presentation that are not deleted.
+// Callers must hold the lock.
+func (m *Memory) sectionsOf(presentationID int64) []queries.Section {
+	var sections []queries.Section
+	for _, section := range m.sections {
+		if section.Presentation == presentationID && section.DeletedAt == nil {
+			sections = append(sections, section)
+		}
+	}
+
+	return sections
+}
+
+func sortByPosition(sections []queries.Section) {
+	slices.SortFunc(sections, func(a, b queries.Section) int {
+		return cmp.Or(cmp.Compare(a.Position, b.Position), cmp.Compare(a.ID, b.ID))
+	})
+}
+
+// matchesFilters reports whether an item with the given name, duration and
+// creation time passes the field filters of a list, nil filters are ignored.
+func matchesFilters(
+	name string,
+	duration time.Duration,
+	createdAt time.Time,
+	nameContains *string,
+	minDuration, maxDuration *time.Duration,
+	createdAfter *time.Time,
+) bool {
+	switch {
+	case nameContains != nil && !strings.Contains(strings.ToLower(name), strings.ToLower(*nameContains)):
+		return false
+	case minDuration != nil && duration < *minDuration:
+		return false
+	case maxDuration != nil && duration > *maxDuration:
+		return false
+	case createdAfter != nil && !createdAt.After(*createdAfter):
+		return false
+	}
+
+	return true
+}
+
+type sortField struct {
+	by        string
+	direction string
+}
+
+// compareRows compares rows by fields in order, compare holds how to compare
+// rows by each field and fallback breaks the ties left. Unknown fields are
+// skipped.
+func compareRows[T any](
+	fields []sortField,
+	compare map[string]func(a, b T) int,
+	fallback func(a, b T) int,
+) func(a, b T) int {
+	return func(a, b T) int {
+		for _, field := range fields {
+			compareField, ok := compare[field.by]
+			if !ok {
+				continue
+			}
+			if c := direction(field.direction, compareField(a, b)); c != 0 {
+				return c
+			}
+		}
+
+		return fallback(a, b)
+	}
+}
+
+func direction(direction string, c int) int {
+	if direction == "DESC" {
+		return -c
+	}
+
+	return c
+}
+
+func paginate[T any](rows []T, offset, limit int32) []T {
+	start := min(int(max(offset, 0)), len(rows))
+	end := min(start+int(max(limit, 0)), len(rows))
+
+	if start == end {
+		return nil
+	}
+
+	return rows[start:end]
+}
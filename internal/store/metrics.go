@@ -0,0 +1,78 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/PabloVarg/presentation-timer/internal/metrics"
+	sqlitequeries "github.com/PabloVarg/presentation-timer/internal/queries/sqlite/sqlc"
+	"github.com/jackc/pgx/v5"
+)
+
+// QueryTracer times the queries sent to postgres by their sqlc name, it is set
+// as the tracer of the connections of the pool.
+type QueryTracer struct{}
+
+type queryStartKey struct{}
+
+type queryStart struct {
+	name string
+	at   time.Time
+}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{
+		name: metrics.QueryName(data.SQL),
+		at:   time.Now(),
+	})
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+
+	observeQuery(start.name, time.Since(start.at), data.Err)
+}
+
+// timedDB times the queries sent to sqlite by their sqlc name.
+type timedDB struct {
+	sqlitequeries.DBTX
+}
+
+func (db timedDB) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	start := time.Now()
+	result, err := db.DBTX.ExecContext(ctx, query, args...)
+	observeQuery(metrics.QueryName(query), time.Since(start), err)
+
+	return result, err
+}
+
+func (db timedDB) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := db.DBTX.QueryContext(ctx, query, args...)
+	observeQuery(metrics.QueryName(query), time.Since(start), err)
+
+	return rows, err
+}
+
+func (db timedDB) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	start := time.Now()
+	row := db.DBTX.QueryRowContext(ctx, query, args...)
+	observeQuery(metrics.QueryName(query), time.Since(start), row.Err())
+
+	return row
+}
+
+// observeQuery records a query in the metrics, not finding rows is not an
+// error of the database.
+func observeQuery(name string, d time.Duration, err error) {
+	metrics.DBQueryDuration.WithLabelValues(name).Observe(d.Seconds())
+
+	if err != nil && !errors.Is(err, sql.ErrNoRows) && !errors.Is(err, pgx.ErrNoRows) {
+		metrics.DBQueryErrors.WithLabelValues(name).Inc()
+	}
+}
@@ -0,0 +1,118 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SQLSTATE codes postgres reports for constraint errors.
+const (
+	foreignKeyViolation = "23503"
+	uniqueViolation     = "23505"
+)
+
+// Postgres is the Store backed by the sqlc generated queries.
+type Postgres struct {
+	*queries.Queries
+	pool *pgxpool.Pool
+	inTx bool
+}
+
+func NewPostgres(pool *pgxpool.Pool) Postgres {
+	return Postgres{
+		Queries: queries.New(pool),
+		pool:    pool,
+	}
+}
+
+func (p Postgres) InTx(ctx context.Context, fn func(Store) error) error {
+	if p.inTx {
+		return fn(p)
+	}
+
+	tx, err := p.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(Postgres{
+		Queries: p.Queries.WithTx(tx),
+		pool:    p.pool,
+		inTx:    true,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func (p Postgres) PoolStats() PoolStats {
+	stat := p.pool.Stat()
+
+	return PoolStats{
+		TotalConns:      stat.TotalConns(),
+		AcquiredConns:   stat.AcquiredConns(),
+		IdleConns:       stat.IdleConns(),
+		MaxConns:        stat.MaxConns(),
+		AcquireCount:    stat.AcquireCount(),
+		AcquireDuration: stat.AcquireDuration(),
+		WaitCount:       stat.EmptyAcquireCount(),
+	}
+}
+
+func (p Postgres) CreateSection(ctx context.Context, arg queries.CreateSectionParams) (queries.Section, error) {
+	section, err := p.Queries.CreateSection(ctx, arg)
+	return section, translateError(err)
+}
+
+func (p Postgres) UpsertGrant(ctx context.Context, arg queries.UpsertGrantParams) error {
+	return translateError(p.Queries.UpsertGrant(ctx, arg))
+}
+
+func (p Postgres) CreateAccount(ctx context.Context, arg queries.CreateAccountParams) (queries.Account, error) {
+	account, err := p.Queries.CreateAccount(ctx, arg)
+	return account, translateError(err)
+}
+
+func (p Postgres) CreateAPIKey(ctx context.Context, arg queries.CreateAPIKeyParams) (queries.ApiKey, error) {
+	key, err := p.Queries.CreateAPIKey(ctx, arg)
+	return key, translateError(err)
+}
+
+func (p Postgres) UpsertWorkspaceMember(ctx context.Context, arg queries.UpsertWorkspaceMemberParams) error {
+	return translateError(p.Queries.UpsertWorkspaceMember(ctx, arg))
+}
+
+func (p Postgres) CreatePresentationVersion(
+	ctx context.Context,
+	arg queries.CreatePresentationVersionParams,
+) (queries.PresentationVersion, error) {
+	version, err := p.Queries.CreatePresentationVersion(ctx, arg)
+	return version, translateError(err)
+}
+
+func (p Postgres) CreateShareLink(ctx context.Context, arg queries.CreateShareLinkParams) (queries.ShareLink, error) {
+	link, err := p.Queries.CreateShareLink(ctx, arg)
+	return link, translateError(err)
+}
+
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case foreignKeyViolation:
+		return errors.Join(ErrInvalidReference, err)
+	case uniqueViolation:
+		return errors.Join(ErrConflict, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,26 @@
+package store
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SearchTerms splits a full text search into the lower case words it is made
+// of, it is how the stores without tsvector support read a search.
+func SearchTerms(search string) []string {
+	return strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// matchesSearch reports whether text contains every word of search.
+func matchesSearch(text string, search string) bool {
+	text = strings.ToLower(text)
+	for _, term := range SearchTerms(search) {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+
+	return true
+}
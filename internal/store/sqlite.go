@@ -0,0 +1,861 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+	sqlitequeries "github.com/PabloVarg/presentation-timer/internal/queries/sqlite/sqlc"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// SQLite is the Store backed by a local sqlite database, it translates between
+// the sqlite generated queries and the types used by the rest of the server.
+type SQLite struct {
+	db      *sql.DB
+	queries *sqlitequeries.Queries
+	inTx    bool
+}
+
+// OpenSQLite opens the database at path with the pragmas the store relies on,
+// foreign keys are off by default in sqlite. Times are written in the sqlite
+// format so they compare as text. Transactions take the write lock when they
+// begin, as sqlite has no row locks.
+func OpenSQLite(path string) (*sql.DB, error) {
+	pragmas := url.Values{}
+	pragmas.Add("_pragma", "foreign_keys(1)")
+	pragmas.Add("_pragma", "journal_mode(WAL)")
+	pragmas.Add("_pragma", "busy_timeout(5000)")
+	pragmas.Add("_time_format", "sqlite")
+	pragmas.Add("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite", fmt.Sprintf("file:%s?%s", path, pragmas.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func NewSQLite(db *sql.DB) SQLite {
+	return SQLite{
+		db:      db,
+		queries: sqlitequeries.New(timedDB{db}),
+	}
+}
+
+func (s SQLite) InTx(ctx context.Context, fn func(Store) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(SQLite{
+		db:      s.db,
+		queries: sqlitequeries.New(timedDB{tx}),
+		inTx:    true,
+	}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (s SQLite) PoolStats() PoolStats {
+	stats := s.db.Stats()
+
+	return PoolStats{
+		TotalConns:      int32(stats.OpenConnections),
+		AcquiredConns:   int32(stats.InUse),
+		IdleConns:       int32(stats.Idle),
+		MaxConns:        int32(stats.MaxOpenConnections),
+		AcquireDuration: stats.WaitDuration,
+		WaitCount:       stats.WaitCount,
+	}
+}
+
+func (s SQLite) GetPresentations(
+	ctx context.Context,
+	arg queries.GetPresentationsParams,
+) ([]queries.GetPresentationsRow, error) {
+	rows, err := s.queries.GetPresentations(ctx, sqlitequeries.GetPresentationsParams{
+		QueryOffset:     int64(arg.QueryOffset),
+		QueryLimit:      int64(arg.QueryLimit),
+		Direction1:      arg.Direction1,
+		SortBy1:         arg.SortBy1,
+		Direction2:      arg.Direction2,
+		SortBy2:         arg.SortBy2,
+		Direction3:      arg.Direction3,
+		SortBy3:         arg.SortBy3,
+		IDDirection:     arg.IDDirection,
+		CursorID:        arg.CursorID,
+		CursorName:      arg.CursorName,
+		CursorDuration:  arg.CursorDuration,
+		CursorCreatedAt: *sqliteTime(&arg.CursorCreatedAt),
+		Workspace:       arg.Workspace,
+		Account:         arg.Account,
+		SharedWithMe:    arg.SharedWithMe,
+		NameContains:    arg.NameContains,
+		CreatedAfter:    sqliteTime(arg.CreatedAfter),
+		Search:          sqliteSearch(arg.Search),
+		MinDuration:     arg.MinDuration,
+		MaxDuration:     arg.MaxDuration,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.GetPresentationsRow
+	for _, row := range rows {
+		result = append(result, queries.GetPresentationsRow(row))
+	}
+
+	return result, nil
+}
+
+func (s SQLite) GetPresentationsMetadata(
+	ctx context.Context,
+	arg queries.GetPresentationsMetadataParams,
+) (int64, error) {
+	return s.queries.GetPresentationsMetadata(ctx, sqlitequeries.GetPresentationsMetadataParams{
+		Workspace:    arg.Workspace,
+		Account:      arg.Account,
+		SharedWithMe: arg.SharedWithMe,
+		NameContains: arg.NameContains,
+		CreatedAfter: sqliteTime(arg.CreatedAfter),
+		Search:       sqliteSearch(arg.Search),
+		MinDuration:  arg.MinDuration,
+		MaxDuration:  arg.MaxDuration,
+	})
+}
+
+func (s SQLite) GetPresentation(ctx context.Context, id int64) (queries.Presentation, error) {
+	presentation, err := s.queries.GetPresentation(ctx, id)
+	return queries.Presentation(presentation), err
+}
+
+func (s SQLite) LockPresentation(ctx context.Context, id int64) (queries.Presentation, error) {
+	presentation, err := s.queries.LockPresentation(ctx, id)
+	return queries.Presentation(presentation), err
+}
+
+func (s SQLite) CreatePresentation(
+	ctx context.Context,
+	arg queries.CreatePresentationParams,
+) (queries.Presentation, error) {
+	presentation, err := s.queries.CreatePresentation(ctx, sqlitequeries.CreatePresentationParams{
+		Workspace: arg.Workspace,
+		Name:      arg.Name,
+		Owner:     arg.Owner,
+		Now:       time.Now().UTC(),
+	})
+	if err != nil {
+		return queries.Presentation{}, translateSQLiteError(err)
+	}
+
+	return queries.Presentation(presentation), nil
+}
+
+func (s SQLite) UpdatePresentation(ctx context.Context, arg queries.UpdatePresentationParams) (int64, error) {
+	return s.queries.UpdatePresentation(ctx, sqlitequeries.UpdatePresentationParams(arg))
+}
+
+func (s SQLite) PatchPresentation(ctx context.Context, arg queries.PatchPresentationParams) (int64, error) {
+	return s.queries.PatchPresentation(ctx, sqlitequeries.PatchPresentationParams(arg))
+}
+
+func (s SQLite) DeletePresentation(ctx context.Context, arg queries.DeletePresentationParams) (int64, error) {
+	now := arg.Now.UTC()
+
+	return s.queries.DeletePresentation(ctx, sqlitequeries.DeletePresentationParams{
+		Now: &now,
+		ID:  arg.ID,
+	})
+}
+
+func (s SQLite) GetPresentationAccess(
+	ctx context.Context,
+	arg queries.GetPresentationAccessParams,
+) (queries.GetPresentationAccessRow, error) {
+	access, err := s.queries.GetPresentationAccess(ctx, sqlitequeries.GetPresentationAccessParams(arg))
+	return queries.GetPresentationAccessRow(access), err
+}
+
+func (s SQLite) GetGrants(ctx context.Context, presentationID int64) ([]queries.GetGrantsRow, error) {
+	grants, err := s.queries.GetGrants(ctx, presentationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.GetGrantsRow
+	for _, grant := range grants {
+		result = append(result, queries.GetGrantsRow(grant))
+	}
+
+	return result, nil
+}
+
+func (s SQLite) UpsertGrant(ctx context.Context, arg queries.UpsertGrantParams) error {
+	return translateSQLiteError(s.queries.UpsertGrant(ctx, sqlitequeries.UpsertGrantParams(arg)))
+}
+
+func (s SQLite) DeleteGrant(ctx context.Context, arg queries.DeleteGrantParams) (int64, error) {
+	return s.queries.DeleteGrant(ctx, sqlitequeries.DeleteGrantParams(arg))
+}
+
+func (s SQLite) GetSections(ctx context.Context, arg queries.GetSectionsParams) ([]queries.Section, error) {
+	sections, err := s.queries.GetSections(ctx, sqlitequeries.GetSectionsParams{
+		PresentationID:  arg.PresentationID,
+		QueryOffset:     int64(arg.QueryOffset),
+		QueryLimit:      int64(arg.QueryLimit),
+		Direction1:      arg.Direction1,
+		SortBy1:         arg.SortBy1,
+		Direction2:      arg.Direction2,
+		SortBy2:         arg.SortBy2,
+		Direction3:      arg.Direction3,
+		SortBy3:         arg.SortBy3,
+		IDDirection:     arg.IDDirection,
+		CursorID:        arg.CursorID,
+		CursorName:      arg.CursorName,
+		CursorDuration:  arg.CursorDuration,
+		CursorPosition:  int64(arg.CursorPosition),
+		CursorCreatedAt: *sqliteTime(&arg.CursorCreatedAt),
+		NameContains:    arg.NameContains,
+		MinDuration:     arg.MinDuration,
+		MaxDuration:     arg.MaxDuration,
+		CreatedAfter:    sqliteTime(arg.CreatedAfter),
+		Search:          sqliteSearch(arg.Search),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromSQLiteSections(sections), nil
+}
+
+func (s SQLite) GetSectionsMetadata(ctx context.Context, arg queries.GetSectionsMetadataParams) (int64, error) {
+	return s.queries.GetSectionsMetadata(ctx, sqlitequeries.GetSectionsMetadataParams{
+		PresentationID: arg.PresentationID,
+		NameContains:   arg.NameContains,
+		MinDuration:    arg.MinDuration,
+		MaxDuration:    arg.MaxDuration,
+		CreatedAfter:   sqliteTime(arg.CreatedAfter),
+		Search:         sqliteSearch(arg.Search),
+	})
+}
+
+func (s SQLite) GetSection(ctx context.Context, id int64) (queries.Section, error) {
+	section, err := s.queries.GetSection(ctx, id)
+	return fromSQLiteSection(section), err
+}
+
+func (s SQLite) LockSection(ctx context.Context, id int64) (queries.Section, error) {
+	section, err := s.queries.LockSection(ctx, id)
+	return fromSQLiteSection(section), err
+}
+
+func (s SQLite) CreateSection(ctx context.Context, arg queries.CreateSectionParams) (queries.Section, error) {
+	section, err := s.queries.CreateSection(ctx, sqlitequeries.CreateSectionParams{
+		Presentation: arg.Presentation,
+		Name:         arg.Name,
+		Duration:     arg.Duration,
+		Position:     int64(arg.Position),
+		Now:          time.Now().UTC(),
+	})
+	if err != nil {
+		return queries.Section{}, translateSQLiteError(err)
+	}
+
+	return fromSQLiteSection(section), nil
+}
+
+func (s SQLite) UpdateSection(ctx context.Context, arg queries.UpdateSectionParams) (int64, error) {
+	return s.queries.UpdateSection(ctx, sqlitequeries.UpdateSectionParams{
+		Name:     arg.Name,
+		Duration: arg.Duration,
+		Position: int64(arg.Position),
+		ID:       arg.ID,
+	})
+}
+
+func (s SQLite) PatchSection(ctx context.Context, arg queries.PatchSectionParams) (int64, error) {
+	var position *int64
+	if arg.Position != nil {
+		position = new(int64)
+		*position = int64(*arg.Position)
+	}
+
+	return s.queries.PatchSection(ctx, sqlitequeries.PatchSectionParams{
+		Name:     arg.Name,
+		Duration: arg.Duration,
+		Position: position,
+		ID:       arg.ID,
+	})
+}
+
+func (s SQLite) DeleteSection(ctx context.Context, arg queries.DeleteSectionParams) (int64, error) {
+	now := arg.Now.UTC()
+
+	return s.queries.DeleteSection(ctx, sqlitequeries.DeleteSectionParams{
+		Now: &now,
+		ID:  arg.ID,
+	})
+}
+
+func (s SQLite) MaxPosition(ctx context.Context, presentationID int64) (int16, error) {
+	position, err := s.queries.MaxPosition(ctx, presentationID)
+	return int16(position), err
+}
+
+// CleanPositions renumbers the sections of every presentation from 1 to n,
+// it does what the clean_section_positions procedure does in postgres.
+func (s SQLite) CleanPositions(ctx context.Context) error {
+	return s.withQueries(ctx, func(q *sqlitequeries.Queries) error {
+		sections, err := q.GetAllSectionsByPosition(ctx)
+		if err != nil {
+			return err
+		}
+
+		var presentation, position int64
+		for _, section := range sections {
+			if section.Presentation != presentation {
+				presentation, position = section.Presentation, 0
+			}
+			position++
+			if section.Position == position {
+				continue
+			}
+
+			if err := q.SetPosition(ctx, sqlitequeries.SetPositionParams{
+				Position: position,
+				ID:       section.ID,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s SQLite) CleanPositionsBySectionGroup(ctx context.Context, id int64) error {
+	return s.withQueries(ctx, func(q *sqlitequeries.Queries) error {
+		// the section itself may be deleted, its group is still renumbered
+		presentation, err := q.GetSectionPresentation(ctx, id)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+
+		sections, err := q.GetSectionsByPosition(ctx, presentation)
+		if err != nil {
+			return err
+		}
+
+		for i, section := range sections {
+			if section.Position == int64(i+1) {
+				continue
+			}
+
+			if err := q.SetPosition(ctx, sqlitequeries.SetPositionParams{
+				Position: int64(i + 1),
+				ID:       section.ID,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s SQLite) SetSectionPosition(ctx context.Context, arg queries.SetSectionPositionParams) error {
+	return s.withQueries(ctx, func(q *sqlitequeries.Queries) error {
+		section, err := q.GetSection(ctx, arg.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		if section.Position == int64(arg.Position) {
+			return nil
+		}
+
+		return q.SetPosition(ctx, sqlitequeries.SetPositionParams{
+			Position: int64(arg.Position),
+			ID:       arg.ID,
+		})
+	})
+}
+
+func (s SQLite) GetSectionsByPosition(ctx context.Context, presentationID int64) ([]queries.Section, error) {
+	sections, err := s.queries.GetSectionsByPosition(ctx, presentationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromSQLiteSections(sections), nil
+}
+
+func (s SQLite) GetSectionsOfPresentations(ctx context.Context, presentationIDs []int64) ([]queries.Section, error) {
+	sections, err := s.queries.GetSectionsOfPresentations(ctx, presentationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromSQLiteSections(sections), nil
+}
+
+func (s SQLite) RestorePresentation(ctx context.Context, id int64) (int64, error) {
+	return s.queries.RestorePresentation(ctx, id)
+}
+
+func (s SQLite) GetDeletedPresentations(
+	ctx context.Context,
+	arg queries.GetDeletedPresentationsParams,
+) ([]queries.Presentation, error) {
+	presentations, err := s.queries.GetDeletedPresentations(ctx, sqlitequeries.GetDeletedPresentationsParams(arg))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.Presentation
+	for _, presentation := range presentations {
+		result = append(result, queries.Presentation(presentation))
+	}
+
+	return result, nil
+}
+
+func (s SQLite) PurgePresentations(ctx context.Context, before time.Time) (int64, error) {
+	before = before.UTC()
+	return s.queries.PurgePresentations(ctx, &before)
+}
+
+func (s SQLite) GetDeletedSection(ctx context.Context, id int64) (queries.Section, error) {
+	section, err := s.queries.GetDeletedSection(ctx, id)
+	return fromSQLiteSection(section), err
+}
+
+func (s SQLite) RestoreSection(ctx context.Context, arg queries.RestoreSectionParams) (int64, error) {
+	return s.queries.RestoreSection(ctx, sqlitequeries.RestoreSectionParams{
+		Position: int64(arg.Position),
+		ID:       arg.ID,
+	})
+}
+
+func (s SQLite) GetDeletedSections(
+	ctx context.Context,
+	arg queries.GetDeletedSectionsParams,
+) ([]queries.Section, error) {
+	sections, err := s.queries.GetDeletedSections(ctx, sqlitequeries.GetDeletedSectionsParams(arg))
+	if err != nil {
+		return nil, err
+	}
+
+	return fromSQLiteSections(sections), nil
+}
+
+func (s SQLite) PurgeSections(ctx context.Context, before time.Time) (int64, error) {
+	before = before.UTC()
+	return s.queries.PurgeSections(ctx, &before)
+}
+
+func (s SQLite) CreateAccount(ctx context.Context, arg queries.CreateAccountParams) (queries.Account, error) {
+	account, err := s.queries.CreateAccount(ctx, sqlitequeries.CreateAccountParams(arg))
+	if err != nil {
+		return queries.Account{}, translateSQLiteError(err)
+	}
+
+	return queries.Account(account), nil
+}
+
+func (s SQLite) GetAccount(ctx context.Context, id int64) (queries.Account, error) {
+	account, err := s.queries.GetAccount(ctx, id)
+	return queries.Account(account), err
+}
+
+func (s SQLite) GetAccountByUsername(ctx context.Context, username string) (queries.Account, error) {
+	account, err := s.queries.GetAccountByUsername(ctx, username)
+	return queries.Account(account), err
+}
+
+func (s SQLite) CountAccounts(ctx context.Context) (int64, error) {
+	return s.queries.CountAccounts(ctx)
+}
+
+// LockAccounts has nothing to do, transactions take the write lock of the
+// database as they begin.
+func (s SQLite) LockAccounts(_ context.Context) error {
+	return nil
+}
+
+func (s SQLite) CreateSession(ctx context.Context, arg queries.CreateSessionParams) error {
+	err := s.queries.CreateSession(ctx, sqlitequeries.CreateSessionParams{
+		TokenHash: arg.TokenHash,
+		Account:   arg.Account,
+		ExpiresAt: arg.ExpiresAt.UTC(),
+	})
+	return translateSQLiteError(err)
+}
+
+func (s SQLite) GetSessionAccount(
+	ctx context.Context,
+	arg queries.GetSessionAccountParams,
+) (queries.Account, error) {
+	account, err := s.queries.GetSessionAccount(ctx, sqlitequeries.GetSessionAccountParams{
+		TokenHash: arg.TokenHash,
+		Now:       arg.Now.UTC(),
+	})
+	return queries.Account(account), err
+}
+
+func (s SQLite) DeleteSession(ctx context.Context, tokenHash []byte) (int64, error) {
+	return s.queries.DeleteSession(ctx, tokenHash)
+}
+
+func (s SQLite) DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error) {
+	return s.queries.DeleteExpiredSessions(ctx, now.UTC())
+}
+
+func (s SQLite) CreateAPIKey(ctx context.Context, arg queries.CreateAPIKeyParams) (queries.ApiKey, error) {
+	key, err := s.queries.CreateAPIKey(ctx, sqlitequeries.CreateAPIKeyParams(arg))
+	if err != nil {
+		return queries.ApiKey{}, translateSQLiteError(err)
+	}
+
+	return queries.ApiKey(key), nil
+}
+
+func (s SQLite) GetAPIKeys(ctx context.Context, arg queries.GetAPIKeysParams) ([]queries.ApiKey, error) {
+	keys, err := s.queries.GetAPIKeys(ctx, sqlitequeries.GetAPIKeysParams(arg))
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.ApiKey
+	for _, key := range keys {
+		result = append(result, queries.ApiKey(key))
+	}
+
+	return result, nil
+}
+
+func (s SQLite) DeleteAPIKey(ctx context.Context, arg queries.DeleteAPIKeyParams) (int64, error) {
+	return s.queries.DeleteAPIKey(ctx, sqlitequeries.DeleteAPIKeyParams(arg))
+}
+
+func (s SQLite) UseAPIKey(ctx context.Context, arg queries.UseAPIKeyParams) (queries.UseAPIKeyRow, error) {
+	now := arg.Now.UTC()
+
+	key, err := s.queries.UseAPIKey(ctx, sqlitequeries.UseAPIKeyParams{
+		Now:     &now,
+		KeyHash: arg.KeyHash,
+	})
+	return queries.UseAPIKeyRow(key), err
+}
+
+func (s SQLite) CreateShareLink(ctx context.Context, arg queries.CreateShareLinkParams) (queries.ShareLink, error) {
+	if arg.ExpiresAt != nil {
+		expiresAt := arg.ExpiresAt.UTC()
+		arg.ExpiresAt = &expiresAt
+	}
+
+	link, err := s.queries.CreateShareLink(ctx, sqlitequeries.CreateShareLinkParams(arg))
+	if err != nil {
+		return queries.ShareLink{}, translateSQLiteError(err)
+	}
+
+	return queries.ShareLink(link), nil
+}
+
+func (s SQLite) GetShareLinks(ctx context.Context, presentationID int64) ([]queries.ShareLink, error) {
+	links, err := s.queries.GetShareLinks(ctx, presentationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.ShareLink
+	for _, link := range links {
+		result = append(result, queries.ShareLink(link))
+	}
+
+	return result, nil
+}
+
+func (s SQLite) DeleteShareLink(ctx context.Context, arg queries.DeleteShareLinkParams) (int64, error) {
+	return s.queries.DeleteShareLink(ctx, sqlitequeries.DeleteShareLinkParams(arg))
+}
+
+func (s SQLite) GetValidShareLink(
+	ctx context.Context,
+	arg queries.GetValidShareLinkParams,
+) (queries.ShareLink, error) {
+	now := arg.Now.UTC()
+
+	link, err := s.queries.GetValidShareLink(ctx, sqlitequeries.GetValidShareLinkParams{
+		TokenHash: arg.TokenHash,
+		Now:       &now,
+	})
+	return queries.ShareLink(link), err
+}
+
+func (s SQLite) CreateWorkspace(ctx context.Context, name string) (queries.Workspace, error) {
+	workspace, err := s.queries.CreateWorkspace(ctx, name)
+	return queries.Workspace(workspace), err
+}
+
+func (s SQLite) GetWorkspace(ctx context.Context, id int64) (queries.Workspace, error) {
+	workspace, err := s.queries.GetWorkspace(ctx, id)
+	return queries.Workspace(workspace), err
+}
+
+func (s SQLite) GetWorkspaces(ctx context.Context, account int64) ([]queries.GetWorkspacesRow, error) {
+	workspaces, err := s.queries.GetWorkspaces(ctx, account)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.GetWorkspacesRow
+	for _, workspace := range workspaces {
+		result = append(result, queries.GetWorkspacesRow(workspace))
+	}
+
+	return result, nil
+}
+
+func (s SQLite) GetWorkspaceMember(ctx context.Context, arg queries.GetWorkspaceMemberParams) (string, error) {
+	return s.queries.GetWorkspaceMember(ctx, sqlitequeries.GetWorkspaceMemberParams(arg))
+}
+
+func (s SQLite) GetDefaultWorkspace(ctx context.Context, account int64) (queries.GetDefaultWorkspaceRow, error) {
+	workspace, err := s.queries.GetDefaultWorkspace(ctx, account)
+	return queries.GetDefaultWorkspaceRow(workspace), err
+}
+
+func (s SQLite) GetWorkspaceMembers(ctx context.Context, workspace int64) ([]queries.GetWorkspaceMembersRow, error) {
+	members, err := s.queries.GetWorkspaceMembers(ctx, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.GetWorkspaceMembersRow
+	for _, member := range members {
+		result = append(result, queries.GetWorkspaceMembersRow(member))
+	}
+
+	return result, nil
+}
+
+func (s SQLite) UpsertWorkspaceMember(ctx context.Context, arg queries.UpsertWorkspaceMemberParams) error {
+	return translateSQLiteError(s.queries.UpsertWorkspaceMember(ctx, sqlitequeries.UpsertWorkspaceMemberParams(arg)))
+}
+
+func (s SQLite) DeleteWorkspaceMember(ctx context.Context, arg queries.DeleteWorkspaceMemberParams) (int64, error) {
+	return s.queries.DeleteWorkspaceMember(ctx, sqlitequeries.DeleteWorkspaceMemberParams(arg))
+}
+
+func (s SQLite) CreateAuditEntry(ctx context.Context, arg queries.CreateAuditEntryParams) error {
+	return s.queries.CreateAuditEntry(ctx, sqlitequeries.CreateAuditEntryParams{
+		Presentation: arg.Presentation,
+		Section:      arg.Section,
+		Actor:        arg.Actor,
+		Action:       arg.Action,
+		Diff:         string(arg.Diff),
+	})
+}
+
+func (s SQLite) GetAuditEntries(
+	ctx context.Context,
+	arg queries.GetAuditEntriesParams,
+) ([]queries.GetAuditEntriesRow, error) {
+	entries, err := s.queries.GetAuditEntries(ctx, sqlitequeries.GetAuditEntriesParams{
+		PresentationID: arg.PresentationID,
+		QueryOffset:    int64(arg.QueryOffset),
+		QueryLimit:     int64(arg.QueryLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.GetAuditEntriesRow
+	for _, entry := range entries {
+		result = append(result, queries.GetAuditEntriesRow{
+			ID:            entry.ID,
+			Presentation:  entry.Presentation,
+			Section:       entry.Section,
+			Actor:         entry.Actor,
+			Action:        entry.Action,
+			Diff:          []byte(entry.Diff),
+			CreatedAt:     entry.CreatedAt,
+			ActorUsername: entry.ActorUsername,
+		})
+	}
+
+	return result, nil
+}
+
+func (s SQLite) GetAuditEntriesMetadata(ctx context.Context, presentationID int64) (int64, error) {
+	return s.queries.GetAuditEntriesMetadata(ctx, presentationID)
+}
+
+func (s SQLite) CreatePresentationVersion(
+	ctx context.Context,
+	arg queries.CreatePresentationVersionParams,
+) (queries.PresentationVersion, error) {
+	version, err := s.queries.CreatePresentationVersion(ctx, sqlitequeries.CreatePresentationVersionParams{
+		Presentation: arg.Presentation,
+		Name:         arg.Name,
+		Sections:     string(arg.Sections),
+		Actor:        arg.Actor,
+	})
+	if err != nil {
+		return queries.PresentationVersion{}, translateSQLiteError(err)
+	}
+
+	return queries.PresentationVersion{
+		Presentation: version.Presentation,
+		Version:      int32(version.Version),
+		Name:         version.Name,
+		Sections:     []byte(version.Sections),
+		Actor:        version.Actor,
+		CreatedAt:    version.CreatedAt,
+	}, nil
+}
+
+func (s SQLite) GetPresentationVersions(
+	ctx context.Context,
+	arg queries.GetPresentationVersionsParams,
+) ([]queries.GetPresentationVersionsRow, error) {
+	versions, err := s.queries.GetPresentationVersions(ctx, sqlitequeries.GetPresentationVersionsParams{
+		PresentationID: arg.PresentationID,
+		QueryOffset:    int64(arg.QueryOffset),
+		QueryLimit:     int64(arg.QueryLimit),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []queries.GetPresentationVersionsRow
+	for _, version := range versions {
+		result = append(result, queries.GetPresentationVersionsRow{
+			Presentation:  version.Presentation,
+			Version:       int32(version.Version),
+			Name:          version.Name,
+			CreatedAt:     version.CreatedAt,
+			ActorUsername: version.ActorUsername,
+		})
+	}
+
+	return result, nil
+}
+
+func (s SQLite) GetPresentationVersionsMetadata(ctx context.Context, presentationID int64) (int64, error) {
+	return s.queries.GetPresentationVersionsMetadata(ctx, presentationID)
+}
+
+func (s SQLite) GetPresentationVersion(
+	ctx context.Context,
+	arg queries.GetPresentationVersionParams,
+) (queries.GetPresentationVersionRow, error) {
+	version, err := s.queries.GetPresentationVersion(ctx, sqlitequeries.GetPresentationVersionParams{
+		PresentationID: arg.PresentationID,
+		Version:        int64(arg.Version),
+	})
+
+	return queries.GetPresentationVersionRow{
+		Presentation:  version.Presentation,
+		Version:       int32(version.Version),
+		Name:          version.Name,
+		Sections:      []byte(version.Sections),
+		Actor:         version.Actor,
+		CreatedAt:     version.CreatedAt,
+		ActorUsername: version.ActorUsername,
+	}, err
+}
+
+func (s SQLite) withQueries(ctx context.Context, fn func(*sqlitequeries.Queries) error) error {
+	return s.InTx(ctx, func(tx Store) error {
+		return fn(tx.(SQLite).queries)
+	})
+}
+
+func fromSQLiteSection(section sqlitequeries.Section) queries.Section {
+	return queries.Section{
+		ID:           section.ID,
+		Presentation: section.Presentation,
+		Name:         section.Name,
+		Duration:     section.Duration,
+		Position:     int16(section.Position),
+		DeletedAt:    section.DeletedAt,
+		Version:      section.Version,
+		CreatedAt:    section.CreatedAt,
+	}
+}
+
+func fromSQLiteSections(sections []sqlitequeries.Section) []queries.Section {
+	var result []queries.Section
+	for _, section := range sections {
+		result = append(result, fromSQLiteSection(section))
+	}
+
+	return result
+}
+
+func translateSQLiteError(err error) error {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return errors.Join(ErrInvalidReference, err)
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return errors.Join(ErrConflict, err)
+	default:
+		return err
+	}
+}
+
+// sqliteTime formats t the way times are written to the database, so they
+// can be compared as text in the queries.
+func sqliteTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	formatted := t.UTC().Format("2006-01-02 15:04:05.999999999-07:00")
+	return &formatted
+}
+
+// sqliteSearch turns a full text search into the JSON array of lower case
+// words the queries match, sqlite has no tsvector so every word has to be
+// contained in the searched names.
+func sqliteSearch(search *string) *string {
+	if search == nil {
+		return nil
+	}
+
+	terms, err := json.Marshal(SearchTerms(*search))
+	if err != nil {
+		return nil
+	}
+
+	encoded := string(terms)
+	return &encoded
+}
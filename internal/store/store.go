@@ -0,0 +1,186 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	queries "github.com/PabloVarg/presentation-timer/internal/queries/sqlc"
+)
+
+// ErrInvalidReference is returned when a row references another one that does
+// not exist, e.g. creating a section for a missing presentation.
+var ErrInvalidReference = errors.New("referenced row does not exist")
+
+// ErrConflict is returned when a row would duplicate a unique value, e.g.
+// creating an account with a username that is already taken.
+var ErrConflict = errors.New("row already exists")
+
+// Store is the storage used by the server. Lookups of missing rows return an
+// error matching sql.ErrNoRows.
+type Store interface {
+	PresentationStore
+	SectionStore
+	TrashStore
+	RunStore
+	AccountStore
+	ShareLinkStore
+	WorkspaceStore
+	AuditStore
+	VersionStore
+
+	// InTx runs fn in a transaction using the Store it receives, which is bound
+	// to it. The transaction is committed if fn returns nil and rolled back
+	// otherwise, nested calls join the outer transaction.
+	InTx(ctx context.Context, fn func(Store) error) error
+}
+
+// StatsStore is implemented by stores backed by a connection pool.
+type StatsStore interface {
+	PoolStats() PoolStats
+}
+
+type PoolStats struct {
+	TotalConns      int32         `json:"total_conns"`
+	AcquiredConns   int32         `json:"acquired_conns"`
+	IdleConns       int32         `json:"idle_conns"`
+	MaxConns        int32         `json:"max_conns"`
+	AcquireCount    int64         `json:"acquire_count"`
+	AcquireDuration time.Duration `json:"acquire_duration"`
+	WaitCount       int64         `json:"wait_count"`
+}
+
+type PresentationStore interface {
+	GetPresentations(ctx context.Context, arg queries.GetPresentationsParams) ([]queries.GetPresentationsRow, error)
+	GetPresentationsMetadata(ctx context.Context, arg queries.GetPresentationsMetadataParams) (int64, error)
+	GetPresentation(ctx context.Context, id int64) (queries.Presentation, error)
+	// LockPresentation is GetPresentation, locking the row until the end of
+	// the transaction where the database supports it.
+	LockPresentation(ctx context.Context, id int64) (queries.Presentation, error)
+	CreatePresentation(ctx context.Context, arg queries.CreatePresentationParams) (queries.Presentation, error)
+	UpdatePresentation(ctx context.Context, arg queries.UpdatePresentationParams) (int64, error)
+	PatchPresentation(ctx context.Context, arg queries.PatchPresentationParams) (int64, error)
+	DeletePresentation(ctx context.Context, arg queries.DeletePresentationParams) (int64, error)
+	GetPresentationAccess(
+		ctx context.Context,
+		arg queries.GetPresentationAccessParams,
+	) (queries.GetPresentationAccessRow, error)
+	GetGrants(ctx context.Context, presentationID int64) ([]queries.GetGrantsRow, error)
+	UpsertGrant(ctx context.Context, arg queries.UpsertGrantParams) error
+	DeleteGrant(ctx context.Context, arg queries.DeleteGrantParams) (int64, error)
+}
+
+// TrashStore holds the presentations and sections that were deleted, they are
+// hidden everywhere else until they are restored or purged.
+type TrashStore interface {
+	RestorePresentation(ctx context.Context, id int64) (int64, error)
+	GetDeletedPresentations(
+		ctx context.Context,
+		arg queries.GetDeletedPresentationsParams,
+	) ([]queries.Presentation, error)
+	PurgePresentations(ctx context.Context, before time.Time) (int64, error)
+	GetDeletedSection(ctx context.Context, id int64) (queries.Section, error)
+	RestoreSection(ctx context.Context, arg queries.RestoreSectionParams) (int64, error)
+	GetDeletedSections(ctx context.Context, arg queries.GetDeletedSectionsParams) ([]queries.Section, error)
+	PurgeSections(ctx context.Context, before time.Time) (int64, error)
+}
+
+type SectionStore interface {
+	GetSections(ctx context.Context, arg queries.GetSectionsParams) ([]queries.Section, error)
+	GetSectionsMetadata(ctx context.Context, arg queries.GetSectionsMetadataParams) (int64, error)
+	GetSection(ctx context.Context, id int64) (queries.Section, error)
+	// LockSection is GetSection, locking the row until the end of the
+	// transaction where the database supports it.
+	LockSection(ctx context.Context, id int64) (queries.Section, error)
+	CreateSection(ctx context.Context, arg queries.CreateSectionParams) (queries.Section, error)
+	UpdateSection(ctx context.Context, arg queries.UpdateSectionParams) (int64, error)
+	PatchSection(ctx context.Context, arg queries.PatchSectionParams) (int64, error)
+	DeleteSection(ctx context.Context, arg queries.DeleteSectionParams) (int64, error)
+	MaxPosition(ctx context.Context, presentationID int64) (int16, error)
+	CleanPositions(ctx context.Context) error
+	CleanPositionsBySectionGroup(ctx context.Context, id int64) error
+	// SetSectionPosition moves a section to arg.Position without touching the
+	// other sections of its presentation, it does nothing if the section is
+	// already there.
+	SetSectionPosition(ctx context.Context, arg queries.SetSectionPositionParams) error
+	// GetSectionsOfPresentations returns the sections of several presentations
+	// at once, ordered by presentation and then by position.
+	GetSectionsOfPresentations(ctx context.Context, presentationIDs []int64) ([]queries.Section, error)
+}
+
+type RunStore interface {
+	GetSectionsByPosition(ctx context.Context, presentationID int64) ([]queries.Section, error)
+}
+
+// AccountStore holds the accounts and the credentials used to authenticate
+// them. Tokens and keys are only stored hashed.
+type AccountStore interface {
+	CreateAccount(ctx context.Context, arg queries.CreateAccountParams) (queries.Account, error)
+	GetAccount(ctx context.Context, id int64) (queries.Account, error)
+	GetAccountByUsername(ctx context.Context, username string) (queries.Account, error)
+	CountAccounts(ctx context.Context) (int64, error)
+	// LockAccounts keeps other transactions from creating accounts until the
+	// end of the transaction, so the first account is only created once.
+	LockAccounts(ctx context.Context) error
+	CreateSession(ctx context.Context, arg queries.CreateSessionParams) error
+	GetSessionAccount(ctx context.Context, arg queries.GetSessionAccountParams) (queries.Account, error)
+	DeleteSession(ctx context.Context, tokenHash []byte) (int64, error)
+	DeleteExpiredSessions(ctx context.Context, now time.Time) (int64, error)
+	CreateAPIKey(ctx context.Context, arg queries.CreateAPIKeyParams) (queries.ApiKey, error)
+	GetAPIKeys(ctx context.Context, arg queries.GetAPIKeysParams) ([]queries.ApiKey, error)
+	DeleteAPIKey(ctx context.Context, arg queries.DeleteAPIKeyParams) (int64, error)
+	UseAPIKey(ctx context.Context, arg queries.UseAPIKeyParams) (queries.UseAPIKeyRow, error)
+}
+
+// ShareLinkStore holds the links that give read only access to a presentation
+// without an account.
+type ShareLinkStore interface {
+	CreateShareLink(ctx context.Context, arg queries.CreateShareLinkParams) (queries.ShareLink, error)
+	GetShareLinks(ctx context.Context, presentationID int64) ([]queries.ShareLink, error)
+	DeleteShareLink(ctx context.Context, arg queries.DeleteShareLinkParams) (int64, error)
+	// GetValidShareLink returns the link with the token hash when it has not
+	// expired and its presentation is not deleted.
+	GetValidShareLink(ctx context.Context, arg queries.GetValidShareLinkParams) (queries.ShareLink, error)
+}
+
+// DefaultWorkspace is the workspace created by the migrations, data that
+// existed before workspaces were introduced belongs to it.
+const DefaultWorkspace int64 = 1
+
+// WorkspaceStore holds the workspaces presentations and API keys belong to and
+// the accounts that are members of them.
+type WorkspaceStore interface {
+	CreateWorkspace(ctx context.Context, name string) (queries.Workspace, error)
+	GetWorkspace(ctx context.Context, id int64) (queries.Workspace, error)
+	GetWorkspaces(ctx context.Context, account int64) ([]queries.GetWorkspacesRow, error)
+	GetWorkspaceMember(ctx context.Context, arg queries.GetWorkspaceMemberParams) (string, error)
+	GetDefaultWorkspace(ctx context.Context, account int64) (queries.GetDefaultWorkspaceRow, error)
+	GetWorkspaceMembers(ctx context.Context, workspace int64) ([]queries.GetWorkspaceMembersRow, error)
+	UpsertWorkspaceMember(ctx context.Context, arg queries.UpsertWorkspaceMemberParams) error
+	DeleteWorkspaceMember(ctx context.Context, arg queries.DeleteWorkspaceMemberParams) (int64, error)
+}
+
+// AuditStore records who changed presentations and their sections.
+type AuditStore interface {
+	CreateAuditEntry(ctx context.Context, arg queries.CreateAuditEntryParams) error
+	GetAuditEntries(ctx context.Context, arg queries.GetAuditEntriesParams) ([]queries.GetAuditEntriesRow, error)
+	GetAuditEntriesMetadata(ctx context.Context, presentationID int64) (int64, error)
+}
+
+// VersionStore keeps snapshots of the layout of presentations after every
+// change.
+type VersionStore interface {
+	CreatePresentationVersion(
+		ctx context.Context,
+		arg queries.CreatePresentationVersionParams,
+	) (queries.PresentationVersion, error)
+	GetPresentationVersions(
+		ctx context.Context,
+		arg queries.GetPresentationVersionsParams,
+	) ([]queries.GetPresentationVersionsRow, error)
+	GetPresentationVersionsMetadata(ctx context.Context, presentationID int64) (int64, error)
+	GetPresentationVersion(
+		ctx context.Context,
+		arg queries.GetPresentationVersionParams,
+	) (queries.GetPresentationVersionRow, error)
+}
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- presentations created before accounts existed, or while authentication is
+-- disabled, have no owner, only workspace admins can reach them
+ALTER TABLE presentation
+ADD COLUMN owner BIGINT REFERENCES account(id) ON DELETE CASCADE;
+
+CREATE TABLE presentation_grant (
+    presentation BIGINT NOT NULL REFERENCES presentation(id) ON DELETE CASCADE,
+    account BIGINT NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('viewer', 'editor', 'operator')),
+
+    PRIMARY KEY (presentation, account)
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE presentation_grant;
+
+ALTER TABLE presentation
+DROP COLUMN owner;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE share_link (
+    id BIGSERIAL PRIMARY KEY,
+    presentation BIGINT NOT NULL REFERENCES presentation(id) ON DELETE CASCADE,
+    token_hash BYTEA NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- links without expiration last until they are revoked
+    expires_at TIMESTAMPTZ
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE share_link;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workspace (
+    id BIGSERIAL PRIMARY KEY,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE workspace_member (
+    workspace BIGINT NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    account BIGINT NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('admin', 'member')),
+
+    PRIMARY KEY (workspace, account)
+);
+
+-- everything created before workspaces existed goes to a default workspace,
+-- administered by the existing accounts
+INSERT INTO workspace (name) VALUES ('Default');
+
+INSERT INTO workspace_member (workspace, account, role)
+SELECT workspace.id, account.id, 'admin'
+FROM workspace
+CROSS JOIN account;
+
+ALTER TABLE presentation
+ADD COLUMN workspace BIGINT NOT NULL DEFAULT 1 REFERENCES workspace(id) ON DELETE CASCADE;
+
+ALTER TABLE presentation
+ALTER COLUMN workspace DROP DEFAULT;
+
+CREATE INDEX presentation_workspace ON presentation(workspace);
+
+ALTER TABLE api_key
+ADD COLUMN workspace BIGINT NOT NULL DEFAULT 1 REFERENCES workspace(id) ON DELETE CASCADE;
+
+ALTER TABLE api_key
+ALTER COLUMN workspace DROP DEFAULT;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE api_key
+DROP COLUMN workspace;
+
+ALTER TABLE presentation
+DROP COLUMN workspace;
+
+DROP TABLE workspace_member;
+DROP TABLE workspace;
+-- +goose StatementEnd
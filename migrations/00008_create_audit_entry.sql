@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- entries don't reference the presentation so they outlive it
+CREATE TABLE audit_entry (
+    id BIGSERIAL PRIMARY KEY,
+    presentation BIGINT NOT NULL,
+    section BIGINT,
+    actor BIGINT REFERENCES account(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    diff JSONB NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX audit_entry_presentation ON audit_entry(presentation, id);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_entry;
+-- +goose StatementEnd
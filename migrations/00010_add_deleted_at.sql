@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE presentation ADD COLUMN deleted_at TIMESTAMPTZ;
+ALTER TABLE section ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX presentation_deleted_at ON presentation(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX section_deleted_at ON section(deleted_at) WHERE deleted_at IS NOT NULL;
+
+CREATE OR REPLACE PROCEDURE clean_section_positions()
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    p RECORD;
+BEGIN
+    FOR p IN SELECT id FROM presentation WHERE deleted_at IS NULL FOR UPDATE LOOP
+        WITH ordered AS (
+            SELECT
+                id,
+                ROW_NUMBER() OVER (ORDER BY position) AS new_position
+            FROM section
+            WHERE presentation = p.id AND deleted_at IS NULL
+        )
+        UPDATE section
+        SET position = ordered.new_position
+        FROM ordered
+        WHERE section.id = ordered.id;
+    END LOOP;
+END;
+$$;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE PROCEDURE clean_section_positions()
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    p RECORD;
+BEGIN
+    FOR p IN SELECT id FROM presentation FOR UPDATE LOOP
+        WITH ordered AS (
+            SELECT
+                id,
+                ROW_NUMBER() OVER (ORDER BY position) AS new_position
+            FROM section
+            WHERE presentation = p.id
+        )
+        UPDATE section
+        SET position = ordered.new_position
+        FROM ordered
+        WHERE section.id = ordered.id;
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE section DROP COLUMN deleted_at;
+ALTER TABLE presentation DROP COLUMN deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE presentation ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE section ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- sections whose position doesn't change keep their version
+CREATE OR REPLACE PROCEDURE clean_section_positions()
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    p RECORD;
+BEGIN
+    FOR p IN SELECT id FROM presentation WHERE deleted_at IS NULL FOR UPDATE LOOP
+        WITH ordered AS (
+            SELECT
+                id,
+                ROW_NUMBER() OVER (ORDER BY position) AS new_position
+            FROM section
+            WHERE presentation = p.id AND deleted_at IS NULL
+        )
+        UPDATE section
+        SET position = ordered.new_position, version = section.version + 1
+        FROM ordered
+        WHERE section.id = ordered.id AND section.position <> ordered.new_position;
+    END LOOP;
+END;
+$$;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+CREATE OR REPLACE PROCEDURE clean_section_positions()
+LANGUAGE plpgsql
+AS $$
+DECLARE
+    p RECORD;
+BEGIN
+    FOR p IN SELECT id FROM presentation WHERE deleted_at IS NULL FOR UPDATE LOOP
+        WITH ordered AS (
+            SELECT
+                id,
+                ROW_NUMBER() OVER (ORDER BY position) AS new_position
+            FROM section
+            WHERE presentation = p.id AND deleted_at IS NULL
+        )
+        UPDATE section
+        SET position = ordered.new_position
+        FROM ordered
+        WHERE section.id = ordered.id;
+    END LOOP;
+END;
+$$;
+
+ALTER TABLE section DROP COLUMN version;
+ALTER TABLE presentation DROP COLUMN version;
+-- +goose StatementEnd
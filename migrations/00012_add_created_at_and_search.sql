@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE presentation ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+ALTER TABLE section ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+CREATE INDEX presentation_search ON presentation USING GIN (to_tsvector('simple', name));
+CREATE INDEX section_search ON section USING GIN (to_tsvector('simple', name));
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX section_search;
+DROP INDEX presentation_search;
+
+ALTER TABLE section DROP COLUMN created_at;
+ALTER TABLE presentation DROP COLUMN created_at;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+// Package migrations embeds the SQL migrations so they can be applied by the
+// server binary itself.
+package migrations
+
+import "embed"
+
+// Postgres holds the postgres migrations at its root.
+//
+//go:embed *.sql
+var Postgres embed.FS
+
+// SQLite holds the sqlite migrations under the sqlite directory.
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS
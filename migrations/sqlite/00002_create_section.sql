@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE section (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    presentation INTEGER NOT NULL REFERENCES presentation(id) ON DELETE CASCADE,
+
+    name TEXT NOT NULL,
+    -- stored as nanoseconds, INTERVAL has integer affinity in sqlite
+    duration INTERVAL NOT NULL,
+    position INTEGER NOT NULL DEFAULT 1
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE section;
+-- +goose StatementEnd
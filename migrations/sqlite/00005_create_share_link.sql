@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE share_link (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    presentation INTEGER NOT NULL REFERENCES presentation(id) ON DELETE CASCADE,
+    token_hash BLOB NOT NULL UNIQUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- links without expiration last until they are revoked
+    expires_at DATETIME
+);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE share_link;
+-- +goose StatementEnd
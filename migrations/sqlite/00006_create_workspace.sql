@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workspace (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE workspace_member (
+    workspace INTEGER NOT NULL REFERENCES workspace(id) ON DELETE CASCADE,
+    account INTEGER NOT NULL REFERENCES account(id) ON DELETE CASCADE,
+    role TEXT NOT NULL CHECK (role IN ('admin', 'member')),
+
+    PRIMARY KEY (workspace, account)
+);
+
+-- everything created before workspaces existed goes to a default workspace,
+-- administered by the existing accounts
+INSERT INTO workspace (name) VALUES ('Default');
+
+INSERT INTO workspace_member (workspace, account, role)
+SELECT workspace.id, account.id, 'admin'
+FROM workspace
+CROSS JOIN account;
+
+-- sqlite can't add a column referencing another table unless its default is
+-- NULL, workspaces are never deleted and are checked by the server instead
+ALTER TABLE presentation
+ADD COLUMN workspace INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX presentation_workspace ON presentation(workspace);
+
+ALTER TABLE api_key
+ADD COLUMN workspace INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE api_key
+DROP COLUMN workspace;
+
+DROP INDEX presentation_workspace;
+
+ALTER TABLE presentation
+DROP COLUMN workspace;
+
+DROP TABLE workspace_member;
+DROP TABLE workspace;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- entries don't reference the presentation so they outlive it
+CREATE TABLE audit_entry (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    presentation INTEGER NOT NULL,
+    section INTEGER,
+    actor INTEGER REFERENCES account(id) ON DELETE SET NULL,
+    action TEXT NOT NULL,
+    diff TEXT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_entry_presentation ON audit_entry(presentation, id);
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_entry;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE presentation ADD COLUMN deleted_at DATETIME;
+ALTER TABLE section ADD COLUMN deleted_at DATETIME;
+
+CREATE INDEX presentation_deleted_at ON presentation(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX section_deleted_at ON section(deleted_at) WHERE deleted_at IS NOT NULL;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX section_deleted_at;
+DROP INDEX presentation_deleted_at;
+
+ALTER TABLE section DROP COLUMN deleted_at;
+ALTER TABLE presentation DROP COLUMN deleted_at;
+-- +goose StatementEnd
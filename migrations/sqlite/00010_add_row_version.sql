@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE presentation ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE section ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE section DROP COLUMN version;
+ALTER TABLE presentation DROP COLUMN version;
+-- +goose StatementEnd
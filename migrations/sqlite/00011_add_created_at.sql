@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- sqlite can't add a column with a non constant default, rows are given their
+-- creation time by the queries that insert them instead
+ALTER TABLE presentation ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+ALTER TABLE section ADD COLUMN created_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+
+UPDATE presentation SET created_at = CURRENT_TIMESTAMP;
+UPDATE section SET created_at = CURRENT_TIMESTAMP;
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE section DROP COLUMN created_at;
+ALTER TABLE presentation DROP COLUMN created_at;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- rows that existed before created_at was added got CURRENT_TIMESTAMP, which
+-- has no offset unlike the times written by the store, they have to match to
+-- compare as text when paginating with a cursor
+UPDATE presentation SET created_at = created_at || '+00:00' WHERE created_at NOT LIKE '%+%';
+UPDATE section SET created_at = created_at || '+00:00' WHERE created_at NOT LIKE '%+%';
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+SELECT 1;
+-- +goose StatementEnd
@@ -0,0 +1,180 @@
+// Package client is a Go client for the presentation timer API. It covers the
+// presentation and section endpoints and the run websocket.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorkspaceHeader selects the workspace requests act on.
+const WorkspaceHeader = "X-Workspace"
+
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	token      string
+	workspace  *int64
+	// reconnect is the longest wait between attempts to reconnect a run.
+	reconnect time.Duration
+}
+
+// WithToken authenticates requests with a session token, an API key or a
+// share link.
+func WithToken(token string) func(*Client) {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithWorkspace makes requests act on the given workspace instead of the
+// default one of the account.
+func WithWorkspace(ID int64) func(*Client) {
+	return func(c *Client) {
+		c.workspace = &ID
+	}
+}
+
+func WithHTTPClient(httpClient *http.Client) func(*Client) {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithMaxReconnectDelay sets the longest wait between attempts to reconnect a
+// run, the wait doubles on every failed attempt up to it.
+func WithMaxReconnectDelay(d time.Duration) func(*Client) {
+	return func(c *Client) {
+		c.reconnect = d.Abs()
+	}
+}
+
+// New returns a client for the API served at baseURL, as in
+// http://localhost:8080.
+func New(baseURL string, opts ...func(*Client)) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, fmt.Errorf("base URL %q must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    parsed,
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		reconnect:  30 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// IfMatch makes a change fail with ErrPreconditionFailed when the resource is
+// no longer at version.
+func IfMatch(version int32) func(*http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set("If-Match", strconv.Quote(strconv.FormatInt(int64(version), 10)))
+	}
+}
+
+// do sends a request to path and decodes the response body into out, when out
+// is not nil. Responses other than 2xx are returned as *Error.
+func (c *Client) do(
+	ctx context.Context,
+	method string,
+	path string,
+	query url.Values,
+	in any,
+	out any,
+	opts ...func(*http.Request),
+) (*http.Response, error) {
+	var body io.Reader
+	if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+
+		body = bytes.NewReader(encoded)
+	}
+
+	endpoint := c.baseURL.JoinPath(path)
+	endpoint.RawQuery = query.Encode()
+
+	r, err := http.NewRequestWithContext(ctx, method, endpoint.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if in != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	r.Header.Set("Accept", "application/json")
+	c.authenticate(r.Header)
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	res, err := c.httpClient.Do(r)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res, newError(res)
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(res.Body).Decode(out); err != nil {
+			return res, fmt.Errorf("decode response of %s %s: %w", method, path, err)
+		}
+	}
+
+	return res, nil
+}
+
+func (c *Client) authenticate(header http.Header) {
+	if c.token != "" {
+		header.Set("Authorization", "Bearer "+c.token)
+	}
+	if c.workspace != nil {
+		header.Set(WorkspaceHeader, strconv.FormatInt(*c.workspace, 10))
+	}
+}
+
+// etagVersion returns the version of the resource in the ETag of res, or 0
+// when it has none.
+func etagVersion(res *http.Response) int32 {
+	if res == nil {
+		return 0
+	}
+
+	etag, err := strconv.Unquote(res.Header.Get("ETag"))
+	if err != nil {
+		return 0
+	}
+
+	version, err := strconv.ParseInt(etag, 10, 32)
+	if err != nil {
+		return 0
+	}
+
+	return int32(version)
+}
+
+func pathID(ID int64) string {
+	return strconv.FormatInt(ID, 10)
+}
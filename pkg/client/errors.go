@@ -0,0 +1,78 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrNotFound           = errors.New("not found")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrUnprocessable      = errors.New("unprocessable content")
+)
+
+var statusErrors = map[int]error{
+	http.StatusBadRequest:          ErrBadRequest,
+	http.StatusUnauthorized:        ErrUnauthorized,
+	http.StatusForbidden:           ErrForbidden,
+	http.StatusNotFound:            ErrNotFound,
+	http.StatusPreconditionFailed:  ErrPreconditionFailed,
+	http.StatusUnprocessableEntity: ErrUnprocessable,
+}
+
+// Error is a response of the API other than 2xx. It matches the Err* values
+// of its status code with errors.Is.
+type Error struct {
+	StatusCode int `json:"-"`
+	// Message is the error returned by the API
+	Message string `json:"error"`
+	// Messages are the validation errors by field, only set for
+	// ErrUnprocessable
+	Messages map[string][]string `json:"messages"`
+}
+
+func newError(res *http.Response) *Error {
+	e := &Error{StatusCode: res.StatusCode}
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil || json.Unmarshal(body, e) != nil || e.Message == "" {
+		// not every error has a JSON body, as 404s
+		e.Message = strings.TrimSpace(string(body))
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(res.StatusCode)
+	}
+
+	return e
+}
+
+func (e *Error) Error() string {
+	if len(e.Messages) == 0 {
+		return fmt.Sprintf("%d: %s", e.StatusCode, e.Message)
+	}
+
+	fields := make([]string, 0, len(e.Messages))
+	for field := range e.Messages {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	details := make([]string, 0, len(fields))
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s: %s", field, strings.Join(e.Messages[field], ", ")))
+	}
+
+	return fmt.Sprintf("%d: %s (%s)", e.StatusCode, e.Message, strings.Join(details, "; "))
+}
+
+func (e *Error) Is(target error) bool {
+	return statusErrors[e.StatusCode] == target
+}
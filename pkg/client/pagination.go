@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type PageInfo struct {
+	TotalPages int64 `json:"total_pages"`
+	TotalItems int64 `json:"total_items"`
+	// NextCursor and PrevCursor are nil when there is no page after or before
+	NextCursor *string `json:"next_cursor"`
+	PrevCursor *string `json:"prev_cursor"`
+}
+
+type Page[T any] struct {
+	Items    []T      `json:"data"`
+	PageInfo PageInfo `json:"page_info"`
+}
+
+// ListOptions narrows down and sorts a list, the zero value of every field
+// leaves it out of the request.
+type ListOptions struct {
+	// Page starts from 1, it can't be combined with Cursor
+	Page     int32
+	PageSize int32
+	// SortBy holds up to 3 comma separated fields, prefixed with - to sort in
+	// descending order
+	SortBy string
+	// Cursor is the NextCursor or PrevCursor of a previous page, SortBy must
+	// not change while following it
+	Cursor       string
+	NameContains string
+	MinDuration  time.Duration
+	MaxDuration  time.Duration
+	CreatedAfter time.Time
+	Search       string
+}
+
+func (o ListOptions) query() url.Values {
+	query := url.Values{}
+
+	if o.Page != 0 {
+		query.Set("page", strconv.FormatInt(int64(o.Page), 10))
+	}
+	if o.PageSize != 0 {
+		query.Set("page_size", strconv.FormatInt(int64(o.PageSize), 10))
+	}
+	if o.SortBy != "" {
+		query.Set("sort_by", o.SortBy)
+	}
+	if o.Cursor != "" {
+		query.Set("cursor", o.Cursor)
+	}
+	if o.NameContains != "" {
+		query.Set("name_contains", o.NameContains)
+	}
+	if o.MinDuration != 0 {
+		query.Set("min_duration", o.MinDuration.String())
+	}
+	if o.MaxDuration != 0 {
+		query.Set("max_duration", o.MaxDuration.String())
+	}
+	if !o.CreatedAfter.IsZero() {
+		query.Set("created_after", o.CreatedAfter.Format(time.RFC3339))
+	}
+	if o.Search != "" {
+		query.Set("search", o.Search)
+	}
+
+	return query
+}
+
+// paginate yields every item of the pages returned by list, starting from the
+// page of opts and following NextCursor until the last page. Iteration stops
+// at the first error, which is yielded along with the zero value of T.
+func paginate[T any](
+	ctx context.Context,
+	opts ListOptions,
+	list func(context.Context, ListOptions) (Page[T], error),
+) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			page, err := list(ctx, opts)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if page.PageInfo.NextCursor == nil {
+				return
+			}
+			// pages after the first one are requested by cursor
+			opts.Page, opts.Cursor = 0, *page.PageInfo.NextCursor
+		}
+	}
+}
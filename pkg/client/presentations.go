@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type Presentation struct {
+	ID        int64      `json:"id"`
+	Name      string     `json:"name"`
+	Owner     *int64     `json:"owner"`
+	Workspace int64      `json:"workspace"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	Version   int32      `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	// Duration is the sum of the durations of the sections, it is nil when the
+	// server doesn't return it, which it only does in lists
+	Duration *time.Duration `json:"duration,omitempty"`
+}
+
+// PresentationWithSections is a presentation along with its sections in order.
+type PresentationWithSections struct {
+	Presentation
+	Sections []Section          `json:"sections"`
+	Totals   PresentationTotals `json:"totals"`
+}
+
+type PresentationTotals struct {
+	Sections int           `json:"sections"`
+	Duration time.Duration `json:"duration"`
+}
+
+type PresentationInput struct {
+	Name string `json:"name"`
+}
+
+// PresentationPatch changes the fields that are not nil.
+type PresentationPatch struct {
+	Name *string `json:"name,omitempty"`
+}
+
+// PresentationListOptions are the ListOptions of presentations.
+type PresentationListOptions struct {
+	ListOptions
+	// SharedWithMe only lists the presentations granted to the account by
+	// others
+	SharedWithMe bool
+}
+
+func (c *Client) ListPresentations(ctx context.Context, opts PresentationListOptions) (Page[Presentation], error) {
+	query := opts.query()
+	if opts.SharedWithMe {
+		query.Set("shared_with_me", strconv.FormatBool(true))
+	}
+
+	var page Page[Presentation]
+	_, err := c.do(ctx, http.MethodGet, "/presentations", query, nil, &page)
+	return page, err
+}
+
+// Presentations iterates over every presentation matching opts, requesting
+// pages as they are needed.
+func (c *Client) Presentations(ctx context.Context, opts PresentationListOptions) iter.Seq2[Presentation, error] {
+	return paginate(ctx, opts.ListOptions, func(ctx context.Context, listOpts ListOptions) (Page[Presentation], error) {
+		opts.ListOptions = listOpts
+		return c.ListPresentations(ctx, opts)
+	})
+}
+
+func (c *Client) GetPresentation(ctx context.Context, ID int64) (Presentation, error) {
+	var presentation Presentation
+	_, err := c.do(ctx, http.MethodGet, "/presentations/"+pathID(ID), nil, nil, &presentation)
+	return presentation, err
+}
+
+// GetPresentationWithSections returns a presentation along with its ordered
+// sections and their totals in a single request.
+func (c *Client) GetPresentationWithSections(ctx context.Context, ID int64) (PresentationWithSections, error) {
+	query := url.Values{"include": {"sections"}}
+
+	var presentation PresentationWithSections
+	if _, err := c.do(ctx, http.MethodGet, "/presentations/"+pathID(ID), query, nil, &presentation); err != nil {
+		return PresentationWithSections{}, err
+	}
+
+	duration := presentation.Totals.Duration
+	presentation.Duration = &duration
+
+	return presentation, nil
+}
+
+func (c *Client) CreatePresentation(ctx context.Context, input PresentationInput) (Presentation, error) {
+	var presentation Presentation
+	_, err := c.do(ctx, http.MethodPost, "/presentations", nil, input, &presentation)
+	return presentation, err
+}
+
+// UpdatePresentation replaces a presentation, returning its new version.
+func (c *Client) UpdatePresentation(
+	ctx context.Context,
+	ID int64,
+	input PresentationInput,
+	opts ...func(*http.Request),
+) (int32, error) {
+	res, err := c.do(ctx, http.MethodPut, "/presentations/"+pathID(ID), nil, input, nil, opts...)
+	return etagVersion(res), err
+}
+
+// PatchPresentation changes some fields of a presentation, returning its new
+// version.
+func (c *Client) PatchPresentation(
+	ctx context.Context,
+	ID int64,
+	patch PresentationPatch,
+	opts ...func(*http.Request),
+) (int32, error) {
+	res, err := c.do(ctx, http.MethodPatch, "/presentations/"+pathID(ID), nil, patch, nil, opts...)
+	return etagVersion(res), err
+}
+
+// DeletePresentation moves a presentation to the trash.
+func (c *Client) DeletePresentation(ctx context.Context, ID int64, opts ...func(*http.Request)) error {
+	_, err := c.do(ctx, http.MethodDelete, "/presentations/"+pathID(ID), nil, nil, nil, opts...)
+	return err
+}
+
+// RestorePresentation takes a presentation out of the trash.
+func (c *Client) RestorePresentation(ctx context.Context, ID int64) (Presentation, error) {
+	var presentation Presentation
+	_, err := c.do(ctx, http.MethodPost, "/presentations/"+pathID(ID)+"/restore", nil, nil, &presentation)
+	return presentation, err
+}
+
+// Trash holds the deleted presentations and sections.
+type Trash struct {
+	Presentations []Presentation `json:"presentations"`
+	Sections      []Section      `json:"sections"`
+}
+
+func (c *Client) ListTrash(ctx context.Context) (Trash, error) {
+	var trash Trash
+	_, err := c.do(ctx, http.MethodGet, "/trash", nil, nil, &trash)
+	return trash, err
+}
@@ -0,0 +1,432 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// RunProtocol is the version of the run websocket protocol spoken by Run.
+const RunProtocol = "presentation-timer.v1"
+
+const runProtocolVersion = 1
+
+// Types of the run messages.
+const (
+	runMessageStatus = "status"
+	runMessageStart  = "start"
+	runMessagePause  = "pause"
+	runMessageResume = "resume"
+	runMessageStep   = "step"
+	runMessageExtend = "extend"
+	runMessageState  = "state"
+	runMessageError  = "error"
+)
+
+// RunStatesBuffer is how many states Run.States holds before older ones are
+// dropped.
+const RunStatesBuffer = 16
+
+var (
+	// ErrRunClosed is returned by requests to a run that was closed.
+	ErrRunClosed = errors.New("run is closed")
+	// ErrRunDisconnected is returned by requests that were waiting for a
+	// reply when the connection was lost, they may or may not have been
+	// applied.
+	ErrRunDisconnected = errors.New("run connection was lost")
+)
+
+// RunState is where a run is at.
+type RunState struct {
+	// State is running or stopped
+	State string  `json:"state"`
+	Step  Section `json:"step"`
+	// MsLeft is how many milliseconds are left in the current step
+	MsLeft int64 `json:"ms_left"`
+}
+
+// RunError is the reply to a run request that could not be applied.
+type RunError struct {
+	RequestID string
+	Message   string
+}
+
+func (e *RunError) Error() string {
+	return e.Message
+}
+
+type runEnvelope struct {
+	Type      string          `json:"type"`
+	Version   int             `json:"version"`
+	RequestID string          `json:"request_id,omitempty"`
+	Payload   json.RawMessage `json:"payload,omitempty"`
+}
+
+// Run is a connection to the run of a presentation. States are delivered on
+// States as they change and the connection is reestablished whenever it is
+// lost, until the run is closed.
+type Run struct {
+	client         *Client
+	presentationID int64
+	states         chan RunState
+	ctx            context.Context
+	cancel         context.CancelFunc
+	done           chan struct{}
+
+	// mu guards the fields below
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	pending map[string]chan runEnvelope
+	nextID  int64
+	err     error
+
+	// writeMu serializes writes to conn
+	writeMu sync.Mutex
+}
+
+// Run connects to the run of a presentation. The connection is kept until ctx
+// is done or the run is closed.
+func (c *Client) Run(ctx context.Context, presentationID int64) (*Run, error) {
+	conn, err := c.dialRun(ctx, presentationID)
+	if err != nil {
+		return nil, err
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	run := &Run{
+		client:         c,
+		presentationID: presentationID,
+		states:         make(chan RunState, RunStatesBuffer),
+		ctx:            runCtx,
+		cancel:         cancel,
+		done:           make(chan struct{}),
+		conn:           conn,
+		pending:        make(map[string]chan runEnvelope),
+	}
+	go run.loop()
+
+	return run, nil
+}
+
+func (c *Client) dialRun(ctx context.Context, presentationID int64) (*websocket.Conn, error) {
+	endpoint := c.baseURL.JoinPath("/run", pathID(presentationID))
+	switch endpoint.Scheme {
+	case "https":
+		endpoint.Scheme = "wss"
+	default:
+		endpoint.Scheme = "ws"
+	}
+
+	header := http.Header{}
+	c.authenticate(header)
+
+	dialer := websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: 10 * time.Second,
+		Subprotocols:     []string{RunProtocol},
+	}
+	conn, res, err := dialer.DialContext(ctx, endpoint.String(), header)
+	if err != nil {
+		if res != nil && res.StatusCode != http.StatusSwitchingProtocols {
+			defer res.Body.Close()
+			return nil, newError(res)
+		}
+
+		return nil, err
+	}
+
+	if conn.Subprotocol() != RunProtocol {
+		conn.Close()
+		return nil, fmt.Errorf("server does not support the %s protocol", RunProtocol)
+	}
+
+	return conn, nil
+}
+
+// States delivers the state of the run every time it changes. When the states
+// are not read fast enough older ones are dropped. It is closed once the run
+// is closed.
+func (r *Run) States() <-chan RunState {
+	return r.states
+}
+
+// Err returns why the run stopped, nil while it is running or when it was
+// closed.
+func (r *Run) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+// Close disconnects from the run, it doesn't stop it for other connections.
+func (r *Run) Close() error {
+	r.cancel()
+	<-r.done
+
+	return nil
+}
+
+// Status returns the current state of the run, which is also delivered on
+// States.
+func (r *Run) Status(ctx context.Context) (RunState, error) {
+	reply, err := r.request(ctx, runMessageStatus, nil)
+	if err != nil {
+		return RunState{}, err
+	}
+
+	var state RunState
+	if err := json.Unmarshal(reply.Payload, &state); err != nil {
+		return RunState{}, err
+	}
+
+	return state, nil
+}
+
+// Start starts the run from its first section.
+func (r *Run) Start(ctx context.Context) error {
+	_, err := r.request(ctx, runMessageStart, nil)
+	return err
+}
+
+func (r *Run) Pause(ctx context.Context) error {
+	_, err := r.request(ctx, runMessagePause, nil)
+	return err
+}
+
+func (r *Run) Resume(ctx context.Context) error {
+	_, err := r.request(ctx, runMessageResume, nil)
+	return err
+}
+
+// Step jumps to the section at index step, counting from 0.
+func (r *Run) Step(ctx context.Context, step int32) error {
+	_, err := r.request(ctx, runMessageStep, map[string]int32{"step": step})
+	return err
+}
+
+// Extend adds d to the time left of the current section, the run must have
+// been started.
+func (r *Run) Extend(ctx context.Context, d time.Duration) error {
+	_, err := r.request(ctx, runMessageExtend, map[string]int64{"ms": d.Milliseconds()})
+	return err
+}
+
+// request sends a message and waits for its reply. Replies of type error are
+// returned as *RunError.
+func (r *Run) request(ctx context.Context, messageType string, payload any) (runEnvelope, error) {
+	envelope := runEnvelope{Type: messageType, Version: runProtocolVersion}
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return runEnvelope{}, err
+		}
+
+		envelope.Payload = encoded
+	}
+
+	r.mu.Lock()
+	if r.ctx.Err() != nil {
+		r.mu.Unlock()
+		return runEnvelope{}, ErrRunClosed
+	}
+	if r.conn == nil {
+		r.mu.Unlock()
+		return runEnvelope{}, ErrRunDisconnected
+	}
+	r.nextID++
+	envelope.RequestID = strconv.FormatInt(r.nextID, 10)
+	reply := make(chan runEnvelope, 1)
+	r.pending[envelope.RequestID] = reply
+	conn := r.conn
+	r.mu.Unlock()
+
+	defer func() {
+		r.mu.Lock()
+		delete(r.pending, envelope.RequestID)
+		r.mu.Unlock()
+	}()
+
+	if err := r.write(conn, envelope); err != nil {
+		return runEnvelope{}, err
+	}
+
+	select {
+	case <-ctx.Done():
+		return runEnvelope{}, ctx.Err()
+	case <-r.ctx.Done():
+		return runEnvelope{}, ErrRunClosed
+	case message, ok := <-reply:
+		if !ok {
+			return runEnvelope{}, ErrRunDisconnected
+		}
+		if message.Type == runMessageError {
+			var body struct {
+				Error string `json:"error"`
+			}
+			json.Unmarshal(message.Payload, &body)
+
+			return message, &RunError{RequestID: message.RequestID, Message: body.Error}
+		}
+
+		return message, nil
+	}
+}
+
+func (r *Run) write(conn *websocket.Conn, envelope runEnvelope) error {
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	r.writeMu.Lock()
+	defer r.writeMu.Unlock()
+
+	return conn.WriteMessage(websocket.TextMessage, encoded)
+}
+
+// loop reads the messages of the connection, reconnecting when it is lost,
+// until the run is closed.
+func (r *Run) loop() {
+	defer close(r.done)
+	defer close(r.states)
+
+	stop := context.AfterFunc(r.ctx, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		if r.conn != nil {
+			r.conn.Close()
+		}
+	})
+	defer stop()
+
+	for {
+		r.mu.Lock()
+		conn := r.conn
+		r.mu.Unlock()
+
+		r.read(conn)
+		r.disconnect()
+
+		conn, err := r.reconnect()
+		if err != nil {
+			if r.ctx.Err() == nil {
+				r.mu.Lock()
+				r.err = err
+				r.mu.Unlock()
+				r.cancel()
+			}
+			return
+		}
+
+		r.mu.Lock()
+		r.conn = conn
+		r.mu.Unlock()
+		if r.ctx.Err() != nil {
+			conn.Close()
+			return
+		}
+
+		// states may have been missed while disconnected
+		go r.Status(r.ctx)
+	}
+}
+
+// read dispatches the messages of conn until it fails.
+func (r *Run) read(conn *websocket.Conn) {
+	for {
+		_, p, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		var message runEnvelope
+		if err := json.Unmarshal(p, &message); err != nil {
+			continue
+		}
+
+		if message.Type == runMessageState {
+			var state RunState
+			if err := json.Unmarshal(message.Payload, &state); err == nil {
+				r.deliver(state)
+			}
+		}
+
+		if message.RequestID != "" {
+			r.mu.Lock()
+			reply, ok := r.pending[message.RequestID]
+			r.mu.Unlock()
+
+			if ok {
+				reply <- message
+			}
+		}
+	}
+}
+
+// deliver sends state on the states channel, dropping the oldest state when
+// it is full.
+func (r *Run) deliver(state RunState) {
+	for {
+		select {
+		case r.states <- state:
+			return
+		default:
+		}
+
+		select {
+		case <-r.states:
+		default:
+		}
+	}
+}
+
+// disconnect fails the requests waiting for a reply from the lost connection.
+func (r *Run) disconnect() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn != nil {
+		r.conn.Close()
+		r.conn = nil
+	}
+	for ID, reply := range r.pending {
+		close(reply)
+		delete(r.pending, ID)
+	}
+}
+
+// reconnect dials the run until it succeeds, waiting longer after every
+// failure. Errors that won't go away by retrying, as the presentation being
+// deleted or the credentials revoked, are returned.
+func (r *Run) reconnect() (*websocket.Conn, error) {
+	delay := 500 * time.Millisecond
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return nil, r.ctx.Err()
+		case <-time.After(delay):
+		}
+
+		conn, err := r.client.dialRun(r.ctx, r.presentationID)
+		if err == nil {
+			return conn, nil
+		}
+
+		var apiErr *Error
+		if errors.As(err, &apiErr) && apiErr.StatusCode < http.StatusInternalServerError {
+			return nil, err
+		}
+
+		delay = min(delay*2, r.client.reconnect)
+	}
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"time"
+)
+
+type Section struct {
+	ID           int64         `json:"id"`
+	Presentation int64         `json:"presentation"`
+	Name         string        `json:"name"`
+	Duration     time.Duration `json:"duration"`
+	Position     int16         `json:"position"`
+	DeletedAt    *time.Time    `json:"deleted_at"`
+	Version      int32         `json:"version"`
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
+type SectionInput struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	// Position defaults to after the last section
+	Position *int16 `json:"position,omitempty"`
+}
+
+// SectionPatch changes the fields that are not nil.
+type SectionPatch struct {
+	Name     *string        `json:"name,omitempty"`
+	Duration *time.Duration `json:"duration,omitempty"`
+	Position *int16         `json:"position,omitempty"`
+}
+
+// SectionMovement is where a section is moved to, only one of its fields can
+// be set. Move is how many positions the section is moved by, MoveBefore and
+// MoveAfter are other sections of the presentation.
+type SectionMovement struct {
+	Move       *int32 `json:"move,omitempty"`
+	MoveBefore *int64 `json:"move_before,omitempty"`
+	MoveAfter  *int64 `json:"move_after,omitempty"`
+}
+
+// Operations of a section batch.
+const (
+	BatchCreate = "create"
+	BatchUpdate = "update"
+	BatchDelete = "delete"
+	BatchMove   = "move"
+)
+
+// BatchOperation is a change of a section batch, which fields are used
+// depends on Op.
+type BatchOperation struct {
+	Op       string         `json:"op"`
+	ID       *int64         `json:"id,omitempty"`
+	Name     *string        `json:"name,omitempty"`
+	Duration *time.Duration `json:"duration,omitempty"`
+	Position *int16         `json:"position,omitempty"`
+	SectionMovement
+}
+
+type BatchResult struct {
+	Op string `json:"op"`
+	ID int64  `json:"id"`
+	// Section is the section after the operation, nil for deletes
+	Section *Section `json:"section"`
+}
+
+func (c *Client) ListSections(ctx context.Context, presentationID int64, opts ListOptions) (Page[Section], error) {
+	var page Page[Section]
+	_, err := c.do(ctx, http.MethodGet, "/presentations/"+pathID(presentationID)+"/sections", opts.query(), nil, &page)
+	return page, err
+}
+
+// Sections iterates over every section of a presentation matching opts,
+// requesting pages as they are needed.
+func (c *Client) Sections(ctx context.Context, presentationID int64, opts ListOptions) iter.Seq2[Section, error] {
+	return paginate(ctx, opts, func(ctx context.Context, opts ListOptions) (Page[Section], error) {
+		return c.ListSections(ctx, presentationID, opts)
+	})
+}
+
+func (c *Client) GetSection(ctx context.Context, ID int64) (Section, error) {
+	var section Section
+	_, err := c.do(ctx, http.MethodGet, "/sections/"+pathID(ID), nil, nil, &section)
+	return section, err
+}
+
+func (c *Client) CreateSection(ctx context.Context, presentationID int64, input SectionInput) (Section, error) {
+	var section Section
+	_, err := c.do(ctx, http.MethodPost, "/presentations/"+pathID(presentationID)+"/sections", nil, input, &section)
+	return section, err
+}
+
+// UpdateSection replaces a section, returning its new version.
+func (c *Client) UpdateSection(
+	ctx context.Context,
+	ID int64,
+	input SectionInput,
+	opts ...func(*http.Request),
+) (int32, error) {
+	res, err := c.do(ctx, http.MethodPut, "/sections/"+pathID(ID), nil, input, nil, opts...)
+	return etagVersion(res), err
+}
+
+// PatchSection changes some fields of a section, returning its new version.
+func (c *Client) PatchSection(
+	ctx context.Context,
+	ID int64,
+	patch SectionPatch,
+	opts ...func(*http.Request),
+) (int32, error) {
+	res, err := c.do(ctx, http.MethodPatch, "/sections/"+pathID(ID), nil, patch, nil, opts...)
+	return etagVersion(res), err
+}
+
+// DeleteSection moves a section to the trash.
+func (c *Client) DeleteSection(ctx context.Context, ID int64, opts ...func(*http.Request)) error {
+	_, err := c.do(ctx, http.MethodDelete, "/sections/"+pathID(ID), nil, nil, nil, opts...)
+	return err
+}
+
+// RestoreSection takes a section out of the trash.
+func (c *Client) RestoreSection(ctx context.Context, ID int64) (Section, error) {
+	var section Section
+	_, err := c.do(ctx, http.MethodPost, "/sections/"+pathID(ID)+"/restore", nil, nil, &section)
+	return section, err
+}
+
+// MoveSection moves a section within its presentation, returning its new
+// version.
+func (c *Client) MoveSection(
+	ctx context.Context,
+	ID int64,
+	movement SectionMovement,
+	opts ...func(*http.Request),
+) (int32, error) {
+	res, err := c.do(ctx, http.MethodPost, "/sections/"+pathID(ID)+"/move", nil, movement, nil, opts...)
+	return etagVersion(res), err
+}
+
+// ReorderSections sets the order of the sections of a presentation, order must
+// hold every one of them exactly once. The sections are returned in their new
+// order.
+func (c *Client) ReorderSections(ctx context.Context, presentationID int64, order []int64) ([]Section, error) {
+	input := struct {
+		Sections []int64 `json:"sections"`
+	}{order}
+
+	var output struct {
+		Data []Section `json:"data"`
+	}
+	_, err := c.do(ctx, http.MethodPut, "/presentations/"+pathID(presentationID)+"/sections/order", nil, input, &output)
+	return output.Data, err
+}
+
+// BatchSections applies operations to the sections of a presentation in a
+// single transaction, either all of them are applied or none is. Validation
+// errors are keyed by the index of the operation, as in operations[0].name.
+func (c *Client) BatchSections(
+	ctx context.Context,
+	presentationID int64,
+	operations []BatchOperation,
+) ([]BatchResult, error) {
+	input := struct {
+		Operations []BatchOperation `json:"operations"`
+	}{operations}
+
+	var output struct {
+		Data []BatchResult `json:"data"`
+	}
+	_, err := c.do(ctx, http.MethodPost, "/presentations/"+pathID(presentationID)+"/sections:batch", nil, input, &output)
+	return output.Data, err
+}
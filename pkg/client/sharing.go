@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Roles that can be granted on a presentation.
+const (
+	RoleViewer   = "viewer"
+	RoleEditor   = "editor"
+	RoleOperator = "operator"
+)
+
+type Grant struct {
+	Account  int64  `json:"account"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+type ShareLink struct {
+	ID           int64      `json:"id"`
+	Presentation int64      `json:"presentation"`
+	CreatedAt    time.Time  `json:"created_at"`
+	ExpiresAt    *time.Time `json:"expires_at"`
+	// Token is only returned when the link is created
+	Token string `json:"token"`
+}
+
+func (c *Client) ListGrants(ctx context.Context, presentationID int64) ([]Grant, error) {
+	var output struct {
+		Data []Grant `json:"data"`
+	}
+	_, err := c.do(ctx, http.MethodGet, "/presentations/"+pathID(presentationID)+"/grants", nil, nil, &output)
+	return output.Data, err
+}
+
+// PutGrant shares a presentation with the account of username, or changes its
+// role when it is already shared with it.
+func (c *Client) PutGrant(ctx context.Context, presentationID int64, username string, role string) error {
+	input := struct {
+		Role string `json:"role"`
+	}{role}
+
+	_, err := c.do(ctx, http.MethodPut, grantPath(presentationID, username), nil, input, nil)
+	return err
+}
+
+func (c *Client) DeleteGrant(ctx context.Context, presentationID int64, username string) error {
+	_, err := c.do(ctx, http.MethodDelete, grantPath(presentationID, username), nil, nil, nil)
+	return err
+}
+
+func grantPath(presentationID int64, username string) string {
+	return "/presentations/" + pathID(presentationID) + "/grants/" + url.PathEscape(username)
+}
+
+func (c *Client) ListShareLinks(ctx context.Context, presentationID int64) ([]ShareLink, error) {
+	var output struct {
+		Data []ShareLink `json:"data"`
+	}
+	_, err := c.do(ctx, http.MethodGet, "/presentations/"+pathID(presentationID)+"/share-links", nil, nil, &output)
+	return output.Data, err
+}
+
+// CreateShareLink creates a link to follow the runs of a presentation, it
+// never expires when expiresAt is nil.
+func (c *Client) CreateShareLink(ctx context.Context, presentationID int64, expiresAt *time.Time) (ShareLink, error) {
+	input := struct {
+		ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	}{expiresAt}
+
+	var link ShareLink
+	_, err := c.do(ctx, http.MethodPost, "/presentations/"+pathID(presentationID)+"/share-links", nil, input, &link)
+	return link, err
+}
+
+func (c *Client) DeleteShareLink(ctx context.Context, presentationID int64, ID int64) error {
+	_, err := c.do(
+		ctx,
+		http.MethodDelete,
+		"/presentations/"+pathID(presentationID)+"/share-links/"+pathID(ID),
+		nil,
+		nil,
+		nil,
+	)
+	return err
+}
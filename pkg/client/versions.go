@@ -0,0 +1,143 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+type Version struct {
+	Version int32  `json:"version"`
+	Name    string `json:"name"`
+	// Actor is the username of the account that made the change, nil when
+	// authentication is disabled
+	Actor     *string   `json:"actor"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type VersionDetail struct {
+	Version
+	Sections []VersionSection `json:"sections"`
+}
+
+// VersionSection is a section as it was when a version was taken.
+type VersionSection struct {
+	ID       int64         `json:"id"`
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Position int16         `json:"position"`
+}
+
+// FieldChange is the value of a field before and after a change.
+type FieldChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+type SectionDiff struct {
+	ID int64 `json:"id"`
+	// Change is one of added, removed or changed
+	Change string          `json:"change"`
+	Before *VersionSection `json:"before"`
+	After  *VersionSection `json:"after"`
+}
+
+type VersionDiff struct {
+	From int32 `json:"from"`
+	To   int32 `json:"to"`
+	// Name is nil when the name of the presentation didn't change
+	Name     *FieldChange  `json:"name"`
+	Sections []SectionDiff `json:"sections"`
+}
+
+type AuditEntry struct {
+	ID      int64   `json:"id"`
+	Section *int64  `json:"section"`
+	Actor   *string `json:"actor"`
+	Action  string  `json:"action"`
+	// Diff maps every changed field to its value before and after the change
+	Diff      map[string]FieldChange `json:"diff"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+// pageQuery is the query of the lists that are only paginated by number.
+func pageQuery(page, pageSize int32) url.Values {
+	return ListOptions{Page: page, PageSize: pageSize}.query()
+}
+
+func (c *Client) ListVersions(
+	ctx context.Context,
+	presentationID int64,
+	page int32,
+	pageSize int32,
+) (Page[Version], error) {
+	var versions Page[Version]
+	_, err := c.do(
+		ctx,
+		http.MethodGet,
+		"/presentations/"+pathID(presentationID)+"/versions",
+		pageQuery(page, pageSize),
+		nil,
+		&versions,
+	)
+	return versions, err
+}
+
+func (c *Client) GetVersion(ctx context.Context, presentationID int64, version int32) (VersionDetail, error) {
+	var detail VersionDetail
+	_, err := c.do(
+		ctx,
+		http.MethodGet,
+		"/presentations/"+pathID(presentationID)+"/versions/"+strconv.FormatInt(int64(version), 10),
+		nil,
+		nil,
+		&detail,
+	)
+	return detail, err
+}
+
+func (c *Client) DiffVersions(ctx context.Context, presentationID int64, from, to int32) (VersionDiff, error) {
+	query := url.Values{}
+	query.Set("from", strconv.FormatInt(int64(from), 10))
+	query.Set("to", strconv.FormatInt(int64(to), 10))
+
+	var diff VersionDiff
+	_, err := c.do(ctx, http.MethodGet, "/presentations/"+pathID(presentationID)+"/versions/diff", query, nil, &diff)
+	return diff, err
+}
+
+// RestoreVersion brings a presentation back to one of its versions, which is
+// recorded as a new version that is returned.
+func (c *Client) RestoreVersion(ctx context.Context, presentationID int64, version int32) (Version, error) {
+	var restored Version
+	_, err := c.do(
+		ctx,
+		http.MethodPost,
+		"/presentations/"+pathID(presentationID)+"/versions/"+strconv.FormatInt(int64(version), 10)+"/restore",
+		nil,
+		nil,
+		&restored,
+	)
+	return restored, err
+}
+
+func (c *Client) ListAuditEntries(
+	ctx context.Context,
+	presentationID int64,
+	page int32,
+	pageSize int32,
+) (Page[AuditEntry], error) {
+	var entries Page[AuditEntry]
+	_, err := c.do(
+		ctx,
+		http.MethodGet,
+		"/presentations/"+pathID(presentationID)+"/audit",
+		pageQuery(page, pageSize),
+		nil,
+		&entries,
+	)
+	return entries, err
+}
@@ -0,0 +1,122 @@
+// Helpers shared by every page to talk to the API of the server serving them.
+
+const TOKEN_KEY = "presentation-timer.token";
+
+export const SECOND = 1e9;
+
+export class APIError extends Error {
+  constructor(status, body) {
+    const details = Object.entries(body?.messages ?? {})
+      .map(([field, messages]) => `${field}: ${messages.join(", ")}`)
+      .join("; ");
+
+    super(details ? `${body.error} (${details})` : body?.error ?? `request failed with ${status}`);
+    this.status = status;
+  }
+}
+
+export function token() {
+  return localStorage.getItem(TOKEN_KEY);
+}
+
+export function setToken(value) {
+  if (value) {
+    localStorage.setItem(TOKEN_KEY, value);
+  } else {
+    localStorage.removeItem(TOKEN_KEY);
+  }
+}
+
+// request sends body as JSON and returns the decoded response, null when it
+// has none. Unauthenticated requests are sent to the login page.
+export async function request(method, path, body) {
+  const headers = {};
+  if (token()) {
+    headers["Authorization"] = `Bearer ${token()}`;
+  }
+  if (body !== undefined) {
+    headers["Content-Type"] = "application/json";
+  }
+
+  const res = await fetch(path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+
+  if (res.status === 401 && !location.pathname.endsWith("/login.html")) {
+    const next = encodeURIComponent(location.pathname + location.search);
+    location.href = `login.html?next=${next}`;
+    throw new APIError(401, { error: "authentication required" });
+  }
+
+  const text = await res.text();
+  let decoded = null;
+  try {
+    decoded = text ? JSON.parse(text) : null;
+  } catch {
+    decoded = { error: text.trim() };
+  }
+
+  if (!res.ok) {
+    throw new APIError(res.status, decoded);
+  }
+
+  return decoded;
+}
+
+// formatDuration writes a duration in nanoseconds as M:SS or H:MM:SS.
+export function formatDuration(ns) {
+  const total = Math.ceil(ns / SECOND);
+  const hours = Math.floor(total / 3600);
+  const minutes = Math.floor(total / 60) % 60;
+  const seconds = String(total % 60).padStart(2, "0");
+
+  if (hours > 0) {
+    return `${hours}:${String(minutes).padStart(2, "0")}:${seconds}`;
+  }
+
+  return `${minutes}:${seconds}`;
+}
+
+// parseDuration reads durations written as seconds (90), as M:SS or H:MM:SS
+// (1:30) or with units (1m30s), returning nanoseconds.
+export function parseDuration(text) {
+  text = text.trim();
+
+  if (/^\d+(:\d{1,2}){0,2}$/.test(text)) {
+    return text
+      .split(":")
+      .reduce((total, part) => total * 60 + Number(part), 0) * SECOND;
+  }
+
+  const units = { h: 3600, m: 60, s: 1 };
+  const match = text.match(/^(\d+h)?(\d+m)?(\d+s)?$/);
+  if (!match || text === "") {
+    throw new Error(`invalid duration "${text}", use 90, 1:30 or 1m30s`);
+  }
+
+  return match
+    .slice(1)
+    .filter(Boolean)
+    .reduce((total, part) => total + Number(part.slice(0, -1)) * units[part.at(-1)], 0) * SECOND;
+}
+
+export function presentationID() {
+  return Number(new URLSearchParams(location.search).get("id"));
+}
+
+// showError writes the message of err in element, or clears it.
+export function showError(element, err) {
+  element.textContent = err ? err.message : "";
+  element.hidden = !err;
+}
+
+export async function logout() {
+  try {
+    await request("DELETE", "/sessions/current");
+  } finally {
+    setToken(null);
+    location.href = "login.html";
+  }
+}
@@ -0,0 +1,43 @@
+import { request, setToken, showError } from "./api.js";
+
+const form = document.getElementById("login");
+const error = document.getElementById("error");
+
+function credentials() {
+  return {
+    username: form.elements.username.value,
+    password: form.elements.password.value,
+  };
+}
+
+async function signIn() {
+  const session = await request("POST", "/sessions", credentials());
+  setToken(session.token);
+
+  const next = new URLSearchParams(location.search).get("next");
+  // only pages of the interface are followed
+  location.href = next?.startsWith("/ui/") ? next : "index.html";
+}
+
+form.addEventListener("submit", async (event) => {
+  event.preventDefault();
+
+  try {
+    await signIn();
+  } catch (err) {
+    showError(error, err);
+  }
+});
+
+document.getElementById("signup").addEventListener("click", async () => {
+  if (!form.reportValidity()) {
+    return;
+  }
+
+  try {
+    await request("POST", "/accounts", credentials());
+    await signIn();
+  } catch (err) {
+    showError(error, err);
+  }
+});
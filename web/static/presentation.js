@@ -0,0 +1,138 @@
+import { formatDuration, parseDuration, presentationID, request, showError } from "./api.js";
+
+const ID = presentationID();
+const list = document.getElementById("sections");
+const error = document.getElementById("error");
+const rename = document.getElementById("rename");
+const create = document.getElementById("create");
+
+document.getElementById("stage").href = `stage.html?id=${ID}`;
+
+// run reports the errors of action and reloads the presentation once it is
+// done, so the page always shows what the server holds.
+async function run(action) {
+  try {
+    await action();
+    showError(error, null);
+  } catch (err) {
+    showError(error, err);
+  }
+
+  await load();
+}
+
+function item(section) {
+  const li = document.createElement("li");
+  li.draggable = true;
+  li.dataset.id = section.id;
+
+  const handle = document.createElement("span");
+  handle.className = "handle";
+  handle.textContent = "⠿";
+
+  const name = document.createElement("input");
+  name.name = "name";
+  name.value = section.name;
+
+  const duration = document.createElement("input");
+  duration.className = "duration";
+  duration.value = formatDuration(section.duration);
+
+  const save = document.createElement("button");
+  save.textContent = "Save";
+  save.addEventListener("click", () =>
+    run(() =>
+      request("PATCH", `/sections/${section.id}`, {
+        name: name.value,
+        duration: parseDuration(duration.value),
+      }),
+    ),
+  );
+
+  const remove = document.createElement("button");
+  remove.className = "danger";
+  remove.textContent = "Delete";
+  remove.addEventListener("click", () => run(() => request("DELETE", `/sections/${section.id}`)));
+
+  // inputs can't be dragged from, only the handle starts dragging
+  li.addEventListener("mousedown", (event) => {
+    li.draggable = event.target === handle;
+  });
+
+  li.append(handle, name, duration, save, remove);
+  return li;
+}
+
+// the dragged item is moved as it goes over others and the new order is sent
+// when it is dropped
+let dragged = null;
+let before = "";
+
+function order() {
+  return [...list.children].map((li) => Number(li.dataset.id));
+}
+
+list.addEventListener("dragstart", (event) => {
+  before = order().join();
+  dragged = event.target.closest("li");
+  dragged.classList.add("dragging");
+  event.dataTransfer.effectAllowed = "move";
+});
+
+list.addEventListener("dragover", (event) => {
+  event.preventDefault();
+
+  const over = event.target.closest("li");
+  if (!dragged || !over || over === dragged) {
+    return;
+  }
+
+  const { top, height } = over.getBoundingClientRect();
+  over.parentNode.insertBefore(dragged, event.clientY < top + height / 2 ? over : over.nextSibling);
+});
+
+list.addEventListener("dragend", () => {
+  if (!dragged) {
+    return;
+  }
+
+  dragged.classList.remove("dragging");
+  dragged = null;
+
+  const sections = order();
+  if (sections.join() !== before) {
+    run(() => request("PUT", `/presentations/${ID}/sections/order`, { sections }));
+  }
+});
+
+rename.addEventListener("submit", (event) => {
+  event.preventDefault();
+  run(() => request("PATCH", `/presentations/${ID}`, { name: rename.elements.name.value }));
+});
+
+create.addEventListener("submit", (event) => {
+  event.preventDefault();
+  run(async () => {
+    await request("POST", `/presentations/${ID}/sections`, {
+      name: create.elements.name.value,
+      duration: parseDuration(create.elements.duration.value),
+    });
+    create.reset();
+  });
+});
+
+async function load() {
+  try {
+    const presentation = await request("GET", `/presentations/${ID}?include=sections`);
+
+    document.title = `${presentation.name} · Presentation timer`;
+    rename.elements.name.value = presentation.name;
+    document.getElementById("totals").textContent =
+      `${presentation.totals.sections} sections, ${formatDuration(presentation.totals.duration)} in total`;
+    list.replaceChildren(...presentation.sections.map(item));
+  } catch (err) {
+    showError(error, err);
+  }
+}
+
+load();
@@ -0,0 +1,91 @@
+import { formatDuration, logout, request, showError, token } from "./api.js";
+
+const rows = document.getElementById("presentations");
+const error = document.getElementById("error");
+const more = document.getElementById("more");
+const form = document.getElementById("create");
+
+let cursor = null;
+
+function row(presentation) {
+  const tr = document.createElement("tr");
+
+  const name = document.createElement("a");
+  name.href = `presentation.html?id=${presentation.id}`;
+  name.textContent = presentation.name;
+
+  const stage = document.createElement("a");
+  stage.href = `stage.html?id=${presentation.id}`;
+  stage.textContent = "Stage";
+
+  const remove = document.createElement("button");
+  remove.className = "danger";
+  remove.textContent = "Delete";
+  remove.addEventListener("click", async () => {
+    if (!confirm(`Move "${presentation.name}" to the trash?`)) {
+      return;
+    }
+
+    try {
+      await request("DELETE", `/presentations/${presentation.id}`);
+      tr.remove();
+    } catch (err) {
+      showError(error, err);
+    }
+  });
+
+  const cells = [
+    name,
+    formatDuration(presentation.duration),
+    new Date(presentation.created_at).toLocaleString(),
+  ];
+  for (const content of cells) {
+    const td = document.createElement("td");
+    td.append(content);
+    tr.append(td);
+  }
+
+  const actions = document.createElement("td");
+  actions.append(stage, " ", remove);
+  tr.append(actions);
+
+  return tr;
+}
+
+async function load() {
+  const query = new URLSearchParams({ page_size: "50", sort_by: "-created_at" });
+  if (cursor) {
+    query.set("cursor", cursor);
+  }
+
+  try {
+    const page = await request("GET", `/presentations?${query}`);
+    rows.append(...(page.data ?? []).map(row));
+
+    cursor = page.page_info.next_cursor;
+    more.hidden = !cursor;
+    document.getElementById("empty").hidden = rows.children.length > 0;
+    showError(error, null);
+  } catch (err) {
+    showError(error, err);
+  }
+}
+
+form.addEventListener("submit", async (event) => {
+  event.preventDefault();
+
+  try {
+    const presentation = await request("POST", "/presentations", { name: form.elements.name.value });
+    location.href = `presentation.html?id=${presentation.id}`;
+  } catch (err) {
+    showError(error, err);
+  }
+});
+
+more.addEventListener("click", load);
+
+const signOut = document.getElementById("logout");
+signOut.hidden = !token();
+signOut.addEventListener("click", logout);
+
+load();
@@ -0,0 +1,229 @@
+import { SECOND, formatDuration, presentationID, request, showError, token } from "./api.js";
+
+// PROTOCOL is the version of the run websocket protocol spoken by the stage.
+const PROTOCOL = "presentation-timer.v1";
+const EXTEND_MS = 60 * 1000;
+const MAX_RETRY_MS = 30 * 1000;
+
+const ID = presentationID();
+const elements = Object.fromEntries(
+  ["status", "countdown", "progress", "current", "next", "error", "toggle"].map((id) => [
+    id,
+    document.getElementById(id),
+  ]),
+);
+
+let presentation = null;
+let socket = null;
+let retry = 500;
+let requestID = 0;
+let errorTimeout = null;
+
+// state is the last state of the run, received is when it arrived so the
+// countdown goes on from it and overSince is when the last section ran out
+let state = null;
+let received = 0;
+let overSince = 0;
+
+async function loadPresentation() {
+  presentation = await request("GET", `/presentations/${ID}?include=sections`);
+  document.title = `${presentation.name} · Stage`;
+}
+
+function connect() {
+  const url = new URL(`/run/${ID}`, location.href);
+  url.protocol = location.protocol === "https:" ? "wss:" : "ws:";
+  if (token()) {
+    url.searchParams.set("token", token());
+  }
+
+  socket = new WebSocket(url, PROTOCOL);
+  socket.addEventListener("open", () => {
+    retry = 500;
+    elements.status.textContent = "connected";
+    send("status");
+  });
+  socket.addEventListener("message", (event) => {
+    const message = JSON.parse(event.data);
+    if (message.type === "state") {
+      update(message.payload);
+    } else if (message.type === "error") {
+      notify(message.payload.error);
+    }
+  });
+  socket.addEventListener("close", () => {
+    elements.status.textContent = "disconnected, reconnecting";
+    setTimeout(connect, retry);
+    retry = Math.min(retry * 2, MAX_RETRY_MS);
+  });
+}
+
+function send(type, payload) {
+  if (socket?.readyState !== WebSocket.OPEN) {
+    notify("not connected to the run");
+    return;
+  }
+
+  requestID += 1;
+  socket.send(JSON.stringify({ type, version: 1, request_id: String(requestID), payload }));
+}
+
+function notify(message) {
+  showError(elements.error, new Error(message));
+  clearTimeout(errorTimeout);
+  errorTimeout = setTimeout(() => showError(elements.error, null), 5000);
+}
+
+function sectionIndex(sectionID) {
+  return presentation?.sections.findIndex((section) => section.id === sectionID) ?? -1;
+}
+
+async function update(next) {
+  const last = presentation.sections.length - 1;
+  const finished = next.state !== "running" && next.ms_left === 0 && sectionIndex(next.step.id) === last;
+  if (!finished) {
+    overSince = 0;
+  } else if (state?.state === "running" && !overSince) {
+    overSince = Date.now();
+  }
+
+  state = next;
+  received = Date.now();
+
+  // sections may have changed since the page was loaded
+  if (state.step.id && sectionIndex(state.step.id) === -1) {
+    await loadPresentation();
+  }
+}
+
+// idle reports whether the run has not started or is over.
+function idle() {
+  return state && state.state !== "running" && state.ms_left === 0;
+}
+
+function leftMs() {
+  if (!state) {
+    return 0;
+  }
+
+  const elapsed = state.state === "running" ? Date.now() - received : 0;
+  return Math.max(state.ms_left - elapsed, 0);
+}
+
+function render() {
+  if (!presentation || !state) {
+    return;
+  }
+
+  const index = sectionIndex(state.step.id);
+  const left = leftMs() * 1e6;
+  const duration = state.step.duration;
+
+  let countdown = formatDuration(left);
+  let color = "";
+  if (overSince) {
+    countdown = `+${formatDuration((Date.now() - overSince) * 1e6)}`;
+    color = "over";
+  } else if (state.state !== "running") {
+    color = "stopped";
+  } else if (left <= Math.min(60 * SECOND, duration / 5)) {
+    color = "warning";
+  }
+
+  elements.countdown.textContent = countdown;
+  elements.countdown.className = `countdown ${color}`;
+
+  let progress = 0;
+  if ((!idle() || overSince) && duration > 0 && left <= duration) {
+    progress = 1 - left / duration;
+  }
+  elements.progress.style.width = `${progress * 100}%`;
+  elements.progress.style.background = getComputedStyle(elements.countdown).color;
+
+  const position = index >= 0 ? ` (${index + 1}/${presentation.sections.length})` : "";
+  elements.current.textContent = `${state.step.name ?? ""}${position}`;
+
+  const next = presentation.sections[index + 1];
+  elements.next.textContent = index >= 0 && next ? `Next: ${next.name} · ${formatDuration(next.duration)}` : "";
+
+  if (state.state === "running") {
+    elements.toggle.textContent = "Pause";
+  } else if (idle()) {
+    elements.toggle.textContent = "Start";
+  } else {
+    elements.toggle.textContent = "Resume";
+  }
+}
+
+function toggle() {
+  if (state?.state === "running") {
+    send("pause");
+  } else if (!state || idle()) {
+    start();
+  } else {
+    send("resume");
+  }
+}
+
+function start() {
+  overSince = 0;
+  send("start");
+}
+
+function step(by) {
+  if (!presentation || !state) {
+    return;
+  }
+
+  const index = sectionIndex(state.step.id) + by;
+  if (index >= 0 && index < presentation.sections.length) {
+    send("step", { step: index });
+  }
+}
+
+function fullscreen() {
+  if (document.fullscreenElement) {
+    document.exitFullscreen();
+  } else {
+    document.documentElement.requestFullscreen();
+  }
+}
+
+const actions = {
+  toggle,
+  previous: () => step(-1),
+  "next-section": () => step(1),
+  extend: () => send("extend", { ms: EXTEND_MS }),
+  restart: start,
+  fullscreen,
+};
+for (const [id, action] of Object.entries(actions)) {
+  document.getElementById(id).addEventListener("click", (event) => {
+    event.currentTarget.blur();
+    action();
+  });
+}
+
+const keys = {
+  " ": toggle,
+  ArrowLeft: actions.previous,
+  ArrowRight: actions["next-section"],
+  e: actions.extend,
+  s: start,
+  f: fullscreen,
+};
+document.addEventListener("keydown", (event) => {
+  const action = keys[event.key];
+  if (action && !event.repeat) {
+    event.preventDefault();
+    action();
+  }
+});
+
+try {
+  await loadPresentation();
+  connect();
+  setInterval(render, 100);
+} catch (err) {
+  showError(elements.error, err);
+}
@@ -0,0 +1,21 @@
+// Package web embeds the browser interface so it can be served by the server
+// binary itself.
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed static
+var files embed.FS
+
+// Files are the pages, scripts and styles of the interface.
+func Files() fs.FS {
+	static, err := fs.Sub(files, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	return static
+}